@@ -0,0 +1,89 @@
+// Command watoken membuat dan merotasi keyring PASETO untuk WATOKEN_KEYRING.
+//
+//	PRKEY=... go run ./cmd/watoken rotate -retire 720h > keyring.json
+//	go run ./cmd/watoken rotate -retire 720h < keyring.json > keyring-baru.json
+//
+// rotate membaca keyring dari stdin (atau dari WATOKEN_KEYRING jika stdin kosong). Tanpa keyring,
+// PRKEY dipakai sebagai key lama dengan kid "legacy" sehingga token yang sudah beredar tetap valid
+// sampai masa -retire habis; karena itu migrasi pertama dari PRKEY harus memakai rotate. generate
+// hanya untuk deployment baru yang belum menerbitkan token.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gocroot/helper/watoken"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "generate":
+		err = write(&watoken.Keyring{Keys: []watoken.Key{watoken.NewKey(time.Now())}})
+	case "rotate":
+		err = rotate(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "watoken:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "pemakaian: watoken generate | watoken rotate [-retire 720h] < keyring.json")
+	os.Exit(2)
+}
+
+func rotate(args []string) error {
+	flags := flag.NewFlagSet("rotate", flag.ExitOnError)
+	retire := flags.Duration("retire", 30*24*time.Hour, "lama key lama tetap memverifikasi token (isi dengan umur token terpanjang)")
+	flags.Parse(args)
+
+	ring, err := readKeyring()
+	if err != nil {
+		return err
+	}
+	next := ring.Rotate(time.Now(), *retire)
+	if err := ring.Validate(); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "key aktif baru:", next.ID)
+	return write(ring)
+}
+
+func readKeyring() (*watoken.Keyring, error) {
+	var data []byte
+	if stat, _ := os.Stdin.Stat(); stat != nil && stat.Mode()&os.ModeCharDevice == 0 {
+		var err error
+		if data, err = io.ReadAll(os.Stdin); err != nil {
+			return nil, err
+		}
+	}
+	if len(data) == 0 {
+		data = []byte(os.Getenv("WATOKEN_KEYRING"))
+	}
+	if len(data) > 0 {
+		return watoken.ParseKeyring(data)
+	}
+	key, err := watoken.KeyFromPrivate("legacy", os.Getenv("PRKEY"), time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("keyring kosong dan PRKEY tidak valid: %w", err)
+	}
+	return &watoken.Keyring{Keys: []watoken.Key{key}}, nil
+}
+
+func write(ring *watoken.Keyring) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ring)
+}
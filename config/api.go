@@ -18,10 +18,6 @@ var WAAPIGetToken string = "https://api.wa.my.id/api/signup"
 
 var WAAPIGetDevice string = "https://api.wa.my.id/api/device/"
 
-var PublicKeyWhatsAuth string
-
-var WAAPIToken string
-
 var APIGETPDLMS string = "https://pamongdesa.kemendagri.go.id/webservice/public/user/get-by-phone?number="
 
 var APITOKENPD string = os.Getenv("PDTOKEN")
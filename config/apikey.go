@@ -0,0 +1,7 @@
+package config
+
+// APIKeyRatePerMinute adalah batas default sekaligus batas maksimal request per menit satu API key
+var APIKeyRatePerMinute = envInt("PDFM_APIKEY_RATE_PER_MINUTE", 60)
+
+// APIKeyMaxPerUser membatasi jumlah API key aktif per user
+var APIKeyMaxPerUser = envInt("PDFM_APIKEY_MAX_PER_USER", 10)
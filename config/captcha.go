@@ -0,0 +1,26 @@
+package config
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gocroot/helper/captcha"
+)
+
+// CaptchaVerifier memverifikasi token CAPTCHA dari frontend dengan Cloudflare Turnstile (secret di
+// TURNSTILE_SECRET). Tanpa secret semua token ditolak; PDFM_CAPTCHA=off mematikan verifikasi untuk
+// pengembangan lokal.
+var CaptchaVerifier captcha.Verifier = newCaptchaVerifier()
+
+func newCaptchaVerifier() captcha.Verifier {
+	if os.Getenv("PDFM_CAPTCHA") == "off" {
+		return captcha.Disabled{}
+	}
+	return captcha.Turnstile{Secret: os.Getenv("TURNSTILE_SECRET")}
+}
+
+// ContactRatePerHour adalah jumlah pesan form kontak tanpa login yang boleh dikirim satu IP per jam
+var ContactRatePerHour = envInt("PDFM_CONTACT_RATE_PER_HOUR", 5)
+
+// SpamBlocklist menambah frasa blocklist spam bawaan, dipisah koma di PDFM_SPAM_BLOCKLIST
+var SpamBlocklist = strings.Split(os.Getenv("PDFM_SPAM_BLOCKLIST"), ",")
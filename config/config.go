@@ -1,11 +1,14 @@
 package config
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"os"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gocroot/helper/at"
-	"github.com/gocroot/helper/atdb"
 	"github.com/whatsauth/itmodel"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -18,18 +21,96 @@ var IPPort, Net = at.GetAddress()
 
 var PhoneNumber string = os.Getenv("PHONENUMBER")
 
-var Profile itmodel.Profile
+// currentProfile dipublikasikan secara atomik karena dimuat ulang di latar belakang sementara
+// handler membacanya
+var currentProfile atomic.Pointer[itmodel.Profile]
 
+// Profile mengembalikan salinan profile WhatsAuth yang terakhir dimuat (kosong jika belum)
+func Profile() itmodel.Profile {
+	if p := currentProfile.Load(); p != nil {
+		return *p
+	}
+	return itmodel.Profile{}
+}
+
+// PublicKeyWhatsAuth adalah public key WhatsAuth dari profile untuk memverifikasi token lama
+func PublicKeyWhatsAuth() string {
+	return Profile().PublicKey
+}
+
+// WAAPIToken adalah token API WhatsApp dari profile
+func WAAPIToken() string {
+	return Profile().Token
+}
+
+// SetProfile mengganti profile yang dipakai, mis. di test
+func SetProfile(p itmodel.Profile) {
+	currentProfile.Store(&p)
+}
+
+// ProfileTTL adalah umur profile WhatsAuth di memori sebelum dimuat ulang dari database
+var ProfileTTL = time.Duration(envInt("PDFM_PROFILE_TTL_SECONDS", 300)) * time.Second
+
+// profileRetry adalah jeda sebelum mencoba lagi setelah pemuatan profile gagal
+const profileRetry = 10 * time.Second
+
+var profileState struct {
+	sync.Mutex
+	loadedAt  time.Time
+	attemptAt time.Time
+	loading   bool
+	err       error
+}
+
+// SetEnv memuat profile WhatsAuth dari database secara sinkron
 func SetEnv() {
-	if ErrorMongoconn != nil {
-		log.Println(ErrorMongoconn.Error())
+	err := ErrorMongoconn
+	var profile itmodel.Profile
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err = Mongoconn.Collection("profile").FindOne(ctx, primitive.M{"phonenumber": PhoneNumber}).Decode(&profile)
+		cancel()
 	}
-	Profile, err := atdb.GetOneDoc[itmodel.Profile](Mongoconn, "profile", primitive.M{"phonenumber": PhoneNumber})
+
+	profileState.Lock()
+	defer profileState.Unlock()
+	profileState.loading, profileState.err = false, err
 	if err != nil {
-		log.Println(err)
+		slog.Error("gagal memuat profile WhatsAuth", "phonenumber", PhoneNumber, "error", err)
+		return
+	}
+	SetProfile(profile)
+	profileState.loadedAt = time.Now()
+}
+
+// RefreshEnv dipanggil di setiap request menggantikan SetEnv. Pemuatan pertama berjalan sinkron;
+// setelah itu profile dimuat ulang di latar belakang jika sudah lebih tua dari ProfileTTL, sehingga
+// request tidak menunggu database. Pemuatan yang gagal dicoba lagi paling cepat tiap profileRetry.
+func RefreshEnv() {
+	profileState.Lock()
+	now := time.Now()
+	fresh := !profileState.loadedAt.IsZero() && now.Sub(profileState.loadedAt) < ProfileTTL
+	if fresh || profileState.loading || now.Sub(profileState.attemptAt) < profileRetry {
+		profileState.Unlock()
+		return
+	}
+	profileState.loading, profileState.attemptAt = true, now
+	first := profileState.loadedAt.IsZero()
+	profileState.Unlock()
+
+	if first {
+		SetEnv()
+		return
 	}
-	PublicKeyWhatsAuth = Profile.PublicKey
-	WAAPIToken = Profile.Token
+	go SetEnv()
+}
+
+// ProfileStatus mengembalikan waktu profile terakhir berhasil dimuat (nol jika belum pernah) dan
+// error pemuatan terakhir
+func ProfileStatus() (loadedAt time.Time, err error) {
+	profileState.Lock()
+	defer profileState.Unlock()
+	return profileState.loadedAt, profileState.err
 }
 
 var GHAccessToken string = os.Getenv("GH_ACCESS_TOKEN")
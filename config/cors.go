@@ -1,7 +1,6 @@
 package config
 
 import (
-	"log"
 	"net/http"
 	"strings"
 )
@@ -37,9 +36,6 @@ func SetAccessControlHeaders(w http.ResponseWriter, r *http.Request) bool {
 	origin := r.Header.Get("Origin")
 	normalizedOrigin := normalizeOrigin(origin)
 
-	// Log origin untuk debugging
-	log.Printf("Incoming request from Origin: %s", origin)
-
 	if isAllowedOrigin(normalizedOrigin) {
 		// Tambahkan header Vary untuk cache
 		w.Header().Set("Vary", "Origin")
@@ -47,7 +43,7 @@ func SetAccessControlHeaders(w http.ResponseWriter, r *http.Request) bool {
 		// Tangani preflight request (OPTIONS)
 		if r.Method == http.MethodOptions {
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Login")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Login, X-API-Key")
 			w.Header().Set("Access-Control-Allow-Methods", "POST, GET, DELETE, PUT, OPTIONS")
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Max-Age", "3600")
@@ -59,7 +55,7 @@ func SetAccessControlHeaders(w http.ResponseWriter, r *http.Request) bool {
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Login")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Login, X-API-Key")
 		return false
 	}
 
@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/metrics"
 )
 
 var MongoString string = os.Getenv("MONGOSTRING")
@@ -11,6 +12,7 @@ var MongoString string = os.Getenv("MONGOSTRING")
 var mongoinfo = atdb.DBInfo{
 	DBString: MongoString,
 	DBName:   "pdfm",
+	Monitor:  metrics.MongoMonitor(),
 }
 
 var Mongoconn, ErrorMongoconn = atdb.MongoConnect(mongoinfo)
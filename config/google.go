@@ -0,0 +1,7 @@
+package config
+
+import "os"
+
+// GoogleClientID adalah OAuth client ID (audience ID token) untuk login Google pdfm.
+// Jika kosong, client_id dari koleksi credentials dipakai seperti handler Auth.
+var GoogleClientID = os.Getenv("PDFM_GOOGLE_CLIENT_ID")
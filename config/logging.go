@@ -0,0 +1,11 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/gocroot/helper/logging"
+)
+
+// Logger adalah logger JSON default aplikasi. Level diatur lewat LOG_LEVEL (debug, info, warn, error).
+var Logger *slog.Logger = logging.Setup(os.Getenv("LOG_LEVEL"))
@@ -0,0 +1,7 @@
+package config
+
+import "os"
+
+// MetricsToken melindungi GET /metrics dengan header "Authorization: Bearer <token>". Jika kosong,
+// endpoint terbuka sehingga sebaiknya hanya dijangkau scraper di jaringan internal.
+var MetricsToken = os.Getenv("PDFM_METRICS_TOKEN")
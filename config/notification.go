@@ -0,0 +1,19 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// NotificationPubSub memilih broker notifikasi realtime: "memory" (default, satu instance)
+// atau "mongo" (change stream pada koleksi notifications, untuk banyak instance)
+var NotificationPubSub = os.Getenv("PDFM_PUBSUB")
+
+// SSEHeartbeat adalah jeda komentar ping pada stream SSE agar koneksi tidak diputus proxy
+var SSEHeartbeat = time.Duration(envInt("PDFM_SSE_HEARTBEAT_SECONDS", 25)) * time.Second
+
+// Retry pengiriman notifikasi email/WhatsApp
+var (
+	NotificationMaxAttempts = envInt("PDFM_NOTIF_MAX_ATTEMPTS", 5)
+	NotificationBackoff     = time.Duration(envInt("PDFM_NOTIF_BACKOFF_SECONDS", 60)) * time.Second
+)
@@ -0,0 +1,41 @@
+package config
+
+import "github.com/gocroot/helper/privacy"
+
+// AccountDeletionDays adalah masa tenggang sebelum akun yang diminta dihapus benar-benar dihapus.
+// Selama masa ini user masih bisa login dan membatalkan penghapusan.
+var AccountDeletionDays = envInt("PDFM_ACCOUNT_DELETION_DAYS", 14)
+
+// PersonalData adalah koleksi berisi data personal yang ikut dihapus atau dianonimkan saat akun dihapus.
+// Koleksi users sendiri, file storage dan koleksi riwayat lama ditangani terpisah oleh controller.
+var PersonalData = []privacy.Target{
+	{Collection: "activity_log", Field: "user_id", Key: privacy.ByID, Action: privacy.Delete},
+	{Collection: "notifications", Field: "user_id", Key: privacy.ByID, Action: privacy.Delete},
+	{Collection: "notification_preferences", Field: "user_id", Key: privacy.ByID, Action: privacy.Delete},
+	{Collection: "login_logs", Field: "user_id", Key: privacy.ByID, Action: privacy.Delete},
+	{Collection: "tokens", Field: "email", Key: privacy.ByEmail, Action: privacy.Delete},
+	{Collection: "api_keys", Field: "user_id", Key: privacy.ByID, Action: privacy.Delete},
+	{Collection: "api_key_usage", Field: "user_id", Key: privacy.ByID, Action: privacy.Delete},
+	// Invoice wajib disimpan untuk pembukuan, jadi hanya identitasnya yang diganti
+	{
+		Collection: "invoices", Field: "email", Key: privacy.ByEmail, Action: privacy.Anonymize,
+		Set: map[string]string{"name": privacy.DeletedName, "email": privacy.PseudonymPlaceholder},
+	},
+	// Feedback tetap berguna untuk statistik triage; lampirannya ikut terhapus bersama file milik user
+	{
+		Collection: "feedback", Field: "user_id", Key: privacy.ByHexID, Action: privacy.Anonymize,
+		Set:   map[string]string{"name": privacy.DeletedName, "email": privacy.PseudonymPlaceholder},
+		Unset: []string{"user_id", "attachments"},
+	},
+	// Balasan admin di thread feedback orang lain menyimpan nama penulisnya
+	{
+		Collection: "feedback", Array: "replies", Field: "author_id", Key: privacy.ByID, Action: privacy.Anonymize,
+		Set: map[string]string{"replies.$[" + privacy.ArrayElement + "].author_name": privacy.DeletedName},
+	},
+	// Pesan form kontak yang dikirim dengan email yang sama sebelum/tanpa login
+	{
+		Collection: "feedback", Field: "email", Key: privacy.ByEmail, Action: privacy.Anonymize,
+		Set:   map[string]string{"name": privacy.DeletedName, "email": privacy.PseudonymPlaceholder},
+		Unset: []string{"attachments"},
+	},
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+
+	"github.com/gocroot/helper/retention"
+)
+
+// Lama penyimpanan data per plan (hari), bisa diubah lewat environment
+var (
+	RetentionFreeDays      = envInt("PDFM_RETENTION_FREE_DAYS", 30)
+	RetentionSupporterDays = envInt("PDFM_RETENTION_SUPPORTER_DAYS", 365)
+	LoginLogRetentionDays  = envInt("PDFM_LOGIN_LOG_RETENTION_DAYS", 90)
+	AuditLogRetentionDays  = envInt("PDFM_AUDIT_LOG_RETENTION_DAYS", 365)
+)
+
+// RetentionPolicies adalah koleksi yang dipurge oleh retention purger.
+// Koleksi tokens tidak ada di sini karena dihapus lewat TTL index pada expiresAt.
+var RetentionPolicies = []retention.Policy{
+	{
+		Collection: "activity_log",
+		TimeField:  "created_at",
+		UserField:  "user_id",
+		Free:       retention.Days(RetentionFreeDays),
+		Supporter:  retention.Days(RetentionSupporterDays),
+	},
+	{
+		Collection: "notifications",
+		TimeField:  "created_at",
+		UserField:  "user_id",
+		Free:       retention.Days(RetentionFreeDays),
+		Supporter:  retention.Days(RetentionSupporterDays),
+	},
+	{
+		Collection: "login_logs",
+		TimeField:  "login_at",
+		Free:       retention.Days(LoginLogRetentionDays),
+		Supporter:  retention.Days(LoginLogRetentionDays),
+	},
+	// audit_log tidak ikut dihapus saat akun dihapus, hanya dibuang setelah masa retensinya
+	{
+		Collection: "audit_log",
+		TimeField:  "created_at",
+		Free:       retention.Days(AuditLogRetentionDays),
+		Supporter:  retention.Days(AuditLogRetentionDays),
+	},
+}
+
+// CronSecret dipakai scheduler (Cloud Scheduler) untuk memanggil endpoint purge tanpa token admin
+var CronSecret = os.Getenv("PDFM_CRON_SECRET")
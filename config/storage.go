@@ -0,0 +1,37 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gocroot/helper/storage"
+)
+
+// FileStore menyimpan file input/output tool pdfm di GridFS bucket "pdfm_files"
+var FileStore storage.Backend = storage.NewGridFS(Mongoconn, "pdfm_files")
+
+// FileRetention adalah lama file input/output disimpan agar operasi bisa dijalankan ulang
+var FileRetention = time.Duration(envInt("PDFM_FILE_RETENTION_HOURS", 24)) * time.Hour
+
+// MaxUploadSize adalah batas ukuran satu file yang diunggah ke pdfm (byte)
+var MaxUploadSize = int64(envInt("PDFM_MAX_UPLOAD_MB", 25)) << 20
+
+// FeedbackMaxAttachments dan FeedbackMaxAttachmentSize membatasi lampiran satu feedback
+var FeedbackMaxAttachments = envInt("PDFM_FEEDBACK_MAX_ATTACHMENTS", 5)
+
+var FeedbackMaxAttachmentSize = int64(envInt("PDFM_FEEDBACK_ATTACHMENT_MB", 10)) << 20
+
+// ProfilePhotoMaxSize adalah batas ukuran foto profil sebelum diproses menjadi avatar (byte)
+var ProfilePhotoMaxSize = int64(envInt("PDFM_PROFILE_PHOTO_MB", 5)) << 20
+
+// MaxRequestSize adalah batas ukuran body semua request (byte). Batas per endpoint yang lebih kecil tetap berlaku.
+var MaxRequestSize = int64(envInt("PDFM_MAX_REQUEST_MB", 64)) << 20
+
+func envInt(key string, def int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || n <= 0 {
+		return def
+	}
+	return n
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/gocroot/helper/watoken"
+)
+
+// WatokenAudience adalah aud token PASETO yang ditandatangani aplikasi ini
+var WatokenAudience = envString("WATOKEN_AUDIENCE", "domyikado")
+
+// WatokenKeyring berisi key untuk menandatangani dan memverifikasi token PASETO. Dibaca dari
+// WATOKEN_KEYRING (JSON hasil `go run ./cmd/watoken rotate`); jika kosong, PRKEY dipakai sebagai
+// key aktif dengan kid "legacy" sehingga deployment lama tetap berjalan.
+var WatokenKeyring = loadWatokenKeyring()
+
+func loadWatokenKeyring() *watoken.Keyring {
+	if raw := os.Getenv("WATOKEN_KEYRING"); raw != "" {
+		ring, err := watoken.ParseKeyring([]byte(raw))
+		if err == nil {
+			return ring
+		}
+		slog.Warn("WATOKEN_KEYRING tidak valid, memakai PRKEY", "error", err)
+	}
+	ring := &watoken.Keyring{}
+	if key, err := watoken.KeyFromPrivate("legacy", PrivateKey, time.Now()); err == nil {
+		ring.Keys = append(ring.Keys, key)
+	}
+	return ring
+}
+
+func envString(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
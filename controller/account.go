@@ -0,0 +1,454 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/activity"
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/auth"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/privacy"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/time/rate"
+)
+
+// exportLimiter membatasi ekspor data 3 kali per jam per user karena membaca banyak koleksi sekaligus
+var exportLimiter = auth.NewRateLimiter(rate.Every(20*time.Minute), 3)
+
+// ExportAccount godoc
+// @Summary Ekspor Data Pribadi (UU PDP)
+// @Description Mengunduh zip berisi profil, invoice, semua jenis riwayat, notifikasi, preferensi notifikasi, feedback, login log dan daftar file tersimpan milik user. Dibatasi 3 kali per jam
+// @Tags User Profile
+// @Produce application/zip
+// @Success 200 {file} file
+// @Failure 401 {object} model.ResponseMessage
+// @Failure 429 {object} model.ResponseMessage
+// @Router /pdfm/account/export [post]
+// @Security BearerAuth
+func ExportAccount(w http.ResponseWriter, r *http.Request) {
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+	if !exportLimiter.GetLimiter(user.ID.Hex()).Allow() {
+		w.Header().Set("Retry-After", "1200")
+		writeMessage(w, lang, http.StatusTooManyRequests, i18n.ExportRateLimited)
+		return
+	}
+
+	now := time.Now()
+	var buf bytes.Buffer
+	if err := writeAccountExport(r.Context(), privacy.NewArchive(&buf, now), user, now); err != nil {
+		slog.ErrorContext(r.Context(), "ekspor data user gagal", "user_id", user.ID.Hex(), "error", err)
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.ExportFailed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+privacy.ExportFileName(now)+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// writeAccountExport mengisi zip ekspor; setiap koleksi menjadi satu file JSON dan manifest.json merangkum jumlah entrinya
+func writeAccountExport(ctx context.Context, archive *privacy.Archive, user model.PdfmUsers, now time.Time) error {
+	manifest := model.ExportManifest{UserID: user.ID.Hex(), Email: user.Email, GeneratedAt: now, Files: map[string]int64{}}
+	add := func(name string, v interface{}, count int) error {
+		manifest.Files[name] = int64(count)
+		return archive.AddJSON(name, v)
+	}
+
+	user.Password = ""
+	if err := add("profile.json", user, 1); err != nil {
+		return err
+	}
+
+	invoices, err := exportDocs[model.Invoice](ctx, "invoices", bson.M{"email": user.Email}, "createdAt")
+	if err != nil {
+		return err
+	}
+	if err := add("invoices.json", invoices, len(invoices)); err != nil {
+		return err
+	}
+
+	// Semua tipe riwayat yang terdaftar ikut ditulis, kosong sekalipun, agar user tahu tipe apa saja yang dicatat
+	activities, err := exportDocs[model.Activity](ctx, activityCollection, bson.M{"user_id": user.ID}, "created_at")
+	if err != nil {
+		return err
+	}
+	history := map[string][]model.HistoryItem{}
+	var types []string
+	for _, tool := range activity.Tools() {
+		history[tool.Type] = []model.HistoryItem{}
+		types = append(types, tool.Type)
+	}
+	for _, a := range activities {
+		if _, ok := history[a.Type]; !ok {
+			types = append(types, a.Type)
+		}
+		history[a.Type] = append(history[a.Type], toHistoryItem(a))
+	}
+	for _, typ := range types {
+		if err := add("history/"+typ+".json", history[typ], len(history[typ])); err != nil {
+			return err
+		}
+	}
+
+	notifications, err := exportDocs[model.Notification](ctx, "notifications", bson.M{"user_id": user.ID}, "created_at")
+	if err != nil {
+		return err
+	}
+	if err := add("notifications.json", notifications, len(notifications)); err != nil {
+		return err
+	}
+
+	var prefs model.NotificationPreferences
+	err = GetMongoCollection(notificationPrefsCollection).FindOne(ctx, bson.M{"user_id": user.ID}).Decode(&prefs)
+	switch {
+	case err == nil:
+		if err := add("notification_preferences.json", prefs, 1); err != nil {
+			return err
+		}
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return err
+	}
+
+	feedback, err := exportDocs[model.Feedback](ctx, "feedback", bson.M{"$or": bson.A{
+		bson.M{"user_id": user.ID.Hex()},
+		bson.M{"email": user.Email},
+	}}, "created_at")
+	if err != nil {
+		return err
+	}
+	for i := range feedback {
+		// Hasil triage adalah catatan internal admin, bukan data yang diberikan user
+		feedback[i].Triage = nil
+	}
+	if err := add("feedback.json", feedback, len(feedback)); err != nil {
+		return err
+	}
+
+	logins, err := exportDocs[model.LoginLog](ctx, "login_logs", bson.M{"user_id": user.ID}, "login_at")
+	if err != nil {
+		return err
+	}
+	if err := add("login_logs.json", logins, len(logins)); err != nil {
+		return err
+	}
+
+	keys, err := exportDocs[model.APIKey](ctx, apiKeyCollection, bson.M{"user_id": user.ID}, "created_at")
+	if err != nil {
+		return err
+	}
+	if err := add("api_keys.json", keys, len(keys)); err != nil {
+		return err
+	}
+
+	files, err := config.FileStore.ListOwned(ctx, user.ID.Hex())
+	if err != nil {
+		return err
+	}
+	if err := add("files.json", files, len(files)); err != nil {
+		return err
+	}
+
+	if err := archive.AddJSON("manifest.json", manifest); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// exportDocs membaca semua dokumen yang cocok dengan filter, urut naik berdasarkan sortField
+func exportDocs[T any](ctx context.Context, collection string, filter bson.M, sortField string) ([]T, error) {
+	cursor, err := GetMongoCollection(collection).Find(ctx, filter, options.Find().SetSort(bson.D{{Key: sortField, Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	docs := []T{}
+	err = cursor.All(ctx, &docs)
+	return docs, err
+}
+
+// RequestAccountDeletion godoc
+// @Summary Hapus Akun Saya (UU PDP)
+// @Description Menjadwalkan penghapusan permanen akun setelah masa tenggang (default 14 hari, PDFM_ACCOUNT_DELETION_DAYS). Selama masa tenggang user masih bisa login dan membatalkan. Setelahnya riwayat, notifikasi, login log, token dan file dihapus, sedangkan invoice dan feedback dianonimkan
+// @Tags User Profile
+// @Accept json
+// @Produce json
+// @Param request body model.AccountDeletionInput true "Konfirmasi password"
+// @Success 202 {object} model.AccountDeletionResponse
+// @Failure 401 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Router /pdfm/account [delete]
+// @Security BearerAuth
+func RequestAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.AccountDeletionInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidBody, err)
+		return
+	}
+	if user.Password != "" && req.Password != user.Password {
+		writeMessage(w, lang, http.StatusForbidden, i18n.PasswordMismatch)
+		return
+	}
+
+	// Permintaan ulang tidak memundurkan jadwal yang sudah ada
+	scheduled := user.DeletionScheduledAt
+	if scheduled.IsZero() {
+		now := time.Now()
+		scheduled = now.AddDate(0, 0, config.AccountDeletionDays)
+		_, err := GetMongoCollection("users").UpdateOne(r.Context(), bson.M{"_id": user.ID}, bson.M{"$set": bson.M{
+			"deletionRequestedAt": now,
+			"deletionScheduledAt": scheduled,
+			"updatedAt":           now,
+		}})
+		if err != nil {
+			writeError(w, lang, http.StatusInternalServerError, i18n.SaveFailed, err)
+			return
+		}
+		recordAudit(r, &user, auditAccountDelete, "user", user.ID.Hex(), map[string]any{"scheduled_at": scheduled})
+		date := deletionDate(scheduled)
+		if err := CreateTemplatedNotification(user.ID, "account", i18n.NotifAccountDeletion, "alert-triangle", "", "date", date); err != nil {
+			slog.ErrorContext(r.Context(), "gagal membuat notifikasi hapus akun", "user_id", user.ID.Hex(), "error", err)
+		}
+	}
+
+	w.Header().Set("Content-Language", lang)
+	at.WriteJSON(w, http.StatusAccepted, model.AccountDeletionResponse{
+		Status:      http.StatusAccepted,
+		Code:        i18n.DeletionScheduled,
+		Message:     i18n.T(lang, i18n.DeletionScheduled, "date", deletionDate(scheduled)),
+		ScheduledAt: scheduled,
+	})
+}
+
+// deletionDate memformat jadwal hapus akun dalam zona waktu Asia/Jakarta
+func deletionDate(t time.Time) string {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	return t.In(loc).Format("2006-01-02")
+}
+
+// CancelAccountDeletion godoc
+// @Summary Batalkan Penghapusan Akun
+// @Description Membatalkan permintaan hapus akun yang masih dalam masa tenggang
+// @Tags User Profile
+// @Produce json
+// @Success 200 {object} model.ResponseMessage
+// @Failure 401 {object} model.ResponseMessage
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/account/deletion/cancel [post]
+// @Security BearerAuth
+func CancelAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := GetMongoCollection("users").UpdateOne(r.Context(),
+		bson.M{"_id": user.ID, "deletionScheduledAt": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"deletionRequestedAt": "", "deletionScheduledAt": ""}, "$set": bson.M{"updatedAt": time.Now()}},
+	)
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.SaveFailed, err)
+		return
+	}
+	if res.MatchedCount == 0 {
+		writeMessage(w, lang, http.StatusNotFound, i18n.DeletionNotFound)
+		return
+	}
+	recordAudit(r, &user, auditAccountCancel, "user", user.ID.Hex(), nil)
+	writeMessage(w, lang, http.StatusOK, i18n.DeletionCancelled)
+}
+
+// PurgeDeletedAccounts godoc
+// @Summary Jalankan Penghapusan Akun Terjadwal (Admin/Cron)
+// @Description Menghapus permanen akun yang masa tenggangnya sudah lewat beserta data personalnya (lihat config.PersonalData). Invoice dan feedback dianonimkan. Dipanggil admin atau scheduler dengan header X-Cron-Secret. Tambahkan ?dry_run=true untuk simulasi
+// @Tags Admin
+// @Produce json
+// @Param dry_run query bool false "Simulasi tanpa menghapus"
+// @Param X-Cron-Secret header string false "Secret scheduler (PDFM_CRON_SECRET)"
+// @Success 200 {object} model.AccountPurgeResult
+// @Failure 401 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Router /pdfm/admin/accounts/purge [post]
+// @Security BearerAuth
+func PurgeDeletedAccounts(w http.ResponseWriter, r *http.Request) {
+	var actor *model.PdfmUsers
+	lang := requestLocale(r, nil)
+	if !isCronRequest(r) {
+		admin, adminLang, ok := adminFromRequest(w, r)
+		if !ok {
+			return
+		}
+		actor, lang = &admin, adminLang
+	}
+
+	now := time.Now()
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	users, err := exportDocs[model.PdfmUsers](r.Context(), "users", bson.M{"deletionScheduledAt": bson.M{"$lte": now}}, "deletionScheduledAt")
+	if err != nil {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.FetchFailed)
+		return
+	}
+
+	tally := newErasureTally(now)
+	result := model.AccountPurgeResult{Status: http.StatusOK, Message: i18n.T(lang, i18n.AccountPurgeDone), DryRun: dryRun}
+	for _, u := range users {
+		if err := eraseAccount(r.Context(), u, dryRun, tally); err != nil {
+			slog.ErrorContext(r.Context(), "gagal menghapus akun", "user_id", u.ID.Hex(), "error", err)
+			result.Failed = append(result.Failed, u.ID.Hex())
+			continue
+		}
+		if !dryRun {
+			recordAudit(r, actor, auditAccountPurge, "user", u.ID.Hex(), map[string]any{"email": u.Email})
+		}
+		result.Accounts++
+	}
+	if dryRun {
+		result.Message = i18n.T(lang, i18n.AccountPurgeDryRun)
+	}
+	result.Items = tally.items()
+	at.WriteJSON(w, http.StatusOK, result)
+}
+
+// eraseAccount menghapus atau menganonimkan semua data personal satu user. Dokumen users dihapus
+// paling akhir sehingga akun yang gagal di tengah jalan akan dicoba lagi pada jalan berikutnya.
+func eraseAccount(ctx context.Context, user model.PdfmUsers, dryRun bool, tally *erasureTally) error {
+	owner := user.ID.Hex()
+	files, err := config.FileStore.ListOwned(ctx, owner)
+	if err != nil {
+		tally.fail("pdfm_files", err)
+		return err
+	}
+	tally.get("pdfm_files").Matched += int64(len(files))
+	if !dryRun {
+		for _, f := range files {
+			if err := config.FileStore.Delete(ctx, f.ID); err != nil {
+				tally.fail("pdfm_files", err)
+				return err
+			}
+			tally.get("pdfm_files").Deleted++
+		}
+	}
+
+	targets := append([]privacy.Target{}, config.PersonalData...)
+	for _, tool := range activity.Tools() {
+		if tool.LegacyCollection != "" {
+			targets = append(targets, privacy.Target{Collection: tool.LegacyCollection, Field: "user_id", Key: privacy.ByID, Action: privacy.Delete})
+		}
+	}
+	pseudonym := privacy.Pseudonym(user.ID)
+	for _, t := range targets {
+		filter := t.Filter(user.ID, user.Email)
+		coll := GetMongoCollection(t.Collection)
+		n, err := coll.CountDocuments(ctx, filter)
+		if err != nil {
+			tally.fail(t.Collection, err)
+			return err
+		}
+		item := tally.get(t.Collection)
+		item.Matched += n
+		if dryRun || n == 0 {
+			continue
+		}
+		if t.Action == privacy.Anonymize {
+			opts := options.Update()
+			if af := t.ArrayFilters(user.ID, user.Email); af != nil {
+				opts.SetArrayFilters(options.ArrayFilters{Filters: af})
+			}
+			res, err := coll.UpdateMany(ctx, filter, t.Update(pseudonym), opts)
+			if err != nil {
+				tally.fail(t.Collection, err)
+				return err
+			}
+			item.Anonymized += res.ModifiedCount
+			continue
+		}
+		res, err := coll.DeleteMany(ctx, filter)
+		if err != nil {
+			tally.fail(t.Collection, err)
+			return err
+		}
+		item.Deleted += res.DeletedCount
+	}
+
+	// Segmen pengumuman bisa menyebut user langsung lewat ID atau email
+	segment := bson.M{"$or": bson.A{bson.M{"segment.user_ids": user.ID}, bson.M{"segment.emails": user.Email}}}
+	n, err := GetMongoCollection(announcementCollection).CountDocuments(ctx, segment)
+	if err != nil {
+		tally.fail(announcementCollection, err)
+		return err
+	}
+	tally.get(announcementCollection).Matched += n
+	if !dryRun && n > 0 {
+		res, err := GetMongoCollection(announcementCollection).UpdateMany(ctx, segment,
+			bson.M{"$pull": bson.M{"segment.user_ids": user.ID, "segment.emails": user.Email}})
+		if err != nil {
+			tally.fail(announcementCollection, err)
+			return err
+		}
+		tally.get(announcementCollection).Anonymized += res.ModifiedCount
+	}
+
+	tally.get("users").Matched++
+	if dryRun {
+		return nil
+	}
+	if _, err := GetMongoCollection("users").DeleteOne(ctx, bson.M{"_id": user.ID}); err != nil {
+		tally.fail("users", err)
+		return err
+	}
+	tally.get("users").Deleted++
+	return nil
+}
+
+// erasureTally menjumlahkan hasil penghapusan semua akun per koleksi, urut sesuai kemunculan
+type erasureTally struct {
+	cutoff time.Time
+	order  []string
+	byName map[string]*model.RetentionItem
+}
+
+func newErasureTally(cutoff time.Time) *erasureTally {
+	return &erasureTally{cutoff: cutoff, byName: map[string]*model.RetentionItem{}}
+}
+
+func (t *erasureTally) get(collection string) *model.RetentionItem {
+	item, ok := t.byName[collection]
+	if !ok {
+		item = &model.RetentionItem{Collection: collection, Cutoff: t.cutoff}
+		t.byName[collection] = item
+		t.order = append(t.order, collection)
+	}
+	return item
+}
+
+func (t *erasureTally) fail(collection string, err error) {
+	t.get(collection).Error = err.Error()
+}
+
+func (t *erasureTally) items() []model.RetentionItem {
+	out := make([]model.RetentionItem, 0, len(t.order))
+	for _, name := range t.order {
+		out = append(out, *t.byName[name])
+	}
+	return out
+}
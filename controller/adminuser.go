@@ -0,0 +1,590 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/authn"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var userIndexOnce indexOnce
+
+// ensureUserIndexes membuat index untuk listing admin dan lookup login terakhir
+func ensureUserIndexes() {
+	userIndexOnce.Do(func() error {
+		_, err := GetMongoCollection("users").Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+			{Keys: bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}},
+			{Keys: bson.D{{Key: "email", Value: 1}}},
+		})
+		if err == nil {
+			_, err = GetMongoCollection("login_logs").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+				Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "login_at", Value: -1}},
+			})
+		}
+		if err != nil {
+			slog.Error("gagal membuat index", "collection", "users", "error", err)
+		}
+		return err
+	})
+}
+
+// notDeleted adalah filter user yang belum di-soft delete
+var notDeleted = bson.M{"deletedAt": bson.M{"$exists": false}}
+
+// activeUser mempersempit filter ke user yang belum dihapus dan tidak disuspend, mis. untuk
+// penerima pengumuman dan notifikasi
+func activeUser(filter bson.M) bson.M {
+	return bson.M{"$and": bson.A{filter, notDeleted, bson.M{"suspended": bson.M{"$ne": true}}}}
+}
+
+// adminFromRequest memastikan pemanggil adalah admin; respons error sudah ditulis jika ok bernilai false
+func adminFromRequest(w http.ResponseWriter, r *http.Request) (admin model.PdfmUsers, lang string, ok bool) {
+	lang = requestLocale(r, nil)
+	p, err := Authenticate(r)
+	if err == nil && p.User == nil {
+		err = errNotPdfmAccount
+	}
+	if err != nil {
+		writeError(w, lang, http.StatusUnauthorized, i18n.Unauthorized, err)
+		return admin, lang, false
+	}
+	admin = *p.User
+	lang = requestLocale(r, &admin)
+	if !p.HasRole(authn.RoleAdmin) {
+		writeMessage(w, lang, http.StatusForbidden, i18n.ForbiddenAdmin)
+		return admin, lang, false
+	}
+	return admin, lang, true
+}
+
+// ListUsersAdmin godoc
+// @Summary Daftar User (Admin)
+// @Description Mencari dan memfilter user dengan cursor pagination. Password tidak pernah ikut dikirim. User yang dihapus hanya tampil dengan status=deleted
+// @Tags User Management
+// @Produce json
+// @Param q query string false "Cari nama atau email"
+// @Param admin query bool false "Filter admin"
+// @Param supporter query bool false "Filter supporter"
+// @Param verified query bool false "Filter email terverifikasi"
+// @Param status query string false "active (default: semua yang belum dihapus), suspended, deleted"
+// @Param from query string false "Tanggal daftar awal (YYYY-MM-DD atau RFC3339)"
+// @Param to query string false "Tanggal daftar akhir (YYYY-MM-DD atau RFC3339)"
+// @Param sort query string false "newest (default), oldest, name_asc, name_desc, email_asc, email_desc"
+// @Param limit query int false "Jumlah item per halaman (default 20, maks 100)"
+// @Param cursor query string false "Cursor dari response sebelumnya (next_cursor)"
+// @Success 200 {object} model.AdminUserListResponse
+// @Failure 400 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Router /pdfm/admin/users [get]
+// @Security BearerAuth
+func ListUsersAdmin(w http.ResponseWriter, r *http.Request) {
+	_, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	limit := paging.Limit(q.Get("limit"), 20, 100)
+	sortBy := q.Get("sort")
+	if sortBy == "" {
+		sortBy = "newest"
+	}
+	if _, known := userSortFields[sortBy]; !known {
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidQuery, errors.New("sort"))
+		return
+	}
+
+	filter, err := adminUserFilter(q)
+	if err != nil {
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidQuery, err)
+		return
+	}
+
+	ensureUserIndexes()
+	total, err := GetMongoCollection("users").CountDocuments(r.Context(), filter)
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
+		return
+	}
+
+	if raw := q.Get("cursor"); raw != "" {
+		cur, err := paging.Decode(raw)
+		if err == nil {
+			var cursorMatch bson.M
+			cursorMatch, err = userCursorFilter(sortBy, cur)
+			filter = bson.M{"$and": bson.A{filter, cursorMatch}}
+		}
+		if err != nil {
+			writeError(w, lang, http.StatusBadRequest, i18n.InvalidQuery, err)
+			return
+		}
+	}
+
+	field, dir := userSortFields[sortBy].field, userSortFields[sortBy].dir
+	rows, err := atdb.AggregateDoc[model.AdminUser](config.Mongoconn, "users", bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$sort": bson.D{{Key: field, Value: dir}, {Key: "_id", Value: dir}}},
+		bson.M{"$limit": limit + 1},
+		bson.M{"$project": bson.M{"password": 0}},
+		lastLoginLookup,
+		bson.M{"$set": bson.M{"lastLoginAt": bson.M{"$first": "$lastLogin.login_at"}}},
+		bson.M{"$unset": "lastLogin"},
+	})
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
+		return
+	}
+
+	response := model.AdminUserListResponse{
+		Status:  http.StatusOK,
+		Message: "Users retrieved successfully",
+		Users:   []model.AdminUser{},
+		Total:   total,
+	}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[limit-1]
+		response.HasMore = true
+		text := last.Name
+		if field == "email" {
+			text = last.Email
+		}
+		response.NextCursor = paging.Encode(paging.Cursor{Time: last.CreatedAt, Text: text, ID: last.ID.Hex()})
+	}
+	if rows != nil {
+		response.Users = rows
+	}
+	at.WriteJSON(w, http.StatusOK, response)
+}
+
+// lastLoginLookup mengambil entri login_logs terakhir tiap user
+var lastLoginLookup = bson.M{"$lookup": bson.M{
+	"from": "login_logs",
+	"let":  bson.M{"uid": "$_id"},
+	"pipeline": bson.A{
+		bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$user_id", "$$uid"}}}},
+		bson.M{"$sort": bson.M{"login_at": -1}},
+		bson.M{"$limit": 1},
+		bson.M{"$project": bson.M{"login_at": 1}},
+	},
+	"as": "lastLogin",
+}}
+
+// userSortFields memetakan parameter sort ke field dan arah urutan
+var userSortFields = map[string]struct {
+	field string
+	dir   int
+}{
+	"newest":     {"createdAt", -1},
+	"oldest":     {"createdAt", 1},
+	"name_asc":   {"name", 1},
+	"name_desc":  {"name", -1},
+	"email_asc":  {"email", 1},
+	"email_desc": {"email", -1},
+}
+
+// adminUserFilter membangun filter users dari parameter query listing admin
+func adminUserFilter(q map[string][]string) (bson.M, error) {
+	get := func(key string) string {
+		if v := q[key]; len(v) > 0 {
+			return strings.TrimSpace(v[0])
+		}
+		return ""
+	}
+	and := bson.A{}
+
+	switch get("status") {
+	case "":
+		and = append(and, notDeleted)
+	case "active":
+		and = append(and, notDeleted, bson.M{"suspended": bson.M{"$ne": true}})
+	case "suspended":
+		and = append(and, notDeleted, bson.M{"suspended": true})
+	case "deleted":
+		and = append(and, bson.M{"deletedAt": bson.M{"$exists": true}})
+	default:
+		return nil, errors.New("status harus active, suspended atau deleted")
+	}
+
+	for param, field := range map[string]string{"admin": "isAdmin", "supporter": "isSupport", "verified": "isVerified"} {
+		raw := get(param)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("parameter " + param + " harus true atau false")
+		}
+		if v {
+			and = append(and, bson.M{field: true})
+		} else {
+			and = append(and, bson.M{field: bson.M{"$ne": true}})
+		}
+	}
+
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	createdAt := bson.M{}
+	if from := get("from"); from != "" {
+		t, err := paging.ParseDate(from, loc, false)
+		if err != nil {
+			return nil, errors.New("Format tanggal 'from' tidak valid")
+		}
+		createdAt["$gte"] = t
+	}
+	if to := get("to"); to != "" {
+		t, err := paging.ParseDate(to, loc, true)
+		if err != nil {
+			return nil, errors.New("Format tanggal 'to' tidak valid")
+		}
+		createdAt["$lte"] = t
+	}
+	if len(createdAt) > 0 {
+		and = append(and, bson.M{"createdAt": createdAt})
+	}
+
+	if search := get("q"); search != "" {
+		rx := bson.M{"$regex": regexp.QuoteMeta(search), "$options": "i"}
+		and = append(and, bson.M{"$or": bson.A{bson.M{"name": rx}, bson.M{"email": rx}}})
+	}
+	return bson.M{"$and": and}, nil
+}
+
+// userCursorFilter membuat filter "setelah item terakhir" sesuai arah urutan
+func userCursorFilter(sortBy string, cur paging.Cursor) (bson.M, error) {
+	id, err := primitive.ObjectIDFromHex(cur.ID)
+	if err != nil {
+		return nil, paging.ErrInvalidCursor
+	}
+	s := userSortFields[sortBy]
+	op := "$gt"
+	if s.dir < 0 {
+		op = "$lt"
+	}
+	var value interface{} = cur.Time
+	if s.field != "createdAt" {
+		value = cur.Text
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{s.field: bson.M{op: value}},
+		bson.M{s.field: value, "_id": bson.M{op: id}},
+	}}, nil
+}
+
+// GetUserAdmin godoc
+// @Summary Detail User dan Ringkasan Aktivitas (Admin)
+// @Description Data user (tanpa password) beserta jumlah dan total invoice, jumlah riwayat per tipe, jumlah feedback, dan login terakhir dari login_logs
+// @Tags User Management
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} model.AdminUserDetailResponse
+// @Failure 403 {object} model.ResponseMessage
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/admin/users/{id} [get]
+// @Security BearerAuth
+func GetUserAdmin(w http.ResponseWriter, r *http.Request) {
+	_, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(at.GetParam(r))
+	if err != nil {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.UserInvalidID)
+		return
+	}
+	user, err := atdb.GetOneDoc[model.PdfmUsers](config.Mongoconn, "users", bson.M{"_id": id})
+	if err != nil {
+		writeMessage(w, lang, http.StatusNotFound, i18n.UserNotFound)
+		return
+	}
+	user.Password = ""
+
+	summary, err := userSummary(r.Context(), user)
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
+		return
+	}
+	detail := model.AdminUser{PdfmUsers: user}
+	if summary.LastLogin != nil {
+		detail.LastLoginAt = summary.LastLogin.LoginAt
+	}
+	at.WriteJSON(w, http.StatusOK, model.AdminUserDetailResponse{
+		Status:  http.StatusOK,
+		Message: "User retrieved successfully",
+		User:    detail,
+		Summary: summary,
+	})
+}
+
+// userSummary menghitung ringkasan invoice, riwayat, feedback dan login satu user.
+// Invoice dicocokkan lewat email karena koleksi invoice tidak menyimpan ID user.
+func userSummary(ctx context.Context, user model.PdfmUsers) (model.UserSummary, error) {
+	summary := model.UserSummary{History: map[string]int64{}}
+
+	type invoiceStats struct {
+		Count  int64     `bson:"count"`
+		Paid   int64     `bson:"paid"`
+		Latest time.Time `bson:"latest"`
+	}
+	inv, err := atdb.AggregateDoc[invoiceStats](config.Mongoconn, "invoices", bson.A{
+		bson.M{"$match": bson.M{"email": user.Email}},
+		bson.M{"$group": bson.M{
+			"_id":    nil,
+			"count":  bson.M{"$sum": 1},
+			"paid":   bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", "Paid"}}, "$amount", 0}}},
+			"latest": bson.M{"$max": "$createdAt"},
+		}},
+	})
+	if err != nil {
+		return summary, err
+	}
+	if len(inv) > 0 {
+		summary.Invoices, summary.InvoiceTotal, summary.LastInvoiceAt = inv[0].Count, inv[0].Paid, inv[0].Latest
+	}
+
+	history, err := atdb.AggregateDoc[model.FeedbackCount](config.Mongoconn, activityCollection, bson.A{
+		bson.M{"$match": bson.M{"user_id": user.ID}},
+		bson.M{"$group": bson.M{"_id": "$type", "count": bson.M{"$sum": 1}}},
+	})
+	if err != nil {
+		return summary, err
+	}
+	for _, h := range history {
+		summary.History[h.Value] = h.Count
+		summary.HistoryTotal += h.Count
+	}
+
+	if summary.Feedback, err = GetMongoCollection("feedback").CountDocuments(ctx, bson.M{"user_id": user.ID.Hex()}); err != nil {
+		return summary, err
+	}
+	logins := GetMongoCollection("login_logs")
+	if summary.Logins30d, err = logins.CountDocuments(ctx, bson.M{
+		"user_id":  user.ID,
+		"login_at": bson.M{"$gte": time.Now().AddDate(0, 0, -30)},
+	}); err != nil {
+		return summary, err
+	}
+	var last model.LoginLog
+	err = logins.FindOne(ctx, bson.M{"user_id": user.ID}, options.FindOne().SetSort(bson.M{"login_at": -1})).Decode(&last)
+	switch {
+	case err == nil:
+		summary.LastLogin = &last
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return summary, err
+	}
+	return summary, nil
+}
+
+// DeleteUserAdmin godoc
+// @Summary Hapus User (Soft Delete, Admin)
+// @Description Menandai user sebagai dihapus dan mencabut semua tokennya. Data tetap ada dan bisa dipulihkan lewat endpoint restore
+// @Tags User Management
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/admin/users/{id} [delete]
+// @Security BearerAuth
+func DeleteUserAdmin(w http.ResponseWriter, r *http.Request) {
+	admin, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(at.GetParam(r))
+	if err != nil {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.UserInvalidID)
+		return
+	}
+	if id == admin.ID {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.UserSelfAction)
+		return
+	}
+	user, err := softDeleteUser(r.Context(), id, admin.ID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			writeMessage(w, lang, http.StatusNotFound, i18n.UserNotFound)
+			return
+		}
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
+		return
+	}
+	recordAudit(r, &admin, auditUserDelete, "user", id.Hex(), map[string]any{"email": user.Email})
+	writeMessage(w, lang, http.StatusOK, i18n.UserDeleted)
+}
+
+// softDeleteUser menandai user yang belum dihapus sebagai dihapus lalu mencabut tokennya.
+// Mengembalikan mongo.ErrNoDocuments jika user tidak ada atau sudah dihapus.
+func softDeleteUser(ctx context.Context, id, by primitive.ObjectID) (model.PdfmUsers, error) {
+	now := time.Now()
+	set := bson.M{"deletedAt": now, "updatedAt": now}
+	if !by.IsZero() {
+		set["deletedBy"] = by
+	}
+	var user model.PdfmUsers
+	err := GetMongoCollection("users").FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "deletedAt": bson.M{"$exists": false}},
+		bson.M{"$set": set},
+	).Decode(&user)
+	if err != nil {
+		return user, err
+	}
+	return user, revokeUserTokens(ctx, user.Email)
+}
+
+// revokeUserTokens menghapus semua token login user sehingga sesi aktif langsung berakhir
+func revokeUserTokens(ctx context.Context, email string) error {
+	_, err := GetMongoCollection("tokens").DeleteMany(ctx, bson.M{"email": email})
+	return err
+}
+
+// RestoreUserAdmin godoc
+// @Summary Pulihkan User yang Dihapus (Admin)
+// @Description Membatalkan soft delete sehingga user bisa login kembali
+// @Tags User Management
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/admin/users/{id}/restore [post]
+// @Security BearerAuth
+func RestoreUserAdmin(w http.ResponseWriter, r *http.Request) {
+	admin, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(at.PathParam(r, "/pdfm/admin/users/:id/restore", "id"))
+	if err != nil {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.UserInvalidID)
+		return
+	}
+	res, err := GetMongoCollection("users").UpdateOne(r.Context(),
+		bson.M{"_id": id, "deletedAt": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"deletedAt": "", "deletedBy": ""}, "$set": bson.M{"updatedAt": time.Now()}},
+	)
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
+		return
+	}
+	if res.MatchedCount == 0 {
+		writeMessage(w, lang, http.StatusNotFound, i18n.UserNotFound)
+		return
+	}
+	recordAudit(r, &admin, auditUserRestore, "user", id.Hex(), nil)
+	writeMessage(w, lang, http.StatusOK, i18n.UserRestored)
+}
+
+// SuspendUserAdmin godoc
+// @Summary Tangguhkan User (Admin)
+// @Description Memblokir login user dan mencabut semua tokennya tanpa menghapus data. Alasan disimpan untuk catatan admin
+// @Tags User Management
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body model.SuspendUserInput false "Alasan penangguhan"
+// @Success 200 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/admin/users/{id}/suspend [post]
+// @Security BearerAuth
+func SuspendUserAdmin(w http.ResponseWriter, r *http.Request) {
+	admin, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(at.PathParam(r, "/pdfm/admin/users/:id/suspend", "id"))
+	if err != nil {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.UserInvalidID)
+		return
+	}
+	if id == admin.ID {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.UserSelfAction)
+		return
+	}
+	var req model.SuspendUserInput
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, lang, http.StatusBadRequest, i18n.InvalidBody, err)
+			return
+		}
+	}
+
+	now := time.Now()
+	set := bson.M{"suspended": true, "suspendedAt": now, "updatedAt": now}
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		set["suspendReason"] = reason
+	}
+	var user model.PdfmUsers
+	err = GetMongoCollection("users").FindOneAndUpdate(r.Context(),
+		bson.M{"_id": id, "deletedAt": bson.M{"$exists": false}},
+		bson.M{"$set": set},
+	).Decode(&user)
+	if err == nil {
+		err = revokeUserTokens(r.Context(), user.Email)
+	}
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			writeMessage(w, lang, http.StatusNotFound, i18n.UserNotFound)
+			return
+		}
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
+		return
+	}
+	recordAudit(r, &admin, auditUserSuspend, "user", id.Hex(), map[string]any{"email": user.Email, "reason": set["suspendReason"]})
+	writeMessage(w, lang, http.StatusOK, i18n.UserSuspended)
+}
+
+// UnsuspendUserAdmin godoc
+// @Summary Cabut Penangguhan User (Admin)
+// @Description Mengizinkan user yang ditangguhkan untuk login kembali
+// @Tags User Management
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/admin/users/{id}/unsuspend [post]
+// @Security BearerAuth
+func UnsuspendUserAdmin(w http.ResponseWriter, r *http.Request) {
+	admin, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(at.PathParam(r, "/pdfm/admin/users/:id/unsuspend", "id"))
+	if err != nil {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.UserInvalidID)
+		return
+	}
+	res, err := GetMongoCollection("users").UpdateOne(r.Context(),
+		bson.M{"_id": id, "deletedAt": bson.M{"$exists": false}},
+		bson.M{"$unset": bson.M{"suspended": "", "suspendedAt": "", "suspendReason": ""}, "$set": bson.M{"updatedAt": time.Now()}},
+	)
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
+		return
+	}
+	if res.MatchedCount == 0 {
+		writeMessage(w, lang, http.StatusNotFound, i18n.UserNotFound)
+		return
+	}
+	recordAudit(r, &admin, auditUserUnsuspend, "user", id.Hex(), nil)
+	writeMessage(w, lang, http.StatusOK, i18n.UserUnsuspended)
+}
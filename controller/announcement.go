@@ -0,0 +1,373 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/notify"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	announcementCollection = "announcements"
+	announcementBatchSize  = 1000
+)
+
+var announcementIndexOnce indexOnce
+
+// ensureAnnouncementIndexes mencegah satu pengumuman tersebar dua kali ke user yang sama
+// jika fan-out diulang setelah terputus
+func ensureAnnouncementIndexes() {
+	announcementIndexOnce.Do(func() error {
+		ctx := context.Background()
+		_, err1 := GetMongoCollection("notifications").Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "announcement_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"announcement_id": bson.M{"$exists": true}}),
+		})
+		if err1 != nil {
+			slog.Error("gagal membuat index announcement", "collection", "notifications", "error", err1)
+		}
+		_, err2 := GetMongoCollection(announcementCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "publish_at", Value: 1}},
+		})
+		if err2 != nil {
+			slog.Error("gagal membuat index", "collection", announcementCollection, "error", err2)
+		}
+		return errors.Join(err1, err2)
+	})
+}
+
+// announcementAudience mengubah target pengumuman menjadi filter koleksi users. User yang dihapus
+// atau disuspend disaring terpisah lewat activeUser.
+func announcementAudience(a model.Announcement) bson.M {
+	switch a.Audience {
+	case "supporters":
+		return bson.M{"isSupport": true}
+	case "segment":
+		filter := bson.M{}
+		seg := a.Segment
+		if len(seg.UserIDs) > 0 {
+			filter["_id"] = bson.M{"$in": seg.UserIDs}
+		}
+		if len(seg.Emails) > 0 {
+			filter["email"] = bson.M{"$in": seg.Emails}
+		}
+		if seg.Supporter != nil {
+			filter["isSupport"] = *seg.Supporter
+		}
+		created := bson.M{}
+		if !seg.RegisteredAfter.IsZero() {
+			created["$gte"] = seg.RegisteredAfter
+		}
+		if !seg.RegisteredBefore.IsZero() {
+			created["$lt"] = seg.RegisteredBefore
+		}
+		if len(created) > 0 {
+			filter["createdAt"] = created
+		}
+		return filter
+	}
+	return bson.M{}
+}
+
+// publishAnnouncement menyebar pengumuman ke notifikasi setiap user target lewat BulkWrite per batch
+func publishAnnouncement(ctx context.Context, a *model.Announcement) error {
+	ensureAnnouncementIndexes()
+	coll := GetMongoCollection("notifications")
+	_, err := GetMongoCollection(announcementCollection).UpdateOne(ctx,
+		bson.M{"_id": a.ID}, bson.M{"$set": bson.M{"status": "publishing"}})
+	if err != nil {
+		return err
+	}
+
+	cursor, err := GetMongoCollection("users").Find(ctx, activeUser(announcementAudience(*a)),
+		options.Find().SetProjection(bson.M{"_id": 1}).SetBatchSize(announcementBatchSize))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	now := time.Now()
+	var batch []mongo.WriteModel
+	var published []model.Notification
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		_, err := coll.BulkWrite(ctx, batch, options.BulkWrite().SetOrdered(false))
+		// Duplikat berarti user tersebut sudah menerima pada fan-out sebelumnya
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+		// Hanya notifikasi yang benar-benar tersimpan yang dikirim ke SSE agar fan-out ulang tidak
+		// menampilkan pengumuman yang sama dua kali
+		failed := map[int]bool{}
+		var bulkErr mongo.BulkWriteException
+		if errors.As(err, &bulkErr) {
+			for _, we := range bulkErr.WriteErrors {
+				failed[we.Index] = true
+			}
+		}
+		for i, n := range published {
+			if !failed[i] {
+				publishNotification(n)
+			}
+		}
+		batch, published = batch[:0], published[:0]
+		return nil
+	}
+
+	for cursor.Next(ctx) {
+		var u struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if cursor.Decode(&u) != nil {
+			continue
+		}
+		n := model.Notification{
+			ID:             primitive.NewObjectID(),
+			UserID:         u.ID,
+			Type:           "announcement",
+			Title:          a.Title,
+			Message:        a.Body,
+			Icon:           a.Icon,
+			Link:           a.Link,
+			AnnouncementID: a.ID,
+			CreatedAt:      now,
+			Deliveries: []model.NotificationDelivery{
+				{Channel: notify.ChannelInApp, Status: notify.StatusSent, SentAt: now},
+			},
+		}
+		batch = append(batch, mongo.NewInsertOneModel().SetDocument(n))
+		published = append(published, n)
+		if len(batch) >= announcementBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	recipients, err := coll.CountDocuments(ctx, bson.M{"announcement_id": a.ID})
+	if err != nil {
+		return err
+	}
+	a.Status, a.Recipients, a.PublishedAt = "published", recipients, now
+	_, err = GetMongoCollection(announcementCollection).UpdateOne(ctx, bson.M{"_id": a.ID}, bson.M{"$set": bson.M{
+		"status":       a.Status,
+		"recipients":   a.Recipients,
+		"published_at": a.PublishedAt,
+	}})
+	return err
+}
+
+// runAnnouncementSchedule menerbitkan pengumuman terjadwal yang sudah waktunya (termasuk fan-out yang
+// terputus) dan menarik notifikasi pengumuman yang sudah kedaluwarsa
+func runAnnouncementSchedule(ctx context.Context, now time.Time) (published, expired int) {
+	due, err := atdb.GetAllDoc[[]model.Announcement](config.Mongoconn, announcementCollection, bson.M{
+		"status":     bson.M{"$in": bson.A{"scheduled", "publishing"}},
+		"publish_at": bson.M{"$lte": now},
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "gagal membaca pengumuman terjadwal", "error", err)
+	}
+	for i := range due {
+		if err := publishAnnouncement(ctx, &due[i]); err != nil {
+			slog.ErrorContext(ctx, "gagal menerbitkan pengumuman", "announcement_id", due[i].ID.Hex(), "error", err)
+			continue
+		}
+		published++
+	}
+
+	stale, err := atdb.GetAllDoc[[]model.Announcement](config.Mongoconn, announcementCollection, bson.M{
+		"status":     "published",
+		"expires_at": bson.M{"$lte": now},
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "gagal membaca pengumuman kedaluwarsa", "error", err)
+	}
+	for _, a := range stale {
+		if _, err := atdb.DeleteManyDocs(config.Mongoconn, "notifications", bson.M{"announcement_id": a.ID}); err != nil {
+			continue
+		}
+		GetMongoCollection(announcementCollection).UpdateOne(ctx, bson.M{"_id": a.ID}, bson.M{"$set": bson.M{"status": "expired"}})
+		expired++
+	}
+	return
+}
+
+// CreateAnnouncement godoc
+// @Summary Buat Pengumuman (Admin Only)
+// @Description Menyebar pengumuman ke notifikasi in-app semua user, supporter saja, atau segmen tertentu. Jika publish_at di masa depan, pengumuman diterbitkan oleh job /pdfm/admin/notifications/dispatch
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body model.Announcement true "Pengumuman"
+// @Success 201 {object} model.AnnouncementResponse
+// @Failure 400 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Router /pdfm/admin/announcements [post]
+// @Security BearerAuth
+func CreateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	admin, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var a model.Announcement
+	if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidBody, err)
+		return
+	}
+	a.Title, a.Body = strings.TrimSpace(a.Title), strings.TrimSpace(a.Body)
+	if a.Audience == "" {
+		a.Audience = "all"
+	}
+	now := time.Now()
+	if a.PublishAt.IsZero() {
+		a.PublishAt = now
+	}
+	invalid := ""
+	switch {
+	case a.Title == "" || a.Body == "":
+		invalid = i18n.AnnouncementRequired
+	case a.Audience != "all" && a.Audience != "supporters" && a.Audience != "segment":
+		invalid = i18n.AnnouncementAudienceInvalid
+	case a.Audience == "segment" && len(announcementAudience(a)) == 0:
+		invalid = i18n.AnnouncementSegmentEmpty
+	case a.Link != "" && !strings.HasPrefix(a.Link, "https://") && !strings.HasPrefix(a.Link, "/"):
+		invalid = i18n.AnnouncementLinkInvalid
+	case !a.ExpiresAt.IsZero() && !a.ExpiresAt.After(a.PublishAt):
+		invalid = i18n.AnnouncementExpiryInvalid
+	}
+	if invalid != "" {
+		writeMessage(w, lang, http.StatusBadRequest, invalid)
+		return
+	}
+
+	a.ID = primitive.NewObjectID()
+	a.Status = "scheduled"
+	a.Recipients = 0
+	a.CreatedBy = admin.ID
+	a.CreatedAt = now
+	a.PublishedAt = time.Time{}
+	if _, err := atdb.InsertOneDoc(config.Mongoconn, announcementCollection, a); err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.SaveFailed, err)
+		return
+	}
+
+	code := i18n.AnnouncementScheduled
+	if !a.PublishAt.After(now) {
+		if err := publishAnnouncement(r.Context(), &a); err != nil {
+			// Tetap tersimpan berstatus publishing; job dispatch akan melanjutkan fan-out
+			slog.ErrorContext(r.Context(), "fan-out pengumuman gagal", "announcement_id", a.ID.Hex(), "error", err)
+			code = i18n.AnnouncementDeferred
+		} else {
+			code = i18n.AnnouncementPublished
+		}
+	}
+
+	w.Header().Set("Content-Language", lang)
+	at.WriteJSON(w, http.StatusCreated, model.AnnouncementResponse{
+		Status:       http.StatusCreated,
+		Message:      i18n.T(lang, code),
+		Announcement: a,
+	})
+}
+
+// GetAnnouncements godoc
+// @Summary Daftar Pengumuman (Admin Only)
+// @Description Semua pengumuman beserta jumlah penerima dan jumlah yang sudah membaca
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} model.AnnouncementListResponse
+// @Failure 403 {object} model.ResponseMessage
+// @Router /pdfm/admin/announcements [get]
+// @Security BearerAuth
+func GetAnnouncements(w http.ResponseWriter, r *http.Request) {
+	_, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(100)
+	cursor, err := GetMongoCollection(announcementCollection).Find(r.Context(), bson.M{}, opts)
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.FetchFailed, err)
+		return
+	}
+	announcements := []model.Announcement{}
+	cursor.All(r.Context(), &announcements)
+
+	// Jumlah pembaca per pengumuman dalam satu agregasi
+	reads, _ := atdb.AggregateDoc[struct {
+		ID    primitive.ObjectID `bson:"_id"`
+		Count int64              `bson:"count"`
+	}](config.Mongoconn, "notifications", bson.A{
+		bson.M{"$match": bson.M{"announcement_id": bson.M{"$exists": true}, "is_read": true}},
+		bson.M{"$group": bson.M{"_id": "$announcement_id", "count": bson.M{"$sum": 1}}},
+	})
+	readCount := map[primitive.ObjectID]int64{}
+	for _, rc := range reads {
+		readCount[rc.ID] = rc.Count
+	}
+	for i := range announcements {
+		announcements[i].ReadCount = readCount[announcements[i].ID]
+	}
+
+	json.NewEncoder(w).Encode(model.AnnouncementListResponse{
+		Status:        http.StatusOK,
+		Message:       "Announcements retrieved successfully",
+		Announcements: announcements,
+	})
+}
+
+// WithdrawAnnouncement godoc
+// @Summary Tarik Pengumuman (Admin Only)
+// @Description Membatalkan pengumuman terjadwal atau menarik notifikasi pengumuman yang sudah terbit dari semua user
+// @Tags Admin
+// @Produce json
+// @Param id path string true "ID pengumuman"
+// @Success 200 {object} model.ResponseMessage
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/admin/announcements/{id} [delete]
+// @Security BearerAuth
+func WithdrawAnnouncement(w http.ResponseWriter, r *http.Request) {
+	admin, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(at.GetParam(r))
+	if err != nil {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+	res, err := GetMongoCollection(announcementCollection).UpdateOne(r.Context(),
+		bson.M{"_id": id}, bson.M{"$set": bson.M{"status": "withdrawn"}})
+	if err != nil || res.MatchedCount == 0 {
+		writeMessage(w, lang, http.StatusNotFound, i18n.AnnouncementNotFound)
+		return
+	}
+	atdb.DeleteManyDocs(config.Mongoconn, "notifications", bson.M{"announcement_id": id})
+	recordAudit(r, &admin, auditAnnounceWithdraw, "announcement", id.Hex(), nil)
+
+	writeMessage(w, lang, http.StatusOK, i18n.AnnouncementWithdrawn)
+}
@@ -0,0 +1,425 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/activity"
+	"github.com/gocroot/helper/apikey"
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/authn"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	apiKeyCollection      = "api_keys"
+	apiKeyUsageCollection = "api_key_usage"
+	apiKeyRateCollection  = "api_key_rate"
+
+	// Scope di luar tipe tool: unggah/unduh file tool dan membaca/menjalankan ulang riwayat
+	scopeFiles   = "files"
+	scopeHistory = "history"
+)
+
+var (
+	apiKeyIndexOnce indexOnce
+	// apiKeyLimiters hanya cadangan per instance jika hitungan bersama di Mongo gagal
+	apiKeyLimiters = apikey.NewLimiters()
+)
+
+func ensureAPIKeyIndexes() {
+	apiKeyIndexOnce.Do(func() error {
+		ctx := context.Background()
+		_, err := GetMongoCollection(apiKeyCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+			{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		})
+		if err == nil {
+			_, err = GetMongoCollection(apiKeyUsageCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys:    bson.D{{Key: "key_id", Value: 1}, {Key: "day", Value: -1}},
+				Options: options.Index().SetUnique(true),
+			})
+		}
+		if err == nil {
+			_, err = GetMongoCollection(apiKeyRateCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{Keys: bson.D{{Key: "key_id", Value: 1}, {Key: "window", Value: 1}}, Options: options.Index().SetUnique(true)},
+				{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+			})
+		}
+		if err != nil {
+			slog.Error("gagal membuat index", "collection", apiKeyCollection, "error", err)
+		}
+		return err
+	})
+}
+
+// apiKeyScopes adalah semua scope yang bisa dipilih: tipe tool di registry activity, files dan history
+func apiKeyScopes() []string {
+	scopes := []string{scopeFiles, scopeHistory}
+	for _, tool := range activity.Tools() {
+		scopes = append(scopes, tool.Type)
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+func knownAPIKeyScope(scope string) bool {
+	if scope == scopeFiles || scope == scopeHistory {
+		return true
+	}
+	_, err := activity.Lookup(scope)
+	return err == nil
+}
+
+// AuthenticateAPIKey memeriksa header X-API-Key untuk endpoint dengan scope tertentu (lihat RequireScope).
+// Key diperiksa (hash, masa berlaku, pencabutan, scope dan rate limit per key) lalu pemiliknya disimpan di
+// context sebagai authn.Principal sehingga GetUserFromToken mengenalinya. Rate limit dihitung di Mongo per
+// jendela menit sehingga berlaku bersama untuk semua instance. Pemakaian key dicatat sebelum handler
+// dijalankan. Jika ok bernilai false, respons error sudah ditulis.
+func AuthenticateAPIKey(w http.ResponseWriter, r *http.Request, scope string) (*http.Request, bool) {
+	lang := requestLocale(r, nil)
+	plain := strings.TrimSpace(r.Header.Get("X-API-Key"))
+	if !apikey.Valid(plain) {
+		writeMessage(w, lang, http.StatusUnauthorized, i18n.APIKeyInvalid)
+		return r, false
+	}
+
+	ensureAPIKeyIndexes()
+	now := time.Now()
+	var key model.APIKey
+	err := GetMongoCollection(apiKeyCollection).FindOne(r.Context(), bson.M{"hash": apikey.Hash(plain)}).Decode(&key)
+	if err != nil || !key.RevokedAt.IsZero() || (!key.ExpiresAt.IsZero() && now.After(key.ExpiresAt)) {
+		writeMessage(w, lang, http.StatusUnauthorized, i18n.APIKeyInvalid)
+		return r, false
+	}
+	var user model.PdfmUsers
+	if err := GetMongoCollection("users").FindOne(r.Context(), bson.M{"_id": key.UserID}).Decode(&user); err != nil || !user.DeletedAt.IsZero() {
+		writeMessage(w, lang, http.StatusUnauthorized, i18n.APIKeyInvalid)
+		return r, false
+	}
+	lang = requestLocale(r, &user)
+	if user.Suspended {
+		writeMessage(w, lang, http.StatusForbidden, i18n.AccountSuspended)
+		return r, false
+	}
+	if !apikey.Allows(key.Scopes, scope) {
+		writeMessage(w, lang, http.StatusForbidden, i18n.APIKeyScope, "scope", scope)
+		return r, false
+	}
+
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(key.RateLimit))
+	ok, retry := allowAPIKeyRequest(r.Context(), key, now)
+	ip, _ := at.GetClientIP(r)
+	recordAPIKeyUsage(r.Context(), key, scope, ip, now, !ok)
+	if !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+		writeMessage(w, lang, http.StatusTooManyRequests, i18n.APIKeyRateLimited)
+		return r, false
+	}
+
+	// API key tidak pernah membawa role admin walaupun pemiliknya admin
+	principal := &authn.Principal{
+		Scheme:    authn.SchemeAPIKey,
+		Subject:   user.ID.Hex(),
+		Name:      user.Name,
+		Email:     user.Email,
+		Roles:     []string{authn.RoleUser},
+		Scopes:    key.Scopes,
+		KeyID:     key.ID.Hex(),
+		ExpiresAt: key.ExpiresAt,
+		Supporter: user.IsSupport,
+		User:      &user,
+	}
+	return r.WithContext(authn.WithPrincipal(r.Context(), principal)), true
+}
+
+// allowAPIKeyRequest menambah hitungan request key pada jendela menit berjalan dan memeriksanya terhadap
+// batas key. Jika Mongo gagal, limiter lokal per instance dipakai agar API tetap terbatas.
+func allowAPIKeyRequest(ctx context.Context, key model.APIKey, now time.Time) (bool, time.Duration) {
+	if key.RateLimit <= 0 {
+		return true, 0
+	}
+	window := apikey.Window(now)
+	filter := bson.M{"key_id": key.ID, "window": window}
+	update := bson.M{
+		"$inc":         bson.M{"count": 1},
+		"$setOnInsert": bson.M{"expires_at": window.Add(2 * time.Minute)},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var counter struct {
+		Count int `bson:"count"`
+	}
+	err := GetMongoCollection(apiKeyRateCollection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&counter)
+	if mongo.IsDuplicateKeyError(err) {
+		// Dua upsert bersamaan untuk jendela baru; dokumen sudah dibuat request lain
+		err = GetMongoCollection(apiKeyRateCollection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&counter)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "gagal menghitung rate limit API key, memakai limiter lokal", "key_id", key.ID.Hex(), "error", err)
+		return apiKeyLimiters.Allow(key.ID.Hex(), key.RateLimit, now)
+	}
+	return apikey.AllowWindow(counter.Count, key.RateLimit, now)
+}
+
+// recordAPIKeyUsage memperbarui last-used key dan menambah hitungan harian per scope
+func recordAPIKeyUsage(ctx context.Context, key model.APIKey, scope, ip string, now time.Time, limited bool) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	inc := bson.M{"total": 1}
+	if limited {
+		inc = bson.M{"limited": 1}
+	} else {
+		inc["scopes."+scope] = 1
+		_, err := GetMongoCollection(apiKeyCollection).UpdateOne(ctx, bson.M{"_id": key.ID}, bson.M{
+			"$set": bson.M{"last_used_at": now, "last_used_ip": ip},
+			"$inc": bson.M{"usage_count": 1},
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "gagal mencatat pemakaian API key", "key_id", key.ID.Hex(), "error", err)
+		}
+	}
+	_, err := GetMongoCollection(apiKeyUsageCollection).UpdateOne(ctx,
+		bson.M{"key_id": key.ID, "day": now.In(loc).Format("2006-01-02")},
+		bson.M{"$inc": inc, "$setOnInsert": bson.M{"user_id": key.UserID}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "gagal mencatat pemakaian harian API key", "key_id", key.ID.Hex(), "error", err)
+	}
+}
+
+// apiKeyAllows bernilai true untuk login biasa, atau jika API key yang dipakai punya scope tersebut
+func apiKeyAllows(r *http.Request, scope string) bool {
+	p := authn.FromContext(r.Context())
+	return p == nil || p.Scheme != authn.SchemeAPIKey || apikey.Allows(p.Scopes, scope)
+}
+
+// CreateAPIKey godoc
+// @Summary Buat API Key
+// @Description Membuat personal API key untuk memanggil tool pdfm dari script lewat header X-API-Key. Key hanya ditampilkan sekali di response ini. Scope berisi tipe tool (merge, compress, ...), files, history, atau * untuk semua. rate_limit adalah batas request per menit kalender untuk key ini, dihitung bersama di semua instance server
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Param request body model.APIKeyInput true "Nama, scope dan batas request per menit"
+// @Success 201 {object} model.APIKeyCreatedResponse
+// @Failure 400 {object} model.ResponseMessage
+// @Failure 409 {object} model.ResponseMessage
+// @Router /pdfm/apikeys [post]
+// @Security BearerAuth
+func CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.APIKeyInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidBody, err)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.APIKeyNameRequired)
+		return
+	}
+	scopes, err := apikey.NormalizeScopes(req.Scopes, knownAPIKeyScope)
+	if err != nil {
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidBody, err)
+		return
+	}
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > 365 {
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidBody, errors.New("expires_in_days harus 0-365"))
+		return
+	}
+	rateLimit := req.RateLimit
+	if rateLimit <= 0 || rateLimit > config.APIKeyRatePerMinute {
+		rateLimit = config.APIKeyRatePerMinute
+	}
+
+	ensureAPIKeyIndexes()
+	now := time.Now()
+	active, err := GetMongoCollection(apiKeyCollection).CountDocuments(r.Context(), activeAPIKeys(user.ID, now))
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
+		return
+	}
+	if active >= int64(config.APIKeyMaxPerUser) {
+		writeMessage(w, lang, http.StatusConflict, i18n.APIKeyLimit, "max", strconv.Itoa(config.APIKeyMaxPerUser))
+		return
+	}
+
+	plain, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
+		return
+	}
+	key := model.APIKey{
+		ID:        primitive.NewObjectID(),
+		UserID:    user.ID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		Hash:      hash,
+		Scopes:    scopes,
+		RateLimit: rateLimit,
+		CreatedAt: now,
+	}
+	if req.ExpiresInDays > 0 {
+		key.ExpiresAt = now.AddDate(0, 0, req.ExpiresInDays)
+	}
+	if _, err := GetMongoCollection(apiKeyCollection).InsertOne(r.Context(), key); err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.SaveFailed, err)
+		return
+	}
+	recordAudit(r, &user, auditAPIKeyCreate, "apikey", key.ID.Hex(), map[string]any{"prefix": prefix, "scopes": scopes})
+
+	w.Header().Set("Content-Language", lang)
+	w.Header().Set("Cache-Control", "no-store")
+	at.WriteJSON(w, http.StatusCreated, model.APIKeyCreatedResponse{
+		Status:  http.StatusCreated,
+		Message: i18n.T(lang, i18n.APIKeyCreated),
+		Key:     plain,
+		APIKey:  key,
+	})
+}
+
+// activeAPIKeys adalah filter key milik user yang belum dicabut dan belum kedaluwarsa
+func activeAPIKeys(userID primitive.ObjectID, now time.Time) bson.M {
+	return bson.M{
+		"user_id":    userID,
+		"revoked_at": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$exists": false}},
+			bson.M{"expires_at": bson.M{"$gt": now}},
+		},
+	}
+}
+
+// ListAPIKeys godoc
+// @Summary Daftar API Key Saya
+// @Description Menampilkan semua API key milik user (termasuk yang sudah dicabut) beserta scope yang tersedia. Key asli tidak pernah ditampilkan lagi, hanya awalannya
+// @Tags API Key
+// @Produce json
+// @Success 200 {object} model.APIKeyListResponse
+// @Failure 401 {object} model.ResponseMessage
+// @Router /pdfm/apikeys [get]
+// @Security BearerAuth
+func ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+	keys, err := exportDocs[model.APIKey](r.Context(), apiKeyCollection, bson.M{"user_id": user.ID}, "created_at")
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
+		return
+	}
+	// Terbaru di atas
+	for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+		keys[i], keys[j] = keys[j], keys[i]
+	}
+	at.WriteJSON(w, http.StatusOK, model.APIKeyListResponse{
+		Status:  http.StatusOK,
+		Message: "API keys retrieved successfully",
+		Keys:    keys,
+		Scopes:  apiKeyScopes(),
+	})
+}
+
+// RevokeAPIKey godoc
+// @Summary Cabut API Key
+// @Description Mencabut API key sehingga langsung tidak bisa dipakai lagi. Riwayat pemakaiannya tetap tersimpan
+// @Tags API Key
+// @Produce json
+// @Param id path string true "ID API key"
+// @Success 200 {object} model.ResponseMessage
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/apikeys/{id} [delete]
+// @Security BearerAuth
+func RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(at.GetParam(r))
+	if err != nil {
+		writeMessage(w, lang, http.StatusNotFound, i18n.APIKeyNotFound)
+		return
+	}
+	res, err := GetMongoCollection(apiKeyCollection).UpdateOne(r.Context(),
+		bson.M{"_id": id, "user_id": user.ID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.SaveFailed, err)
+		return
+	}
+	if res.MatchedCount == 0 {
+		writeMessage(w, lang, http.StatusNotFound, i18n.APIKeyNotFound)
+		return
+	}
+	recordAudit(r, &user, auditAPIKeyRevoke, "apikey", id.Hex(), nil)
+	writeMessage(w, lang, http.StatusOK, i18n.APIKeyRevoked)
+}
+
+// GetAPIKeyUsage godoc
+// @Summary Pemakaian API Key
+// @Description Jumlah request per hari (zona Asia/Jakarta) dan per scope untuk satu API key, termasuk request yang ditolak rate limit
+// @Tags API Key
+// @Produce json
+// @Param id path string true "ID API key"
+// @Param days query int false "Jumlah hari ke belakang (default 30, maks 90)"
+// @Success 200 {object} model.APIKeyUsageResponse
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/apikeys/{id}/usage [get]
+// @Security BearerAuth
+func GetAPIKeyUsage(w http.ResponseWriter, r *http.Request) {
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(at.PathParam(r, "/pdfm/apikeys/:id/usage", "id"))
+	if err != nil {
+		writeMessage(w, lang, http.StatusNotFound, i18n.APIKeyNotFound)
+		return
+	}
+	var key model.APIKey
+	if err := GetMongoCollection(apiKeyCollection).FindOne(r.Context(), bson.M{"_id": id, "user_id": user.ID}).Decode(&key); err != nil {
+		writeMessage(w, lang, http.StatusNotFound, i18n.APIKeyNotFound)
+		return
+	}
+
+	days, err := strconv.Atoi(r.URL.Query().Get("days"))
+	if err != nil || days <= 0 {
+		days = 30
+	}
+	if days > 90 {
+		days = 90
+	}
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	since := time.Now().In(loc).AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+	usage, err := exportDocs[model.APIKeyUsage](r.Context(), apiKeyUsageCollection,
+		bson.M{"key_id": key.ID, "day": bson.M{"$gte": since}}, "day")
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
+		return
+	}
+	at.WriteJSON(w, http.StatusOK, model.APIKeyUsageResponse{
+		Status:  http.StatusOK,
+		Message: "Usage retrieved successfully",
+		Key:     key,
+		Days:    usage,
+	})
+}
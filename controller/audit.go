@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/authn"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/logging"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const auditCollection = "audit_log"
+
+// Aksi yang dicatat di audit_log
+const (
+	auditUserDelete       = "admin.user.delete"
+	auditUserRestore      = "admin.user.restore"
+	auditUserSuspend      = "admin.user.suspend"
+	auditUserUnsuspend    = "admin.user.unsuspend"
+	auditUserCreate       = "user.create"
+	auditUserUpdate       = "user.update"
+	auditUserRemove       = "user.delete"
+	auditPaymentConfirm   = "payment.confirm"
+	auditAccountDelete    = "account.deletion.request"
+	auditAccountCancel    = "account.deletion.cancel"
+	auditAccountPurge     = "account.purge"
+	auditAccountLink      = "account.google.link"
+	auditHistoryDelete    = "history.delete"
+	auditRetentionPurge   = "retention.purge"
+	auditAPIKeyCreate     = "apikey.create"
+	auditAPIKeyRevoke     = "apikey.revoke"
+	auditTokenRevoke      = "token.revoke"
+	auditAnnounceWithdraw = "announcement.withdraw"
+)
+
+var auditIndexOnce indexOnce
+
+func ensureAuditIndexes() {
+	auditIndexOnce.Do(func() error {
+		_, err := GetMongoCollection(auditCollection).Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+			{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "action", Value: 1}, {Key: "created_at", Value: -1}}},
+			{Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "created_at", Value: -1}}},
+			{Keys: bson.D{{Key: "target_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		})
+		if err != nil {
+			slog.Error("gagal membuat index audit_log", "error", err)
+		}
+		return err
+	})
+}
+
+// recordAudit mencatat aksi ke audit_log dan ke log JSON. actor boleh nil; pelaku lalu diambil dari
+// principal di context, atau dicatat sebagai cron/anonymous. Kegagalan menulis ke database hanya
+// dicatat di log agar aksi utamanya tidak ikut gagal.
+func recordAudit(r *http.Request, actor *model.PdfmUsers, action, targetType, targetID string, details map[string]any) {
+	entry := model.AuditLog{
+		ID:         primitive.NewObjectID(),
+		Action:     action,
+		ActorType:  "anonymous",
+		TargetType: targetType,
+		TargetID:   targetID,
+		Details:    details,
+		UserAgent:  r.UserAgent(),
+		RequestID:  logging.RequestID(r.Context()),
+		CreatedAt:  time.Now(),
+	}
+	entry.IP, _ = at.GetClientIP(r)
+	p := authn.FromContext(r.Context())
+	switch {
+	case actor != nil:
+		entry.ActorID, entry.ActorEmail, entry.ActorType = actor.ID, actor.Email, authn.SchemeSession
+		if p != nil {
+			entry.ActorType = p.Scheme
+		}
+	case p != nil:
+		entry.ActorEmail, entry.ActorType = p.Email, p.Scheme
+		if p.User != nil {
+			entry.ActorID = p.User.ID
+		}
+	case isCronRequest(r):
+		entry.ActorType = "cron"
+	}
+
+	slog.InfoContext(r.Context(), "audit",
+		"action", entry.Action,
+		"actor_id", entry.ActorID.Hex(),
+		"actor_type", entry.ActorType,
+		"target_type", entry.TargetType,
+		"target_id", entry.TargetID,
+	)
+	ensureAuditIndexes()
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 5*time.Second)
+	defer cancel()
+	if _, err := GetMongoCollection(auditCollection).InsertOne(ctx, entry); err != nil {
+		slog.ErrorContext(r.Context(), "gagal menyimpan audit log", "action", action, "error", err)
+	}
+}
+
+// GetAuditLog godoc
+// @Summary Audit Log (Admin)
+// @Description Aksi yang berkaitan dengan keamanan (perubahan user oleh admin, pembayaran, penghapusan data, API key dan token), terbaru lebih dulu dengan cursor pagination
+// @Tags User Management
+// @Produce json
+// @Param action query string false "Awalan aksi, mis. admin.user atau payment.confirm"
+// @Param actor_id query string false "ID user pelaku"
+// @Param target_id query string false "ID objek yang terkena aksi"
+// @Param from query string false "Tanggal awal (YYYY-MM-DD atau RFC3339)"
+// @Param to query string false "Tanggal akhir (YYYY-MM-DD atau RFC3339)"
+// @Param limit query int false "Jumlah entri per halaman (default 50, maks 200)"
+// @Param cursor query string false "next_cursor dari halaman sebelumnya"
+// @Success 200 {object} model.AuditLogListResponse
+// @Failure 400 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Router /pdfm/admin/audit [get]
+// @Security BearerAuth
+func GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	_, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	limit := paging.Limit(q.Get("limit"), 50, 200)
+	filter, err := auditFilter(q.Get)
+	if err != nil {
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidQuery, err)
+		return
+	}
+
+	ensureAuditIndexes()
+	rows, err := atdb.AggregateDoc[model.AuditLog](config.Mongoconn, auditCollection, bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$sort": bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		bson.M{"$limit": limit + 1},
+	})
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
+		return
+	}
+
+	response := model.AuditLogListResponse{
+		Status:  http.StatusOK,
+		Message: "Audit log retrieved successfully",
+		Entries: []model.AuditLog{},
+	}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[limit-1]
+		response.HasMore = true
+		response.NextCursor = paging.Encode(paging.Cursor{Time: last.CreatedAt, ID: last.ID.Hex()})
+	}
+	if rows != nil {
+		response.Entries = rows
+	}
+	at.WriteJSON(w, http.StatusOK, response)
+}
+
+// auditFilter membangun filter audit_log dari parameter query listing admin
+func auditFilter(get func(string) string) (bson.M, error) {
+	and := bson.A{}
+	if action := strings.TrimSpace(get("action")); action != "" {
+		and = append(and, bson.M{"$or": bson.A{
+			bson.M{"action": action},
+			bson.M{"action": bson.M{"$regex": "^" + regexp.QuoteMeta(action+".")}},
+		}})
+	}
+	if raw := get("actor_id"); raw != "" {
+		id, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			return nil, errors.New("actor_id tidak valid")
+		}
+		and = append(and, bson.M{"actor_id": id})
+	}
+	if target := strings.TrimSpace(get("target_id")); target != "" {
+		and = append(and, bson.M{"target_id": target})
+	}
+
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	createdAt := bson.M{}
+	if from := get("from"); from != "" {
+		t, err := paging.ParseDate(from, loc, false)
+		if err != nil {
+			return nil, errors.New("Format tanggal 'from' tidak valid")
+		}
+		createdAt["$gte"] = t
+	}
+	if to := get("to"); to != "" {
+		t, err := paging.ParseDate(to, loc, true)
+		if err != nil {
+			return nil, errors.New("Format tanggal 'to' tidak valid")
+		}
+		createdAt["$lte"] = t
+	}
+	if len(createdAt) > 0 {
+		and = append(and, bson.M{"created_at": createdAt})
+	}
+
+	if raw := get("cursor"); raw != "" {
+		cur, err := paging.Decode(raw)
+		if err != nil {
+			return nil, err
+		}
+		id, err := primitive.ObjectIDFromHex(cur.ID)
+		if err != nil {
+			return nil, paging.ErrInvalidCursor
+		}
+		and = append(and, bson.M{"$or": bson.A{
+			bson.M{"created_at": bson.M{"$lt": cur.Time}},
+			bson.M{"created_at": cur.Time, "_id": bson.M{"$lt": id}},
+		}})
+	}
+	if len(and) == 0 {
+		return bson.M{}, nil
+	}
+	return bson.M{"$and": and}, nil
+}
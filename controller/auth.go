@@ -3,8 +3,8 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
-	"net/url"
 	"regexp"
 	"time"
 
@@ -12,7 +12,7 @@ import (
 	"github.com/gocroot/helper/at"
 	"github.com/gocroot/helper/atdb"
 	"github.com/gocroot/helper/auth"
-	"github.com/gocroot/helper/watoken"
+	"github.com/gocroot/helper/captcha"
 	"github.com/gocroot/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -21,7 +21,7 @@ import (
 )
 
 func RegisterGmailAuth(w http.ResponseWriter, r *http.Request) {
-	logintoken, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	logintoken, err := decodeLoginToken(r)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid "
@@ -190,7 +190,7 @@ func Auth(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(response)
 		return
 	} else if existingUser.PhoneNumber != "" {
-		token, err := watoken.EncodeforHours(existingUser.PhoneNumber, existingUser.Name, config.PrivateKey, 18) // Generating a token for 18 hours
+		token, err := signLoginToken(existingUser.PhoneNumber, existingUser.Name, 18*time.Hour) // Generating a token for 18 hours
 		if err != nil {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusInternalServerError)
@@ -241,36 +241,20 @@ func GeneratePasswordHandler(respw http.ResponseWriter, r *http.Request) {
 		return
 	}
 	// Validate CAPTCHA
-	captchaResponse, err := http.PostForm("https://challenges.cloudflare.com/turnstile/v0/siteverify", url.Values{
-		"secret":   {"0x4AAAAAAAfj2NjfaHRBhkd2VjcfmRe5gvI"},
-		"response": {request.Captcha},
-	})
-	if err != nil {
+	remoteIP, _ := at.GetClientIP(r)
+	if err := config.CaptchaVerifier.Verify(r.Context(), request.Captcha, remoteIP); err != nil {
 		var respn model.Response
+		if errors.Is(err, captcha.ErrInvalid) {
+			respn.Status = "Unauthorized"
+			respn.Response = "Invalid captcha"
+			at.WriteJSON(respw, http.StatusUnauthorized, respn)
+			return
+		}
 		respn.Status = "Failed to verify captcha"
 		respn.Response = err.Error()
 		at.WriteJSON(respw, http.StatusServiceUnavailable, respn)
 		return
 	}
-	defer captchaResponse.Body.Close()
-
-	var captchaResult struct {
-		Success bool `json:"success"`
-	}
-	if err := json.NewDecoder(captchaResponse.Body).Decode(&captchaResult); err != nil {
-		var respn model.Response
-		respn.Status = "Failed to decode captcha response"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
-		return
-	}
-	if !captchaResult.Success {
-		var respn model.Response
-		respn.Status = "Unauthorized"
-		respn.Response = "Invalid captcha"
-		at.WriteJSON(respw, http.StatusUnauthorized, respn)
-		return
-	}
 
 	// Validate phone number
 	re := regexp.MustCompile(`^62\d{9,15}$`)
@@ -284,7 +268,7 @@ func GeneratePasswordHandler(respw http.ResponseWriter, r *http.Request) {
 
 	// Check if phone number exists in the 'user' collection
 	userFilter := bson.M{"phonenumber": request.PhoneNumber}
-	_, err = atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", userFilter)
+	_, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", userFilter)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Unauthorized"
@@ -430,7 +414,7 @@ func VerifyPasswordHandler(respw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := watoken.EncodeforHours(existingUser.PhoneNumber, existingUser.Name, config.PrivateKey, 18)
+	token, err := signLoginToken(existingUser.PhoneNumber, existingUser.Name, 18*time.Hour)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Failed to give the token"
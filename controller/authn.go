@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/authn"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	errUserNotFound     = errors.New("user tidak ditemukan")
+	errAccountDeleted   = errors.New("akun sudah dihapus")
+	errAccountSuspended = errors.New("akun ditangguhkan")
+	errNotPdfmAccount   = errors.New("token bukan milik akun pdfm")
+)
+
+// Authenticator menerima token sesi pdfm (Authorization: Bearer) dan watoken PASETO (header Login).
+// Request dengan X-API-Key di route RequireScope sudah membawa principal di context dari AuthenticateAPIKey.
+var Authenticator authn.Authenticator = authn.Chain{
+	authn.Session{Resolve: resolveSessionToken},
+	authn.PASETO{Keyring: watokenKeyring, Options: watokenVerifyOptions},
+}
+
+// resolveSessionToken mencari token UUID di koleksi tokens lalu memuat user pemiliknya
+func resolveSessionToken(ctx context.Context, token string) (*authn.Principal, error) {
+	tokenData, err := atdb.GetOneDoc[model.Token](config.Mongoconn, "tokens", bson.M{"token": token})
+	if err != nil {
+		return nil, authn.ErrInvalid
+	}
+	if tokenData.ExpiresAt.Before(time.Now()) {
+		return nil, authn.ErrExpired
+	}
+	user, err := atdb.GetOneDoc[model.PdfmUsers](config.Mongoconn, "users", bson.M{"email": tokenData.Email})
+	if err != nil {
+		return nil, errUserNotFound
+	}
+	if !user.DeletedAt.IsZero() {
+		return nil, errAccountDeleted
+	}
+	if user.Suspended {
+		return nil, errAccountSuspended
+	}
+	return &authn.Principal{
+		Scheme:    authn.SchemeSession,
+		Subject:   user.ID.Hex(),
+		Name:      user.Name,
+		Email:     user.Email,
+		Roles:     authn.UserRoles(user),
+		Supporter: user.IsSupport,
+		ExpiresAt: tokenData.ExpiresAt,
+		User:      &user,
+	}, nil
+}
+
+// Authenticate mengembalikan principal request dari skema token mana pun
+func Authenticate(r *http.Request) (*authn.Principal, error) {
+	return Authenticator.Authenticate(r)
+}
+
+// GetUserFromToken mengembalikan akun pdfm pemilik token sesi atau API key
+func GetUserFromToken(r *http.Request) (model.PdfmUsers, error) {
+	p, err := Authenticate(r)
+	if err != nil {
+		return model.PdfmUsers{}, err
+	}
+	if p.User == nil {
+		return model.PdfmUsers{}, errNotPdfmAccount
+	}
+	return *p.User, nil
+}
+
+// GetUserIDFromToken mengembalikan ID akun pdfm pemilik token
+func GetUserIDFromToken(r *http.Request) (primitive.ObjectID, error) {
+	user, err := GetUserFromToken(r)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return user.ID, nil
+}
+
+// sessionUser mengautentikasi request untuk handler pdfm dan menulis respons error yang sesuai jika gagal
+func sessionUser(w http.ResponseWriter, r *http.Request) (user model.PdfmUsers, lang string, ok bool) {
+	lang = requestLocale(r, nil)
+	user, err := GetUserFromToken(r)
+	if err != nil {
+		writeAuthError(w, lang, err)
+		return user, lang, false
+	}
+	return user, requestLocale(r, &user), true
+}
+
+// writeAuthError menulis respons untuk error autentikasi dari Authenticate atau GetUserFromToken
+func writeAuthError(w http.ResponseWriter, lang string, err error) {
+	switch {
+	case errors.Is(err, authn.ErrNoCredentials):
+		writeMessage(w, lang, http.StatusUnauthorized, i18n.TokenMissing)
+	case errors.Is(err, authn.ErrMalformed):
+		writeMessage(w, lang, http.StatusUnauthorized, i18n.TokenInvalidFormat)
+	case errors.Is(err, errUserNotFound):
+		writeMessage(w, lang, http.StatusNotFound, i18n.UserNotFound)
+	case errors.Is(err, errAccountSuspended):
+		writeMessage(w, lang, http.StatusForbidden, i18n.AccountSuspended)
+	default:
+		writeMessage(w, lang, http.StatusUnauthorized, i18n.TokenInvalid)
+	}
+}
@@ -17,7 +17,7 @@ import (
 )
 
 func GetDataSenders(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
@@ -54,7 +54,7 @@ func GetDataSenders(respw http.ResponseWriter, req *http.Request) {
 }
 
 func GetDataSendersTerblokir(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
@@ -92,7 +92,7 @@ func GetDataSendersTerblokir(respw http.ResponseWriter, req *http.Request) {
 
 func GetRekapBlast(respw http.ResponseWriter, req *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(req)
@@ -137,7 +137,7 @@ func GetRekapBlast(respw http.ResponseWriter, req *http.Request) {
 
 // melakukan pendaftaran nomor blast dengan pengecekan apakah suda link device
 func PutNomorBlast(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid "
@@ -177,7 +177,7 @@ func PutNomorBlast(respw http.ResponseWriter, req *http.Request) {
 		IsGroup:  false,
 		Messages: "Hai hai... permisi... nomor ini saya daftarkan untuk broadcast ya mohon clear semua notifikasi, karena nanti ada notifikasi kode untuk linked device ke wa business, mohon notif wa business nya juga di allow di handphone",
 	}
-	httpstatuscode, _, err := atapi.PostStructWithToken[model.Response]("token", config.WAAPIToken, newmsg, config.WAAPIMessage)
+	httpstatuscode, _, err := atapi.PostStructWithToken[model.Response]("token", config.WAAPIToken(), newmsg, config.WAAPIMessage)
 	if httpstatuscode != 200 || err != nil {
 		var respn model.Response
 		respn.Status = "Error : Nomor yang diinputkan tidak valid"
@@ -256,7 +256,7 @@ func PutNomorBlast(respw http.ResponseWriter, req *http.Request) {
 			IsGroup:  false,
 			Messages: "Masukkan kode: *" + qrstat.Code + "*\n" + qrstat.Message + "\nUntuk nomor" + qrstat.PhoneNumber,
 		}
-		httpstatuscode, _, err = atapi.PostStructWithToken[model.Response]("token", config.WAAPIToken, newmsg, config.WAAPITextMessage)
+		httpstatuscode, _, err = atapi.PostStructWithToken[model.Response]("token", config.WAAPIToken(), newmsg, config.WAAPITextMessage)
 		if httpstatuscode != 200 || err != nil {
 			var respn model.Response
 			respn.Status = "Error : Nomor yang diinputkan tidak valid"
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/auth"
+	"github.com/gocroot/helper/captcha"
+	"github.com/gocroot/helper/helpdesk"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/spam"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/time/rate"
+)
+
+// contactMaxMessage adalah panjang maksimal pesan form kontak (karakter)
+const contactMaxMessage = 5000
+
+var (
+	contactLimiter = auth.NewRateLimiter(rate.Every(time.Hour/time.Duration(config.ContactRatePerHour)), config.ContactRatePerHour)
+	contactSpam    = spam.NewScorer(config.SpamBlocklist...)
+)
+
+// SubmitContact godoc
+// @Summary Form Kontak (Tanpa Login)
+// @Description Pengunjung tanpa akun mengirim pesan ke tim pdfm. Wajib token CAPTCHA Turnstile, dibatasi per IP, dan pesan yang terdeteksi spam ditolak. Pesan yang diterima masuk ke daftar feedback admin sebagai feedback anonim. Field website adalah honeypot dan harus kosong
+// @Tags Feedback
+// @Accept json
+// @Produce json
+// @Param request body model.ContactInput true "Pesan kontak"
+// @Success 200 {object} model.FeedbackResponse
+// @Failure 400 {object} model.ResponseMessage
+// @Failure 401 {object} model.ResponseMessage
+// @Failure 422 {object} model.ResponseMessage
+// @Failure 429 {object} model.ResponseMessage
+// @Router /pdfm/contact [post]
+func SubmitContact(w http.ResponseWriter, r *http.Request) {
+	lang := requestLocale(r, nil)
+	ip, _ := at.GetClientIP(r)
+	if !contactLimiter.GetLimiter(ip).Allow() {
+		w.Header().Set("Retry-After", "3600")
+		writeMessage(w, lang, http.StatusTooManyRequests, i18n.ContactRateLimited)
+		return
+	}
+
+	var req model.ContactInput
+	r.Body = http.MaxBytesReader(w, r.Body, 64<<10)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidBody)
+		return
+	}
+
+	// Honeypot terisi: balas seolah berhasil agar bot tidak tahu pesannya dibuang
+	if req.Website != "" {
+		slog.WarnContext(r.Context(), "form kontak: honeypot terisi", "ip", ip)
+		at.WriteJSON(w, http.StatusOK, model.FeedbackResponse{Message: i18n.T(lang, i18n.FeedbackThanks), ID: primitive.NewObjectID()})
+		return
+	}
+
+	req.Name, req.Email, req.Message = strings.TrimSpace(req.Name), strings.TrimSpace(req.Email), strings.TrimSpace(req.Message)
+	invalid := ""
+	switch {
+	case req.Name == "" || req.Message == "":
+		invalid = i18n.ContactRequired
+	case !validContactEmail(req.Email):
+		invalid = i18n.ContactEmailInvalid
+	case utf8.RuneCountInString(req.Message) > contactMaxMessage:
+		invalid = i18n.ContactMessageTooLong
+	case req.Category != "" && !helpdesk.ValidCategory(req.Category):
+		invalid = i18n.FeedbackCategoryInvalid
+	}
+	if invalid != "" {
+		writeMessage(w, lang, http.StatusBadRequest, invalid, "max", strconv.Itoa(contactMaxMessage))
+		return
+	}
+
+	if err := config.CaptchaVerifier.Verify(r.Context(), req.Captcha, ip); err != nil {
+		if errors.Is(err, captcha.ErrInvalid) {
+			writeMessage(w, lang, http.StatusUnauthorized, i18n.CaptchaInvalid)
+			return
+		}
+		slog.WarnContext(r.Context(), "form kontak: verifikasi captcha gagal", "error", err)
+		writeMessage(w, lang, http.StatusServiceUnavailable, i18n.CaptchaUnavailable)
+		return
+	}
+
+	score := contactSpam.Score(req.Name, req.Email, req.Message)
+	if score.Spam {
+		slog.WarnContext(r.Context(), "form kontak: spam", "ip", ip, "score", score.Score, "reasons", score.Reasons)
+		writeMessage(w, lang, http.StatusUnprocessableEntity, i18n.ContactSpam)
+		return
+	}
+
+	now := time.Now()
+	data := model.Feedback{
+		ID:        primitive.NewObjectID(),
+		Name:      req.Name,
+		Email:     req.Email,
+		Message:   req.Message,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Anonymous: true,
+		SpamScore: score.Score,
+	}
+	data.Category, data.Priority, data.Status = helpdesk.Normalize(req.Category, "", "")
+	if _, err := atdb.InsertOneDoc(config.Mongoconn, "feedback", data); err != nil {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.SaveFailed)
+		return
+	}
+	go func(f model.Feedback) {
+		if _, err := analyzeFeedback(context.Background(), f); err != nil {
+			slog.ErrorContext(r.Context(), "gagal menganalisis feedback", "feedback_id", f.ID.Hex(), "error", err)
+		}
+	}(data)
+
+	at.WriteJSON(w, http.StatusOK, model.FeedbackResponse{
+		Message: i18n.T(lang, i18n.FeedbackThanks),
+		ID:      data.ID,
+	})
+}
+
+// validContactEmail memastikan email berupa alamat polos (tanpa nama tampilan) agar admin bisa membalas
+func validContactEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email && strings.Contains(email[strings.LastIndex(email, "@"):], ".")
+}
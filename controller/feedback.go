@@ -1,15 +1,31 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gocroot/config"
+	"github.com/gocroot/helper/at"
 	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/helpdesk"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/storage"
+	"github.com/gocroot/helper/triage"
 	"github.com/gocroot/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // ==========================================
@@ -18,61 +34,83 @@ import (
 
 // InsertFeedback godoc
 // @Summary Mengirim Feedback (User Login)
-// @Description User mengirim kritik dan saran (Wajib Login)
+// @Description User mengirim kritik dan saran (Wajib Login). Kirim sebagai multipart/form-data (field name, email, message, category dan attachments) untuk melampirkan PDF atau screenshot PNG/JPEG
 // @Tags Feedback
 // @Accept json
+// @Accept multipart/form-data
 // @Produce json
 // @Param request body model.FeedbackInput true "Payload Feedback"
+// @Param attachments formData file false "Lampiran PDF/PNG/JPEG (boleh lebih dari satu)"
 // @Success 200 {object} model.FeedbackResponse
 // @Failure 400 {object} model.ResponseMessage
 // @Failure 401 {object} model.ResponseMessage
+// @Failure 413 {object} model.ResponseMessage
 // @Router /pdfm/feedback [post]
 // @Security BearerAuth
 func InsertFeedback(w http.ResponseWriter, r *http.Request) {
-	// 1. Setup Header (Standar CORS)
+	// 1. Setup Header
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-	if r.Method == "OPTIONS" {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
-
-	if r.Method != "POST" {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
 
 	// 2. Cek siapa yang login (Sama seperti di history.go)
-	user, err := GetUserFromToken(r)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Unauthorized: " + err.Error()})
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
 		return
 	}
 
 	// 3. Siapkan wadah data
 	var data model.Feedback
 
-	// 4. Decode data dari Frontend
-	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Data tidak valid"})
+	var files []*multipart.FileHeader
+
+	// 4. Decode data dari Frontend: JSON biasa, atau multipart jika ada lampiran
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		limit := int64(config.FeedbackMaxAttachments)*config.FeedbackMaxAttachmentSize + 1<<20
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		if err := r.ParseMultipartForm(8 << 20); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeMessage(w, lang, http.StatusRequestEntityTooLarge, i18n.FeedbackAttachmentsTooLarge)
+				return
+			}
+			writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidBody)
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+		data.Name = r.FormValue("name")
+		data.Email = r.FormValue("email")
+		data.Message = r.FormValue("message")
+		data.Category = r.FormValue("category")
+		files = r.MultipartForm.File["attachments"]
+		if len(files) > config.FeedbackMaxAttachments {
+			writeMessage(w, lang, http.StatusBadRequest, i18n.FeedbackAttachmentLimit, "max", strconv.Itoa(config.FeedbackMaxAttachments))
+			return
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidBody)
 		return
 	}
 
 	// Validasi Pesan
 	if data.Message == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Pesan tidak boleh kosong"})
+		writeMessage(w, lang, http.StatusBadRequest, i18n.FeedbackMessageRequired)
+		return
+	}
+
+	// Kategori dipilih user; status dan prioritas selalu ditentukan server
+	if data.Category != "" && !helpdesk.ValidCategory(data.Category) {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.FeedbackCategoryInvalid)
 		return
 	}
+	data.Category, data.Priority, data.Status = helpdesk.Normalize(data.Category, "", "")
+	data.AssignedTo = primitive.NilObjectID
+	data.AssigneeName = ""
+	data.Replies = nil
+	data.ResolvedAt = time.Time{}
 
 	// 5. Lengkapi data server-side
 	data.ID = primitive.NewObjectID()
 	data.CreatedAt = time.Now()
+	data.UpdatedAt = data.CreatedAt
 
 	// 6. PENTING: Isi data diri otomatis dari Token (Biar aman & valid)
 	data.UserID = user.ID.Hex() // Simpan ID user
@@ -85,13 +123,31 @@ func InsertFeedback(w http.ResponseWriter, r *http.Request) {
 		data.Email = user.Email
 	}
 
+	// Lampiran disimpan di storage backend, dokumen feedback hanya menyimpan metadatanya
+	data.Attachments = nil
+	if len(files) > 0 {
+		attachments, err := storeFeedbackAttachments(r.Context(), data.UserID, files)
+		if err != nil {
+			writeMessageError(w, lang, err)
+			return
+		}
+		data.Attachments = attachments
+	}
+
 	// 7. Simpan ke database "feedback" pakai atdb helper
-	_, err = atdb.InsertOneDoc(config.Mongoconn, "feedback", data)
-	if err != nil {
-		http.Error(w, "Gagal menyimpan feedback: "+err.Error(), http.StatusInternalServerError)
+	if _, err := atdb.InsertOneDoc(config.Mongoconn, "feedback", data); err != nil {
+		deleteFeedbackAttachments(r.Context(), data.Attachments)
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.SaveFailed)
 		return
 	}
 
+	// Triage offline (sentimen, bahasa, keyword, duplikat) tidak menahan response
+	go func(f model.Feedback) {
+		if _, err := analyzeFeedback(context.Background(), f); err != nil {
+			slog.ErrorContext(r.Context(), "gagal menganalisis feedback", "feedback_id", f.ID.Hex(), "error", err)
+		}
+	}(data)
+
 	// 8. Beri respon sukses (Format sama persis dengan history.go)
 	json.NewEncoder(w).Encode(model.FeedbackResponse{
 		Message: "Terima kasih atas masukan Anda!",
@@ -105,55 +161,566 @@ func InsertFeedback(w http.ResponseWriter, r *http.Request) {
 
 // GetAllFeedback godoc
 // @Summary Melihat Semua Feedback (Admin Only)
-// @Description Hanya admin yang bisa melihat daftar feedback
+// @Description Hanya admin yang bisa melihat daftar feedback, terbaru lebih dulu. Bisa difilter dengan status, category, priority, assigned_to (ID admin, "me" atau "none"), sentiment, language dan cluster. Dengan ?view=triage, response berisi agregat sentimen, bahasa, kategori, status, keyword teratas dan cluster duplikat
 // @Tags Feedback
 // @Accept json
 // @Produce json
+// @Param status query string false "new, in_progress, resolved, closed"
+// @Param category query string false "bug, feature, billing, other"
+// @Param priority query string false "low, normal, high, urgent"
+// @Param assigned_to query string false "ID admin, me, atau none"
+// @Param sentiment query string false "positive, neutral, negative (hasil triage otomatis)"
+// @Param language query string false "id atau en (hasil triage otomatis)"
+// @Param cluster query string false "ID cluster near-duplicate"
+// @Param anonymous query bool false "true untuk pesan dari form kontak tanpa login"
+// @Param view query string false "triage untuk tampilan agregat (model.FeedbackTriageResponse) alih-alih daftar"
 // @Success 200 {array} model.Feedback
 // @Failure 401 {object} model.ResponseMessage
 // @Failure 403 {object} model.ResponseMessage
 // @Router /pdfm/feedback [get]
 // @Security BearerAuth
 func GetAllFeedback(w http.ResponseWriter, r *http.Request) {
-	// 1. Setup Header CORS
+	// 1. Setup Header
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
-	if r.Method == "OPTIONS" {
-		w.WriteHeader(http.StatusOK)
+	// 2. Cek Admin Authentication, hanya admin yang boleh lanjut
+	user, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// 3. Ambil feedback sesuai filter dari database
+	filter, err := feedbackFilter(r, user)
+	if err != nil {
+		writeMessageError(w, lang, err)
+		return
+	}
+	if r.URL.Query().Get("view") == "triage" {
+		writeFeedbackTriage(w, lang, filter)
 		return
 	}
+	feedbacks, err := findFeedback(r.Context(), filter)
+	if err != nil {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.FetchFailed)
+		return
+	}
+
+	for i := range feedbacks {
+		withAttachmentURLs(&feedbacks[i])
+	}
+
+	// 4. Return data feedback
+	json.NewEncoder(w).Encode(feedbacks)
+}
+
+var feedbackIndexOnce indexOnce
+
+// ensureFeedbackIndexes membuat index untuk thread milik user dan antrean tiket admin
+func ensureFeedbackIndexes() {
+	feedbackIndexOnce.Do(func() error {
+		_, err := GetMongoCollection("feedback").Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+			{Keys: bson.D{{Key: "assigned_to", Value: 1}, {Key: "status", Value: 1}}},
+		})
+		if err != nil {
+			slog.Error("gagal membuat index feedback", "error", err)
+		}
+		return err
+	})
+}
+
+// legacyMatch mencocokkan field dengan value; jika value adalah nilai default, dokumen lama yang
+// belum punya field tersebut ikut cocok
+func legacyMatch(value, def string) interface{} {
+	if value == def {
+		return bson.M{"$in": bson.A{value, nil}}
+	}
+	return value
+}
+
+// feedbackFilter menyusun filter koleksi feedback dari query string GetAllFeedback
+func feedbackFilter(r *http.Request, admin model.PdfmUsers) (bson.M, error) {
+	q := r.URL.Query()
+	filter := bson.M{}
+	if s := q.Get("status"); s != "" {
+		if !helpdesk.ValidStatus(s) {
+			return nil, newMessageError(http.StatusBadRequest, i18n.FeedbackStatusInvalid)
+		}
+		filter["status"] = legacyMatch(s, helpdesk.StatusNew)
+	}
+	if c := q.Get("category"); c != "" {
+		if !helpdesk.ValidCategory(c) {
+			return nil, newMessageError(http.StatusBadRequest, i18n.FeedbackCategoryInvalid)
+		}
+		filter["category"] = legacyMatch(c, helpdesk.CategoryOther)
+	}
+	if p := q.Get("priority"); p != "" {
+		if !helpdesk.ValidPriority(p) {
+			return nil, newMessageError(http.StatusBadRequest, i18n.FeedbackPriorityInvalid)
+		}
+		filter["priority"] = legacyMatch(p, helpdesk.PriorityNormal)
+	}
+	if s := q.Get("sentiment"); s != "" {
+		if s != triage.Positive && s != triage.Neutral && s != triage.Negative {
+			return nil, newMessageError(http.StatusBadRequest, i18n.FeedbackSentimentInvalid)
+		}
+		filter["triage.sentiment"] = s
+	}
+	if l := q.Get("language"); l != "" {
+		if !i18n.Supported(l) {
+			return nil, newMessageError(http.StatusBadRequest, i18n.FeedbackLanguageInvalid)
+		}
+		filter["triage.language"] = l
+	}
+	if c := q.Get("cluster"); c != "" {
+		id, err := primitive.ObjectIDFromHex(c)
+		if err != nil {
+			return nil, newMessageError(http.StatusBadRequest, i18n.FeedbackClusterInvalid)
+		}
+		filter["triage.cluster_id"] = id
+	}
+	if a := q.Get("anonymous"); a != "" {
+		anonymous, err := strconv.ParseBool(a)
+		if err != nil {
+			return nil, newMessageError(http.StatusBadRequest, i18n.FeedbackAnonymousInvalid)
+		}
+		if anonymous {
+			filter["anonymous"] = true
+		} else {
+			filter["anonymous"] = bson.M{"$ne": true}
+		}
+	}
+	switch a := q.Get("assigned_to"); a {
+	case "":
+	case "me":
+		filter["assigned_to"] = admin.ID
+	case "none":
+		filter["assigned_to"] = bson.M{"$exists": false}
+	default:
+		id, err := primitive.ObjectIDFromHex(a)
+		if err != nil {
+			return nil, newMessageError(http.StatusBadRequest, i18n.FeedbackAssigneeInvalid)
+		}
+		filter["assigned_to"] = id
+	}
+	return filter, nil
+}
+
+// findFeedback mengambil feedback terbaru lebih dulu dengan nilai default ticketing terisi
+func findFeedback(ctx context.Context, filter bson.M) ([]model.Feedback, error) {
+	ensureFeedbackIndexes()
+	cursor, err := GetMongoCollection("feedback").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	feedbacks := []model.Feedback{}
+	if err := cursor.All(ctx, &feedbacks); err != nil {
+		return nil, err
+	}
+	for i := range feedbacks {
+		normalizeFeedback(&feedbacks[i])
+	}
+	return feedbacks, nil
+}
+
+// normalizeFeedback mengisi default untuk feedback yang tersimpan sebelum ada ticketing
+func normalizeFeedback(f *model.Feedback) {
+	f.Category, f.Priority, f.Status = helpdesk.Normalize(f.Category, f.Priority, f.Status)
+	if f.Replies == nil {
+		f.Replies = []model.FeedbackReply{}
+	}
+}
+
+// feedbackForUser mengambil satu feedback dari path; selain admin hanya pemiliknya yang boleh melihat.
+// Error yang dikembalikan adalah messageError.
+func feedbackForUser(user model.PdfmUsers, id string) (model.Feedback, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return model.Feedback{}, newMessageError(http.StatusBadRequest, i18n.InvalidID)
+	}
+	feedback, err := atdb.GetOneDoc[model.Feedback](config.Mongoconn, "feedback", bson.M{"_id": objID})
+	if err != nil || (!user.IsAdmin && feedback.UserID != user.ID.Hex()) {
+		// Feedback milik orang lain diperlakukan seperti tidak ada
+		return model.Feedback{}, newMessageError(http.StatusNotFound, i18n.FeedbackNotFound)
+	}
+	normalizeFeedback(&feedback)
+	if user.IsAdmin {
+		withAttachmentURLs(&feedback)
+	}
+	return feedback, nil
+}
+
+// feedbackStatusSet menambahkan perubahan status ke $set, termasuk waktu resolved
+func feedbackStatusSet(set bson.M, status string, now time.Time) {
+	set["status"] = status
+	if status == helpdesk.StatusResolved {
+		set["resolved_at"] = now
+	}
+}
 
-	if r.Method != "GET" {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// GetMyFeedback godoc
+// @Summary Feedback Saya
+// @Description Daftar feedback yang pernah dikirim user login beserta status dan balasan admin
+// @Tags Feedback
+// @Produce json
+// @Success 200 {object} model.FeedbackListResponse
+// @Failure 401 {object} model.ResponseMessage
+// @Router /pdfm/feedback/mine [get]
+// @Security BearerAuth
+func GetMyFeedback(w http.ResponseWriter, r *http.Request) {
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
 		return
 	}
 
-	// 2. Cek Admin Authentication
-	user, err := GetUserFromToken(r)
+	feedbacks, err := findFeedback(r.Context(), bson.M{"user_id": user.ID.Hex()})
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Unauthorized: " + err.Error()})
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.FetchFailed)
 		return
 	}
+	at.WriteJSON(w, http.StatusOK, model.FeedbackListResponse{
+		Status:    http.StatusOK,
+		Message:   "Feedback retrieved successfully",
+		Feedbacks: feedbacks,
+	})
+}
 
-	// 3. Pastikan user adalah admin
-	if !user.IsAdmin {
-		w.WriteHeader(http.StatusForbidden)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Forbidden: Admin access required"})
+// GetFeedbackThread godoc
+// @Summary Thread Feedback
+// @Description Satu feedback beserta semua balasannya. User hanya bisa membuka feedback miliknya sendiri, admin bisa membuka semua
+// @Tags Feedback
+// @Produce json
+// @Param id path string true "ID feedback"
+// @Success 200 {object} model.FeedbackThreadResponse
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/feedback/{id} [get]
+// @Security BearerAuth
+func GetFeedbackThread(w http.ResponseWriter, r *http.Request) {
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
 		return
 	}
 
-	// 4. Ambil semua feedback dari database
-	var feedbacks []model.Feedback
-	feedbacks, err = atdb.GetAllDoc[[]model.Feedback](config.Mongoconn, "feedback", bson.M{})
+	feedback, err := feedbackForUser(user, at.GetParam(r))
 	if err != nil {
-		http.Error(w, "Gagal mengambil data feedback: "+err.Error(), http.StatusInternalServerError)
+		writeMessageError(w, lang, err)
 		return
 	}
+	at.WriteJSON(w, http.StatusOK, model.FeedbackThreadResponse{
+		Status:   http.StatusOK,
+		Message:  "Feedback retrieved successfully",
+		Feedback: feedback,
+	})
+}
 
-	// 5. Return data feedback
-	json.NewEncoder(w).Encode(feedbacks)
+// UpdateFeedback godoc
+// @Summary Ubah Tiket Feedback (Admin Only)
+// @Description Mengubah status (new -> in_progress -> resolved -> closed, resolved bisa dibuka lagi), kategori, prioritas, atau admin yang ditugaskan
+// @Tags Feedback
+// @Accept json
+// @Produce json
+// @Param id path string true "ID feedback"
+// @Param request body model.FeedbackUpdateInput true "Perubahan tiket"
+// @Success 200 {object} model.FeedbackThreadResponse
+// @Failure 400 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/feedback/{id} [put]
+// @Security BearerAuth
+func UpdateFeedback(w http.ResponseWriter, r *http.Request) {
+	admin, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	feedback, err := feedbackForUser(admin, at.GetParam(r))
+	if err != nil {
+		writeMessageError(w, lang, err)
+		return
+	}
+	var req model.FeedbackUpdateInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidBody)
+		return
+	}
+
+	now := time.Now()
+	set := bson.M{"updated_at": now}
+	unset := bson.M{}
+	switch {
+	case req.Category != "" && !helpdesk.ValidCategory(req.Category):
+		writeMessage(w, lang, http.StatusBadRequest, i18n.FeedbackCategoryInvalid)
+		return
+	case req.Priority != "" && !helpdesk.ValidPriority(req.Priority):
+		writeMessage(w, lang, http.StatusBadRequest, i18n.FeedbackPriorityInvalid)
+		return
+	case req.Status != "":
+		if err := helpdesk.Transition(feedback.Status, req.Status); err != nil {
+			writeError(w, lang, http.StatusBadRequest, i18n.FeedbackTransitionInvalid, err)
+			return
+		}
+	}
+	if req.Category != "" {
+		set["category"] = req.Category
+	}
+	if req.Priority != "" {
+		set["priority"] = req.Priority
+	}
+	if req.Status != "" && req.Status != feedback.Status {
+		feedbackStatusSet(set, req.Status, now)
+	}
+
+	// Penugasan hanya ke akun admin
+	switch req.AssignedTo {
+	case "":
+	case "none":
+		unset["assigned_to"], unset["assignee_name"] = "", ""
+	default:
+		assigneeID := admin.ID
+		if req.AssignedTo != "me" {
+			if assigneeID, err = primitive.ObjectIDFromHex(req.AssignedTo); err != nil {
+				writeMessage(w, lang, http.StatusBadRequest, i18n.FeedbackAssigneeInvalid)
+				return
+			}
+		}
+		assignee, err := atdb.GetOneDoc[model.PdfmUsers](config.Mongoconn, "users", bson.M{"_id": assigneeID, "isAdmin": true})
+		if err != nil {
+			writeMessage(w, lang, http.StatusBadRequest, i18n.FeedbackAssigneeNotFound)
+			return
+		}
+		set["assigned_to"], set["assignee_name"] = assignee.ID, assignee.Name
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	after := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated model.Feedback
+	err = GetMongoCollection("feedback").FindOneAndUpdate(r.Context(), bson.M{"_id": feedback.ID}, update, after).Decode(&updated)
+	if err != nil {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.SaveFailed)
+		return
+	}
+	normalizeFeedback(&updated)
+	withAttachmentURLs(&updated)
+	at.WriteJSON(w, http.StatusOK, model.FeedbackThreadResponse{
+		Status:   http.StatusOK,
+		Message:  "Feedback updated successfully",
+		Feedback: updated,
+	})
+}
+
+// ReplyFeedback godoc
+// @Summary Balas Feedback (Admin Only)
+// @Description Menambah balasan admin ke thread feedback dan mengirim notifikasi ke pengirimnya. Tiket baru otomatis menjadi in_progress kecuali status lain dikirim
+// @Tags Feedback
+// @Accept json
+// @Produce json
+// @Param id path string true "ID feedback"
+// @Param request body model.FeedbackReplyInput true "Balasan"
+// @Success 201 {object} model.FeedbackThreadResponse
+// @Failure 400 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/feedback/{id}/replies [post]
+// @Security BearerAuth
+func ReplyFeedback(w http.ResponseWriter, r *http.Request) {
+	admin, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	feedback, err := feedbackForUser(admin, at.PathParam(r, "/pdfm/feedback/:id/replies", "id"))
+	if err != nil {
+		writeMessageError(w, lang, err)
+		return
+	}
+	var req model.FeedbackReplyInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidBody)
+		return
+	}
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.FeedbackReplyRequired)
+		return
+	}
+	if req.Status == "" && feedback.Status == helpdesk.StatusNew {
+		req.Status = helpdesk.StatusInProgress
+	}
+	if req.Status != "" {
+		if err := helpdesk.Transition(feedback.Status, req.Status); err != nil {
+			writeError(w, lang, http.StatusBadRequest, i18n.FeedbackTransitionInvalid, err)
+			return
+		}
+	}
+
+	now := time.Now()
+	reply := model.FeedbackReply{
+		ID:         primitive.NewObjectID(),
+		AuthorID:   admin.ID,
+		AuthorName: admin.Name,
+		IsAdmin:    true,
+		Message:    req.Message,
+		CreatedAt:  now,
+	}
+	set := bson.M{"updated_at": now}
+	if req.Status != "" && req.Status != feedback.Status {
+		feedbackStatusSet(set, req.Status, now)
+	}
+	after := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated model.Feedback
+	err = GetMongoCollection("feedback").FindOneAndUpdate(r.Context(), bson.M{"_id": feedback.ID},
+		bson.M{"$push": bson.M{"replies": reply}, "$set": set}, after).Decode(&updated)
+	if err != nil {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.SaveFailed)
+		return
+	}
+
+	// Feedback dari form tanpa login tidak punya user_id sehingga tidak ada yang dinotifikasi
+	if submitter, err := primitive.ObjectIDFromHex(updated.UserID); err == nil {
+		err = CreateTemplatedNotification(submitter, "feedback_reply", i18n.NotifFeedbackReply, "message-circle", "",
+			"admin", admin.Name, "excerpt", feedbackExcerpt(req.Message, 80))
+		if err != nil {
+			slog.ErrorContext(r.Context(), "gagal membuat notifikasi balasan feedback", "error", err)
+		}
+	}
+
+	normalizeFeedback(&updated)
+	withAttachmentURLs(&updated)
+	at.WriteJSON(w, http.StatusCreated, model.FeedbackThreadResponse{
+		Status:   http.StatusCreated,
+		Message:  "Reply added successfully",
+		Feedback: updated,
+	})
+}
+
+// feedbackExcerpt memotong pesan menjadi maksimal n karakter untuk teks notifikasi
+func feedbackExcerpt(message string, n int) string {
+	message = strings.Join(strings.Fields(message), " ")
+	if utf8.RuneCountInString(message) <= n {
+		return message
+	}
+	return string([]rune(message)[:n-3]) + "..."
+}
+
+// Purpose file storage untuk lampiran feedback
+const feedbackAttachmentPurpose = "feedback-attachment"
+
+// attachmentTooLarge membuat error 413 untuk lampiran yang melebihi config.FeedbackMaxAttachmentSize
+func attachmentTooLarge(name string) error {
+	return newMessageError(http.StatusRequestEntityTooLarge, i18n.FeedbackAttachmentTooLarge,
+		"name", name, "max", strconv.FormatInt(config.FeedbackMaxAttachmentSize>>20, 10))
+}
+
+// storeFeedbackAttachments memvalidasi lalu menyimpan lampiran ke config.FileStore tanpa masa kedaluwarsa.
+// Jika satu lampiran gagal, lampiran yang sudah tersimpan dihapus lagi. Error yang dikembalikan adalah messageError.
+func storeFeedbackAttachments(ctx context.Context, owner string, files []*multipart.FileHeader) ([]model.FeedbackAttachment, error) {
+	var stored []model.FeedbackAttachment
+	fail := func(err error) ([]model.FeedbackAttachment, error) {
+		deleteFeedbackAttachments(ctx, stored)
+		return nil, err
+	}
+	for _, fh := range files {
+		name := helpdesk.AttachmentName(fh.Filename)
+		if fh.Size > config.FeedbackMaxAttachmentSize {
+			return fail(attachmentTooLarge(name))
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return fail(newMessageError(http.StatusBadRequest, i18n.FeedbackAttachmentReadFailed, "name", name))
+		}
+		data, err := io.ReadAll(io.LimitReader(f, config.FeedbackMaxAttachmentSize+1))
+		f.Close()
+		if err != nil {
+			return fail(newMessageError(http.StatusBadRequest, i18n.FeedbackAttachmentReadFailed, "name", name))
+		}
+		if int64(len(data)) > config.FeedbackMaxAttachmentSize {
+			return fail(attachmentTooLarge(name))
+		}
+		contentType, err := helpdesk.AttachmentType(data)
+		if err != nil {
+			return fail(newMessageError(http.StatusBadRequest, i18n.FeedbackAttachmentType, "name", name))
+		}
+		obj, err := config.FileStore.Put(ctx, storage.Object{
+			Name:        name,
+			ContentType: contentType,
+			Owner:       owner,
+			Purpose:     feedbackAttachmentPurpose,
+		}, data)
+		if err != nil {
+			return fail(newMessageError(http.StatusInternalServerError, i18n.FeedbackAttachmentSaveFailed, "name", name))
+		}
+		stored = append(stored, model.FeedbackAttachment{
+			FileID:      obj.ID,
+			Name:        obj.Name,
+			ContentType: obj.ContentType,
+			Size:        obj.Size,
+		})
+	}
+	return stored, nil
+}
+
+// deleteFeedbackAttachments menghapus isi lampiran dari storage backend
+func deleteFeedbackAttachments(ctx context.Context, attachments []model.FeedbackAttachment) {
+	for _, a := range attachments {
+		if err := config.FileStore.Delete(ctx, a.FileID); err != nil {
+			slog.ErrorContext(ctx, "gagal menghapus lampiran feedback", "file_id", a.FileID, "error", err)
+		}
+	}
+}
+
+// withAttachmentURLs mengisi link unduh lampiran; hanya dipanggil untuk response admin
+func withAttachmentURLs(f *model.Feedback) {
+	for i := range f.Attachments {
+		f.Attachments[i].URL = "/pdfm/feedback/" + f.ID.Hex() + "/attachments/" + f.Attachments[i].FileID
+	}
+}
+
+// DownloadFeedbackAttachment godoc
+// @Summary Unduh Lampiran Feedback (Admin Only)
+// @Description Mengunduh PDF atau screenshot yang dilampirkan user pada feedback
+// @Tags Feedback
+// @Produce application/octet-stream
+// @Param id path string true "ID feedback"
+// @Param file path string true "ID file lampiran"
+// @Success 200 {file} file
+// @Failure 403 {object} model.ResponseMessage
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/feedback/{id}/attachments/{file} [get]
+// @Security BearerAuth
+func DownloadFeedbackAttachment(w http.ResponseWriter, r *http.Request) {
+	admin, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	params, _ := at.PathParams(r.URL.Path, "/pdfm/feedback/:id/attachments/:file")
+	feedback, err := feedbackForUser(admin, params["id"])
+	if err != nil {
+		writeMessageError(w, lang, err)
+		return
+	}
+	attached := false
+	for _, a := range feedback.Attachments {
+		attached = attached || a.FileID == params["file"]
+	}
+	if !attached {
+		writeMessage(w, lang, http.StatusNotFound, i18n.FeedbackAttachmentNotFound)
+		return
+	}
+	obj, data, err := config.FileStore.Get(r.Context(), params["file"])
+	if err != nil || obj.Purpose != feedbackAttachmentPurpose {
+		writeMessage(w, lang, http.StatusNotFound, i18n.FeedbackAttachmentNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", obj.ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": obj.Name}))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
 }
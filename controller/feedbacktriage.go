@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/triage"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// feedbackCorpusWindow dan feedbackCorpusSize membatasi feedback lama yang dibandingkan saat mencari duplikat
+	feedbackCorpusWindow = 90 * 24 * time.Hour
+	feedbackCorpusSize   = 500
+	// feedbackAnalyzeBatch adalah jumlah feedback yang dianalisis ulang per panggilan endpoint analyze
+	feedbackAnalyzeBatch = 500
+)
+
+var (
+	feedbackAnalyzerOnce sync.Once
+	feedbackAnalyzerInst *triage.Analyzer
+)
+
+// feedbackAnalyzer memuat kamus Sastrawi sekali saja saat pertama kali dibutuhkan
+func feedbackAnalyzer() *triage.Analyzer {
+	feedbackAnalyzerOnce.Do(func() {
+		feedbackAnalyzerInst = triage.NewAnalyzer()
+	})
+	return feedbackAnalyzerInst
+}
+
+// analyzeFeedback menjalankan triage offline satu feedback lalu menyimpannya di field triage.
+// Pembandingnya adalah feedback yang sudah dianalisis dan dikirim sebelum feedback ini.
+func analyzeFeedback(ctx context.Context, f model.Feedback) (model.FeedbackTriage, error) {
+	analyzer := feedbackAnalyzer()
+	res := analyzer.Analyze(f.Message)
+	result := model.FeedbackTriage{
+		Language:       res.Language,
+		Sentiment:      res.Sentiment,
+		SentimentScore: res.Score,
+		Keywords:       res.Keywords,
+		Terms:          res.Terms,
+		ClusterID:      f.ID,
+		AnalyzedAt:     time.Now(),
+	}
+	if result.Keywords == nil {
+		result.Keywords, result.Terms = []string{}, []string{}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(feedbackCorpusSize).
+		SetProjection(bson.M{"triage.cluster_id": 1, "triage.terms": 1})
+	cursor, err := GetMongoCollection("feedback").Find(ctx, bson.M{
+		"_id":          bson.M{"$ne": f.ID},
+		"triage.terms": bson.M{"$exists": true, "$ne": bson.A{}},
+		"created_at":   bson.M{"$gte": f.CreatedAt.Add(-feedbackCorpusWindow), "$lte": f.CreatedAt},
+	}, opts)
+	if err != nil {
+		return result, err
+	}
+	var previous []model.Feedback
+	if err := cursor.All(ctx, &previous); err != nil {
+		return result, err
+	}
+	corpus := make([]triage.Document, 0, len(previous))
+	for _, p := range previous {
+		doc := triage.Document{ID: p.ID.Hex(), Terms: p.Triage.Terms}
+		if !p.Triage.ClusterID.IsZero() {
+			doc.Cluster = p.Triage.ClusterID.Hex()
+		}
+		corpus = append(corpus, doc)
+	}
+	if best, ok := analyzer.Nearest(res.Terms, corpus, triage.DuplicateThreshold); ok {
+		result.DuplicateOf, _ = primitive.ObjectIDFromHex(best.ID)
+		result.ClusterID, _ = primitive.ObjectIDFromHex(best.ClusterOf())
+		result.Similarity = best.Score
+	}
+
+	_, err = GetMongoCollection("feedback").UpdateOne(ctx, bson.M{"_id": f.ID}, bson.M{"$set": bson.M{"triage": result}})
+	return result, err
+}
+
+// feedbackTriageSummary mengagregasi feedback yang cocok dengan filter dalam satu pipeline $facet
+func feedbackTriageSummary(filter bson.M) (model.FeedbackTriageSummary, error) {
+	countBy := func(field interface{}, match bson.M) bson.A {
+		stages := bson.A{}
+		if match != nil {
+			stages = append(stages, bson.M{"$match": match})
+		}
+		return append(stages,
+			bson.M{"$group": bson.M{"_id": field, "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		)
+	}
+	analyzed := bson.M{"triage": bson.M{"$exists": true}}
+	status := bson.M{"$ifNull": bson.A{"$status", "new"}}
+
+	type total struct {
+		N int64 `bson:"n"`
+	}
+	type facets struct {
+		Total     []total                 `bson:"total"`
+		Analyzed  []total                 `bson:"analyzed"`
+		Sentiment []model.FeedbackCount   `bson:"sentiment"`
+		Language  []model.FeedbackCount   `bson:"language"`
+		Category  []model.FeedbackCount   `bson:"category"`
+		Status    []model.FeedbackCount   `bson:"status"`
+		Keywords  []model.FeedbackCount   `bson:"keywords"`
+		Clusters  []model.FeedbackCluster `bson:"clusters"`
+	}
+	res, err := atdb.AggregateDoc[facets](config.Mongoconn, "feedback", bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$sort": bson.D{{Key: "created_at", Value: 1}}},
+		bson.M{"$facet": bson.M{
+			"total":     bson.A{bson.M{"$count": "n"}},
+			"analyzed":  bson.A{bson.M{"$match": analyzed}, bson.M{"$count": "n"}},
+			"sentiment": countBy("$triage.sentiment", analyzed),
+			"language":  countBy("$triage.language", analyzed),
+			"category":  countBy(bson.M{"$ifNull": bson.A{"$category", "other"}}, nil),
+			"status":    countBy(status, nil),
+			"keywords": bson.A{
+				bson.M{"$match": analyzed},
+				bson.M{"$unwind": "$triage.keywords"},
+				bson.M{"$group": bson.M{"_id": "$triage.keywords", "count": bson.M{"$sum": 1}}},
+				bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+				bson.M{"$limit": 20},
+			},
+			// Hanya cluster dengan lebih dari satu anggota; sample adalah feedback pertama di cluster
+			"clusters": bson.A{
+				bson.M{"$match": analyzed},
+				bson.M{"$group": bson.M{
+					"_id":      "$triage.cluster_id",
+					"count":    bson.M{"$sum": 1},
+					"sample":   bson.M{"$first": "$message"},
+					"keywords": bson.M{"$first": "$triage.keywords"},
+					"negative": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$triage.sentiment", triage.Negative}}, 1, 0}}},
+					"open":     bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$in": bson.A{status, bson.A{"new", "in_progress"}}}, 1, 0}}},
+					"latest":   bson.M{"$max": "$created_at"},
+					"members":  bson.M{"$push": "$_id"},
+				}},
+				bson.M{"$match": bson.M{"count": bson.M{"$gt": 1}}},
+				bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "latest", Value: -1}}},
+				bson.M{"$limit": 20},
+			},
+		}},
+	})
+	nonNil := func(c []model.FeedbackCount) []model.FeedbackCount {
+		if c == nil {
+			return []model.FeedbackCount{}
+		}
+		return c
+	}
+	summary := model.FeedbackTriageSummary{Clusters: []model.FeedbackCluster{}}
+	var f facets
+	if len(res) > 0 {
+		f = res[0]
+	}
+	if len(f.Total) > 0 {
+		summary.Total = f.Total[0].N
+	}
+	if len(f.Analyzed) > 0 {
+		summary.Analyzed = f.Analyzed[0].N
+	}
+	summary.Sentiment = nonNil(f.Sentiment)
+	summary.Language = nonNil(f.Language)
+	summary.Category = nonNil(f.Category)
+	summary.Status = nonNil(f.Status)
+	summary.Keywords = nonNil(f.Keywords)
+	if f.Clusters != nil {
+		summary.Clusters = f.Clusters
+	}
+	return summary, err
+}
+
+// AnalyzeFeedback godoc
+// @Summary Analisis Ulang Feedback (Admin/Cron)
+// @Description Menjalankan triage (bahasa, sentimen, keyword, cluster duplikat) untuk feedback yang belum dianalisis, dari yang terlama, maksimal 500 per panggilan. Bisa dipanggil cron dengan header X-Cron-Secret
+// @Tags Feedback
+// @Produce json
+// @Success 200 {object} model.FeedbackAnalyzeResult
+// @Failure 403 {object} model.ResponseMessage
+// @Router /pdfm/admin/feedback/analyze [post]
+// @Security BearerAuth
+func AnalyzeFeedback(w http.ResponseWriter, r *http.Request) {
+	lang := requestLocale(r, nil)
+	if !isCronRequest(r) {
+		var ok bool
+		if _, lang, ok = adminFromRequest(w, r); !ok {
+			return
+		}
+	}
+
+	filter := bson.M{"triage": bson.M{"$exists": false}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetLimit(feedbackAnalyzeBatch).
+		SetProjection(bson.M{"message": 1, "created_at": 1})
+	cursor, err := GetMongoCollection("feedback").Find(r.Context(), filter, opts)
+	if err != nil {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.FetchFailed)
+		return
+	}
+	var pending []model.Feedback
+	if err := cursor.All(r.Context(), &pending); err != nil {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.FetchFailed)
+		return
+	}
+
+	// Berurutan dari yang terlama agar feedback berikutnya bisa masuk ke cluster yang baru terbentuk
+	analyzed := 0
+	for _, f := range pending {
+		if _, err := analyzeFeedback(r.Context(), f); err != nil {
+			slog.ErrorContext(r.Context(), "gagal menganalisis feedback", "feedback_id", f.ID.Hex(), "error", err)
+			continue
+		}
+		analyzed++
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.FeedbackAnalyzeResult{
+		Message:  "Analisis selesai",
+		Analyzed: analyzed,
+	})
+}
+
+// writeFeedbackTriage menulis tampilan agregat GetAllFeedback (?view=triage)
+func writeFeedbackTriage(w http.ResponseWriter, lang string, filter bson.M) {
+	summary, err := feedbackTriageSummary(filter)
+	if err != nil {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.FetchFailed)
+		return
+	}
+	json.NewEncoder(w).Encode(model.FeedbackTriageResponse{
+		Status:  http.StatusOK,
+		Message: "Feedback triage retrieved successfully",
+		Summary: summary,
+	})
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/gocroot/helper/atdb"
 	"github.com/gocroot/helper/dokped"
 	"github.com/gocroot/helper/ghupload"
-	"github.com/gocroot/helper/watoken"
 	"github.com/gocroot/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -19,7 +18,7 @@ import (
 
 func AksesFileRepoDraft(w http.ResponseWriter, r *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	payload, err := decodeLoginToken(r)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(r)
@@ -81,7 +80,7 @@ func AksesFileRepoDraft(w http.ResponseWriter, r *http.Request) {
 
 func GetFileDraftSPK(w http.ResponseWriter, r *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	payload, err := decodeLoginToken(r)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(r)
@@ -136,7 +135,7 @@ func GetFileDraftSPK(w http.ResponseWriter, r *http.Request) {
 
 func GetFileDraftSPKT(w http.ResponseWriter, r *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	payload, err := decodeLoginToken(r)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(r)
@@ -191,7 +190,7 @@ func GetFileDraftSPKT(w http.ResponseWriter, r *http.Request) {
 
 func GetFileDraftSPI(w http.ResponseWriter, r *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	payload, err := decodeLoginToken(r)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(r)
@@ -270,7 +269,7 @@ func GetFileDraftSPI(w http.ResponseWriter, r *http.Request) {
 
 func UploadProfilePictureHandler(w http.ResponseWriter, r *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	payload, err := decodeLoginToken(r)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(r)
@@ -336,7 +335,7 @@ func UploadProfilePictureHandler(w http.ResponseWriter, r *http.Request) {
 
 func FileUploadWithParamFileHandler(w http.ResponseWriter, r *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	payload, err := decodeLoginToken(r)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(r)
@@ -409,7 +408,7 @@ func FileUploadWithParamFileHandler(w http.ResponseWriter, r *http.Request) {
 
 func UploadCoverBukuWithParamFileHandler(w http.ResponseWriter, r *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	payload, err := decodeLoginToken(r)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(r)
@@ -492,7 +491,7 @@ func UploadCoverBukuWithParamFileHandler(w http.ResponseWriter, r *http.Request)
 
 func UploadDraftBukuWithParamFileHandler(w http.ResponseWriter, r *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	payload, err := decodeLoginToken(r)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(r)
@@ -576,7 +575,7 @@ func UploadDraftBukuWithParamFileHandler(w http.ResponseWriter, r *http.Request)
 
 func UploadDraftBukuPDFWithParamFileHandler(w http.ResponseWriter, r *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	payload, err := decodeLoginToken(r)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(r)
@@ -659,7 +658,7 @@ func UploadDraftBukuPDFWithParamFileHandler(w http.ResponseWriter, r *http.Reque
 
 func UploadSPKPDFWithParamFileHandler(w http.ResponseWriter, r *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	payload, err := decodeLoginToken(r)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(r)
@@ -742,7 +741,7 @@ func UploadSPKPDFWithParamFileHandler(w http.ResponseWriter, r *http.Request) {
 
 func UploadSPIPDFWithParamFileHandler(w http.ResponseWriter, r *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	payload, err := decodeLoginToken(r)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(r)
@@ -825,7 +824,7 @@ func UploadSPIPDFWithParamFileHandler(w http.ResponseWriter, r *http.Request) {
 
 func UploadSampulBukuPDFWithParamFileHandler(w http.ResponseWriter, r *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	payload, err := decodeLoginToken(r)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(r)
@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/auth"
+	"github.com/gocroot/helper/googleid"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GoogleVerifier memverifikasi ID token di LoginGoogle. Test bisa menggantinya dengan googleid.Static
+// agar berjalan tanpa jaringan.
+var GoogleVerifier googleid.Verifier = googleid.Google
+
+var googleSubIndexOnce indexOnce
+
+func ensureGoogleSubIndex() {
+	googleSubIndexOnce.Do(func() error {
+		_, err := GetMongoCollection("users").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys: bson.D{{Key: "googleSub", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"googleSub": bson.M{"$exists": true}}),
+		})
+		if err != nil {
+			slog.Error("gagal membuat index googleSub", "collection", "users", "error", err)
+		}
+		return err
+	})
+}
+
+// googleAudience mengembalikan client ID untuk memverifikasi ID token
+func googleAudience() (string, error) {
+	if config.GoogleClientID != "" {
+		return config.GoogleClientID, nil
+	}
+	creds, err := atdb.GetOneDoc[auth.GoogleCredential](config.Mongoconn, "credentials", bson.M{})
+	if err != nil {
+		return "", err
+	}
+	if creds.ClientID == "" {
+		return "", errors.New("client_id Google belum dikonfigurasi")
+	}
+	return creds.ClientID, nil
+}
+
+// LoginGoogle godoc
+// @Summary Login dengan Google
+// @Description Login pdfm memakai ID token dari Google Identity Services dan menghasilkan token sesi yang sama dengan /pdfm/login. Akun baru dibuat otomatis pada login pertama. Jika email sudah terdaftar dengan password, kirim juga password akun tersebut untuk menautkannya ke Google (409 jika belum)
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body model.GoogleLoginInput true "ID token Google"
+// @Success 200 {object} model.LoginResponse
+// @Failure 401 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Failure 409 {object} model.ResponseMessage
+// @Router /pdfm/login/google [post]
+func LoginGoogle(w http.ResponseWriter, r *http.Request) {
+	lang := requestLocale(r, nil)
+	var req model.GoogleLoginInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.IDToken) == "" {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidBody)
+		return
+	}
+
+	audience, err := googleAudience()
+	if err != nil {
+		writeError(w, lang, http.StatusServiceUnavailable, i18n.GoogleUnavailable, err)
+		return
+	}
+	identity, err := GoogleVerifier.Verify(r.Context(), req.IDToken, audience)
+	if err != nil {
+		status, code := http.StatusUnauthorized, i18n.GoogleTokenInvalid
+		if errors.Is(err, googleid.ErrEmailUnverify) {
+			status, code = http.StatusForbidden, i18n.GoogleEmailUnverified
+		}
+		writeError(w, lang, status, code, err)
+		return
+	}
+
+	ensureGoogleSubIndex()
+	users := GetMongoCollection("users")
+	var response model.LoginResponse
+
+	// Akun yang sudah tertaut dikenali dari sub, walaupun email Google-nya berubah
+	var user model.PdfmUsers
+	err = users.FindOne(r.Context(), bson.M{"googleSub": identity.Subject}).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		var created bool
+		user, created, err = createGoogleUser(r.Context(), identity)
+		switch {
+		case err != nil:
+		case created:
+			response.Created = true
+		case user.GoogleSub == identity.Subject:
+			// Login bersamaan dengan akun Google yang sama sudah membuat atau menautkan akun ini
+		case user.GoogleSub != "":
+			// Email sama tetapi sudah tertaut ke akun Google lain
+			writeMessage(w, requestLocale(r, &user), http.StatusConflict, i18n.GoogleAccountConflict)
+			return
+		case !user.DeletedAt.IsZero():
+		case user.Password != "" && req.Password != user.Password:
+			// Kepemilikan akun email/password harus dibuktikan sebelum ditautkan
+			code := i18n.GoogleLinkRequired
+			if req.Password != "" {
+				code = i18n.PasswordMismatch
+			}
+			writeMessage(w, requestLocale(r, &user), http.StatusConflict, code)
+			return
+		default:
+			if err = linkGoogleAccount(r.Context(), &user, identity); err == nil {
+				recordAudit(r, &user, auditAccountLink, "user", user.ID.Hex(), map[string]any{"google_email": identity.Email})
+			}
+			response.Linked = true
+		}
+	}
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
+		return
+	}
+	if !user.DeletedAt.IsZero() {
+		writeMessage(w, lang, http.StatusUnauthorized, i18n.LoginInvalid)
+		return
+	}
+	if user.Suspended {
+		writeMessage(w, requestLocale(r, &user), http.StatusForbidden, i18n.AccountSuspended)
+		return
+	}
+
+	startSession(w, r, user, "google", response)
+}
+
+// createGoogleUser membuat akun tanpa password untuk login Google pertama. Email sudah diverifikasi Google.
+// Akun dibuat lewat upsert pada email sehingga pencarian dan pembuatan terjadi dalam satu operasi; jika email
+// sudah terdaftar, created bernilai false dan user berisi akun tersebut untuk ditautkan.
+func createGoogleUser(ctx context.Context, identity googleid.Identity) (user model.PdfmUsers, created bool, err error) {
+	now := time.Now()
+	newUser := model.PdfmUsers{
+		ID:             primitive.NewObjectID(),
+		Name:           identity.Name,
+		Email:          identity.Email,
+		IsVerified:     true,
+		GoogleSub:      identity.Subject,
+		GoogleLinkedAt: now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	users := GetMongoCollection("users")
+	err = users.FindOneAndUpdate(ctx, bson.M{"email": identity.Email}, bson.M{"$setOnInsert": newUser},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&user)
+	if mongo.IsDuplicateKeyError(err) {
+		// Login bersamaan untuk akun Google yang sama sudah membuat akunnya (index unik googleSub)
+		err = users.FindOne(ctx, bson.M{"googleSub": identity.Subject}).Decode(&user)
+		return user, false, err
+	}
+	return user, err == nil && user.ID == newUser.ID, err
+}
+
+// linkGoogleAccount menautkan akun yang sudah ada ke akun Google dan menandai emailnya terverifikasi
+func linkGoogleAccount(ctx context.Context, user *model.PdfmUsers, identity googleid.Identity) error {
+	now := time.Now()
+	res, err := GetMongoCollection("users").UpdateOne(ctx,
+		bson.M{"_id": user.ID, "googleSub": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"googleSub": identity.Subject, "googleLinkedAt": now, "isVerified": true, "updatedAt": now}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("akun sudah tertaut ke Google")
+	}
+	user.GoogleSub, user.GoogleLinkedAt, user.IsVerified = identity.Subject, now, true
+	return nil
+}
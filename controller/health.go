@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/health"
+	"github.com/gocroot/model"
+)
+
+// readyTimeout membatasi lama setiap pemeriksaan readiness
+const readyTimeout = 3 * time.Second
+
+var startedAt = time.Now()
+
+// draining bernilai true setelah server menerima sinyal berhenti
+var draining atomic.Bool
+
+// Drain membuat /readyz gagal agar load balancer berhenti mengirim trafik sebelum server ditutup
+func Drain() {
+	draining.Store(true)
+}
+
+func notDraining(ctx context.Context) error {
+	if draining.Load() {
+		return errors.New("server sedang berhenti")
+	}
+	return nil
+}
+
+// readinessChecks adalah dependensi yang harus siap sebelum instance menerima trafik
+var readinessChecks = []health.Check{
+	{Name: "shutdown", Run: notDraining},
+	{Name: "mongo", Run: pingMongo},
+	{Name: "profile", Run: profileLoaded},
+	{Name: "tempdir", Run: health.TempDirWritable("")},
+	{Name: "pdfcpu", Run: health.PDFCPU},
+}
+
+func pingMongo(ctx context.Context) error {
+	if config.ErrorMongoconn != nil {
+		return config.ErrorMongoconn
+	}
+	return config.Mongoconn.Client().Ping(ctx, nil)
+}
+
+// profileLoaded mencoba memuat profile jika belum ada, lalu memeriksa hasilnya
+func profileLoaded(ctx context.Context) error {
+	config.RefreshEnv()
+	loadedAt, err := config.ProfileStatus()
+	if loadedAt.IsZero() {
+		if err == nil {
+			err = errors.New("profile WhatsAuth belum dimuat")
+		}
+		return err
+	}
+	if config.Profile().Token == "" {
+		return errors.New("profile WhatsAuth tidak memiliki token")
+	}
+	return nil
+}
+
+// GetHealthz godoc
+// @Summary Liveness
+// @Description Selalu 200 selama proses berjalan; tidak memeriksa dependensi
+// @Tags Monitoring
+// @Produce json
+// @Success 200 {object} model.HealthResponse
+// @Router /healthz [get]
+func GetHealthz(w http.ResponseWriter, r *http.Request) {
+	at.WriteJSON(w, http.StatusOK, model.HealthResponse{
+		Status:    health.StatusOK,
+		Uptime:    time.Since(startedAt).Round(time.Second).String(),
+		CheckedAt: time.Now(),
+	})
+}
+
+// GetReadyz godoc
+// @Summary Readiness
+// @Description Memeriksa ping MongoDB, profile WhatsAuth, direktori temp yang bisa ditulisi dan pdfcpu, beserta latensi setiap pemeriksaan. 503 jika ada yang gagal.
+// @Tags Monitoring
+// @Produce json
+// @Success 200 {object} model.HealthResponse
+// @Failure 503 {object} model.HealthResponse
+// @Router /readyz [get]
+func GetReadyz(w http.ResponseWriter, r *http.Request) {
+	report := health.Run(r.Context(), readyTimeout, readinessChecks...)
+	report.Uptime = time.Since(startedAt).Round(time.Second).String()
+	status := http.StatusOK
+	if report.Status != health.StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	at.WriteJSON(w, status, report)
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/gocroot/helper/at"
 	"github.com/gocroot/helper/atdb"
 	"github.com/gocroot/helper/report"
-	"github.com/gocroot/helper/watoken"
 	"github.com/gocroot/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -18,7 +17,7 @@ import (
 // pindahkan task dari to do ke doing
 func PutTaskUser(w http.ResponseWriter, r *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	payload, err := decodeLoginToken(r)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(r)
@@ -76,7 +75,7 @@ func PutTaskUser(w http.ResponseWriter, r *http.Request) {
 // pindahkan task dari doing ke done
 func PostTaskUser(w http.ResponseWriter, r *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	payload, err := decodeLoginToken(r)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(r)
@@ -132,7 +131,7 @@ func PostTaskUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func GetHelpdeskAll(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
@@ -181,7 +180,7 @@ func GetHelpdeskAll(respw http.ResponseWriter, req *http.Request) {
 }
 
 func GetLatestHelpdeskMasuk(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
@@ -216,7 +215,7 @@ func GetLatestHelpdeskMasuk(respw http.ResponseWriter, req *http.Request) {
 }
 
 func GetLatestHelpdeskSelesai(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
@@ -251,7 +250,7 @@ func GetLatestHelpdeskSelesai(respw http.ResponseWriter, req *http.Request) {
 }
 
 func GetTaskDone(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
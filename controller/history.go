@@ -1,542 +1,797 @@
 package controller
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"io"
+	"log/slog"
+	"mime"
 	"net/http"
-	"sort"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gocroot/config"
+	"github.com/gocroot/helper/activity"
+	"github.com/gocroot/helper/at"
 	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/storage"
 	"github.com/gocroot/model"
+	"github.com/jung-kurt/gofpdf"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// ==========================================
-// 1. HANDLER UNTUK MERGE HISTORY
-// ==========================================
+// Semua riwayat tool disimpan di satu koleksi dengan diskriminator "type".
+// Tipe yang valid dan skema detailnya didaftarkan di helper/activity.
+const activityCollection = "activity_log"
+
+// indexOnce menjalankan pembuatan index sampai berhasil satu kali. Berbeda dengan sync.Once, kegagalan
+// (mis. Mongo belum siap saat instance start) tidak ditandai selesai sehingga dicoba lagi pada pemanggilan berikutnya.
+type indexOnce struct {
+	mu   sync.Mutex
+	done bool
+}
+
+// Do memanggil create jika index belum pernah berhasil dibuat; done hanya diset jika create tidak mengembalikan error
+func (o *indexOnce) Do(create func() error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.done {
+		return
+	}
+	o.done = create() == nil
+}
+
+var activityIndexOnce indexOnce
+
+// ensureActivityIndexes membuat index yang dipakai listing riwayat (idempotent, sampai berhasil sekali per instance)
+func ensureActivityIndexes() {
+	activityIndexOnce.Do(func() error {
+		_, err := config.Mongoconn.Collection(activityCollection).Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "type", Value: 1}, {Key: "created_at", Value: -1}}},
+		})
+		if err != nil {
+			slog.Error("gagal membuat index", "collection", "activity_log", "error", err)
+		}
+		return err
+	})
+}
+
+// LogActivity mencatat satu aktivitas. a minimal berisi UserID dan Type;
+// Inputs, Output dan RerunOf opsional. Dipakai handler log maupun proses internal (rerun).
+func LogActivity(a model.Activity, details interface{}) (model.Activity, error) {
+	tool, err := activity.Lookup(a.Type)
+	if err != nil {
+		return model.Activity{}, err
+	}
+	if err := tool.Validate(details); err != nil {
+		return model.Activity{}, err
+	}
+	doc, err := activity.Encode(details)
+	if err != nil {
+		return model.Activity{}, err
+	}
+	a.ID = primitive.NewObjectID()
+	a.FileName = tool.FileName(details)
+	a.Details = doc
+	a.CreatedAt = time.Now()
+	ensureActivityIndexes()
+	_, err = atdb.InsertOneDoc(config.Mongoconn, activityCollection, a)
+	return a, err
+}
 
-// CreateMergeHistory godoc
-// @Summary Simpan Log Merge PDF
-// @Description Mencatat riwayat penggabungan PDF ke database
-// @Tags History - Merge
+// CreateActivity godoc
+// @Summary Simpan Log Aktivitas Tool
+// @Description Mencatat riwayat pemakaian tool (merge, compress, convert, summary). Body mengikuti skema detail tipe tersebut, mis. model.MergeDetails.
+// @Description Field opsional "inputs" berisi ID file dari POST /pdfm/files agar operasi bisa dijalankan ulang.
+// @Tags History
 // @Accept json
 // @Produce json
-// @Param request body model.MergeInput true "Payload Data Merge"
+// @Param type path string true "Tipe aktivitas (merge, compress, convert, summary)"
+// @Param request body model.MergeDetails true "Payload detail sesuai tipe"
 // @Success 200 {object} model.HistoryActionResponse
+// @Failure 400 {object} model.ResponseMessage
 // @Failure 401 {object} model.ResponseMessage
-// @Router /pdfm/log/merge [post]
+// @Router /pdfm/log/{type} [post]
 // @Security BearerAuth
-func CreateMergeHistory(w http.ResponseWriter, r *http.Request) {
-	user, err := GetUserFromToken(r)
+func CreateActivity(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	tool, err := activity.Lookup(at.GetParam(r))
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Unauthorized: " + err.Error()})
+		writeMessage(w, lang, http.StatusBadRequest, i18n.HistoryTypeInvalid)
 		return
 	}
 
-	var req model.MergeInput
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Data tidak valid"})
+	var body json.RawMessage
+	var refs struct {
+		Inputs []string `json:"inputs"`
+	}
+	details := tool.NewDetails()
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || json.Unmarshal(body, details) != nil || json.Unmarshal(body, &refs) != nil {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidBody)
 		return
 	}
 
-	data := model.MergeHistory{
-		ID:         primitive.NewObjectID(),
-		UserID:     user.ID,
-		InputFiles: req.InputFiles,
-		OutputFile: req.OutputFile,
-		CreatedAt:  time.Now(),
+	// File input harus milik user sendiri dan diunggah sebagai input tool
+	for _, id := range refs.Inputs {
+		obj, err := config.FileStore.Stat(r.Context(), id)
+		if err != nil || obj.Owner != user.ID.Hex() || obj.Purpose != fileInputPurpose {
+			writeMessage(w, lang, http.StatusBadRequest, i18n.FileInputInvalid, "id", id)
+			return
+		}
+	}
+
+	if err := tool.Validate(details); err != nil {
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidBody, err)
+		return
 	}
 
-	_, err = atdb.InsertOneDoc(config.Mongoconn, "merge_history", data)
+	data, err := LogActivity(model.Activity{UserID: user.ID, Type: tool.Type, Inputs: refs.Inputs}, details)
 	if err != nil {
-		http.Error(w, "Gagal menyimpan data", http.StatusInternalServerError)
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.SaveFailed)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	// PERBAIKAN: Gunakan HistoryActionResponse
 	json.NewEncoder(w).Encode(model.HistoryActionResponse{
-		Message: "Log Merge berhasil disimpan",
+		Message: "Log " + tool.Type + " berhasil disimpan",
 		ID:      data.ID,
 	})
 }
 
-// GetMergeHistory godoc
-// @Summary Lihat Riwayat Merge
-// @Description Menampilkan daftar riwayat merge user
-// @Tags History - Merge
+// GetActivities godoc
+// @Summary Lihat Riwayat per Tipe
+// @Description Menampilkan daftar riwayat user untuk satu tipe tool. Field detail diratakan ke level atas (kompatibel dengan format lama)
+// @Tags History
 // @Accept json
 // @Produce json
-// @Success 200 {array} model.MergeHistory
+// @Param type path string true "Tipe aktivitas (merge, compress, convert, summary)"
+// @Success 200 {array} map[string]interface{}
 // @Failure 401 {object} model.ResponseMessage
-// @Router /pdfm/log/merge [get]
+// @Router /pdfm/log/{type} [get]
 // @Security BearerAuth
-func GetMergeHistory(w http.ResponseWriter, r *http.Request) {
-	user, err := GetUserFromToken(r)
+func GetActivities(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	tool, err := activity.Lookup(at.GetParam(r))
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Unauthorized"})
+		writeMessage(w, lang, http.StatusBadRequest, i18n.HistoryTypeInvalid)
 		return
 	}
-	data, err := atdb.GetAllDoc[[]model.MergeHistory](config.Mongoconn, "merge_history", bson.M{"user_id": user.ID})
+
+	data, err := atdb.GetAllDoc[[]model.Activity](config.Mongoconn, activityCollection, bson.M{"user_id": user.ID, "type": tool.Type})
 	if err != nil {
-		http.Error(w, "Failed to fetch data", http.StatusInternalServerError)
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.FetchFailed)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
+
+	items := []map[string]interface{}{}
+	for _, a := range data {
+		item := map[string]interface{}{}
+		for k, v := range a.Details {
+			item[k] = v
+		}
+		item["id"] = a.ID
+		item["user_id"] = a.UserID
+		item["created_at"] = a.CreatedAt
+		items = append(items, item)
+	}
+	json.NewEncoder(w).Encode(items)
 }
 
 // ==========================================
-// 2. HANDLER UNTUK COMPRESS HISTORY
+// UNIFIED HISTORY
 // ==========================================
 
-// CreateCompressHistory godoc
-// @Summary Simpan Log Compress PDF
-// @Tags History - Compress
+// GetAllHistory godoc
+// @Summary Lihat Semua Riwayat (Gabungan)
+// @Description Menampilkan semua jenis riwayat dari activity_log dengan filter dan cursor pagination
+// @Tags History - Unified
 // @Accept json
 // @Produce json
-// @Param request body model.CompressInput true "Payload Data Compress"
-// @Success 200 {object} model.HistoryActionResponse
+// @Param type query string false "Filter tipe, pisahkan dengan koma (merge,compress,convert,summary)"
+// @Param from query string false "Tanggal awal (YYYY-MM-DD atau RFC3339)"
+// @Param to query string false "Tanggal akhir (YYYY-MM-DD atau RFC3339)"
+// @Param q query string false "Cari berdasarkan nama file"
+// @Param sort query string false "newest (default), oldest, name_asc, name_desc"
+// @Param limit query int false "Jumlah item per halaman (default 20, maks 100)"
+// @Param cursor query string false "Cursor dari response sebelumnya (next_cursor)"
+// @Success 200 {object} model.UnifiedHistoryResponse
+// @Failure 400 {object} model.ResponseMessage
 // @Failure 401 {object} model.ResponseMessage
-// @Router /pdfm/log/compress [post]
+// @Router /pdfm/history/all [get]
 // @Security BearerAuth
-func CreateCompressHistory(w http.ResponseWriter, r *http.Request) {
-	user, err := GetUserFromToken(r)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Unauthorized"})
-		return
-	}
+func GetAllHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
 
-	var req model.CompressInput
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Data tidak valid"})
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
 		return
 	}
 
-	data := model.CompressHistory{
-		ID:             primitive.NewObjectID(),
-		UserID:         user.ID,
-		FileName:       req.FileName,
-		OriginalSize:   req.OriginalSize,
-		CompressedSize: req.CompressedSize,
-		Status:         req.Status,
-		CreatedAt:      time.Now(),
+	q := r.URL.Query()
+	limit := paging.Limit(q.Get("limit"), 20, 100)
+	sortBy := q.Get("sort")
+	if sortBy == "" {
+		sortBy = "newest"
+	}
+	if sortBy != "newest" && sortBy != "oldest" && sortBy != "name_asc" && sortBy != "name_desc" {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.HistorySortInvalid)
+		return
 	}
 
-	_, err = atdb.InsertOneDoc(config.Mongoconn, "compress_history", data)
+	filter, err := historyFilter(user.ID, q.Get("type"), q.Get("from"), q.Get("to"), q.Get("q"))
 	if err != nil {
-		http.Error(w, "Gagal menyimpan data", http.StatusInternalServerError)
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidQuery, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	// PERBAIKAN: Gunakan HistoryActionResponse (Sekarang Compress juga return ID)
-	json.NewEncoder(w).Encode(model.HistoryActionResponse{
-		Message: "Log Compress berhasil disimpan",
-		ID:      data.ID,
-	})
-}
+	if raw := q.Get("cursor"); raw != "" {
+		cur, err := paging.Decode(raw)
+		if err == nil {
+			var cursorMatch bson.M
+			cursorMatch, err = historyCursorFilter(sortBy, cur)
+			filter = bson.M{"$and": bson.A{filter, cursorMatch}}
+		}
+		if err != nil {
+			writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidCursor)
+			return
+		}
+	}
 
-// GetCompressHistory godoc
-// @Summary Lihat Riwayat Compress
-// @Tags History - Compress
-// @Accept json
-// @Produce json
-// @Success 200 {array} model.CompressHistory
-// @Failure 401 {object} model.ResponseMessage
-// @Router /pdfm/log/compress [get]
-// @Security BearerAuth
-func GetCompressHistory(w http.ResponseWriter, r *http.Request) {
-	user, err := GetUserFromToken(r)
+	ensureActivityIndexes()
+	opts := options.Find().SetSort(historySort(sortBy)).SetLimit(int64(limit + 1))
+	cursor, err := config.Mongoconn.Collection(activityCollection).Find(r.Context(), filter, opts)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Unauthorized"})
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.FetchFailed)
 		return
 	}
-	data, err := atdb.GetAllDoc[[]model.CompressHistory](config.Mongoconn, "compress_history", bson.M{"user_id": user.ID})
-	if err != nil {
-		http.Error(w, "Failed to fetch data", http.StatusInternalServerError)
+	var rows []model.Activity
+	if err := cursor.All(r.Context(), &rows); err != nil {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.FetchFailed)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
-}
-
-// ==========================================
-// 3. HANDLER UNTUK CONVERT HISTORY
-// ==========================================
 
-// CreateConvertHistory godoc
-// @Summary Simpan Log Convert PDF
-// @Tags History - Convert
-// @Accept json
-// @Produce json
-// @Param request body model.ConvertInput true "Payload Data Convert"
-// @Success 200 {object} model.HistoryActionResponse
-// @Failure 401 {object} model.ResponseMessage
-// @Router /pdfm/log/convert [post]
-// @Security BearerAuth
-func CreateConvertHistory(w http.ResponseWriter, r *http.Request) {
-	user, err := GetUserFromToken(r)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Unauthorized"})
-		return
+	response := model.UnifiedHistoryResponse{
+		Status:  200,
+		Message: "History retrieved successfully",
+		History: []model.HistoryItem{},
+	}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[limit-1]
+		response.HasMore = true
+		response.NextCursor = paging.Encode(paging.Cursor{Time: last.CreatedAt, Text: last.FileName, ID: last.ID.Hex()})
+	}
+	for _, a := range rows {
+		response.History = append(response.History, toHistoryItem(a))
 	}
 
-	var req model.ConvertInput
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Data tidak valid"})
-		return
+	json.NewEncoder(w).Encode(response)
+}
+
+// historyFilter membangun filter activity_log dari parameter query listing riwayat
+func historyFilter(userID primitive.ObjectID, types, from, to, search string) (bson.M, error) {
+	filter := bson.M{"user_id": userID}
+
+	if types != "" {
+		var list []string
+		for _, t := range strings.Split(types, ",") {
+			t = strings.TrimSpace(t)
+			if _, err := activity.Lookup(t); err != nil {
+				return nil, errors.New("Invalid history type: " + t)
+			}
+			list = append(list, t)
+		}
+		filter["type"] = bson.M{"$in": list}
 	}
 
-	data := model.ConvertHistory{
-		ID:           primitive.NewObjectID(),
-		UserID:       user.ID,
-		FileName:     req.FileName,
-		SourceFormat: req.SourceFormat,
-		TargetFormat: req.TargetFormat,
-		CreatedAt:    time.Now(),
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	createdAt := bson.M{}
+	if from != "" {
+		t, err := paging.ParseDate(from, loc, false)
+		if err != nil {
+			return nil, errors.New("Format tanggal 'from' tidak valid")
+		}
+		createdAt["$gte"] = t
+	}
+	if to != "" {
+		t, err := paging.ParseDate(to, loc, true)
+		if err != nil {
+			return nil, errors.New("Format tanggal 'to' tidak valid")
+		}
+		createdAt["$lte"] = t
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
 	}
 
-	_, err = atdb.InsertOneDoc(config.Mongoconn, "convert_history", data)
-	if err != nil {
-		http.Error(w, "Gagal menyimpan data", http.StatusInternalServerError)
-		return
+	if search != "" {
+		filter["file_name"] = bson.M{"$regex": regexp.QuoteMeta(search), "$options": "i"}
 	}
+	return filter, nil
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	// PERBAIKAN: Gunakan HistoryActionResponse
-	json.NewEncoder(w).Encode(model.HistoryActionResponse{
-		Message: "Log Convert berhasil disimpan",
-		ID:      data.ID,
-	})
+// historySort mengembalikan urutan sort sesuai parameter sort
+func historySort(sortBy string) bson.D {
+	switch sortBy {
+	case "oldest":
+		return bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}
+	case "name_asc":
+		return bson.D{{Key: "file_name", Value: 1}, {Key: "_id", Value: 1}}
+	case "name_desc":
+		return bson.D{{Key: "file_name", Value: -1}, {Key: "_id", Value: -1}}
+	default:
+		return bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+	}
 }
 
-// GetConvertHistory godoc
-// @Summary Lihat Riwayat Convert
-// @Tags History - Convert
-// @Accept json
-// @Produce json
-// @Success 200 {array} model.ConvertHistory
-// @Failure 401 {object} model.ResponseMessage
-// @Router /pdfm/log/convert [get]
-// @Security BearerAuth
-func GetConvertHistory(w http.ResponseWriter, r *http.Request) {
-	user, err := GetUserFromToken(r)
+// historyCursorFilter membuat filter "setelah item terakhir" sesuai arah urutan
+func historyCursorFilter(sortBy string, cur paging.Cursor) (bson.M, error) {
+	id, err := primitive.ObjectIDFromHex(cur.ID)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Unauthorized"})
-		return
+		return nil, paging.ErrInvalidCursor
+	}
+	field, op := "created_at", "$lt"
+	var value interface{} = cur.Time
+	switch sortBy {
+	case "oldest":
+		op = "$gt"
+	case "name_asc":
+		field, op, value = "file_name", "$gt", cur.Text
+	case "name_desc":
+		field, value = "file_name", cur.Text
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{op: value}},
+		bson.M{field: value, "_id": bson.M{op: id}},
+	}}, nil
+}
+
+// toHistoryItem mengubah entri activity_log menjadi item response dengan deskripsi dari registry
+func toHistoryItem(a model.Activity) model.HistoryItem {
+	item := model.HistoryItem{
+		ID:          a.ID.Hex(),
+		Type:        a.Type,
+		Description: a.Type,
+		FileName:    a.FileName,
+		Details:     a.Details,
+		CreatedAt:   a.CreatedAt,
 	}
-	data, err := atdb.GetAllDoc[[]model.ConvertHistory](config.Mongoconn, "convert_history", bson.M{"user_id": user.ID})
-	if err != nil {
-		http.Error(w, "Failed to fetch data", http.StatusInternalServerError)
-		return
+	if tool, err := activity.Lookup(a.Type); err == nil {
+		item.Description = tool.Description(a.Details)
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
+	return item
 }
 
 // ==========================================
-// 4. HANDLER UNTUK SUMMARY HISTORY
+// DELETE HISTORY ITEM
 // ==========================================
 
-// CreateSummaryHistory godoc
-// @Summary Simpan Log Summary PDF
-// @Tags History - Summary
+// DeleteHistory godoc
+// @Summary Hapus Riwayat
+// @Description Menghapus satu item riwayat berdasarkan ID
+// @Tags History - Unified
 // @Accept json
 // @Produce json
-// @Param request body model.SummaryInput true "Payload Data Summary"
-// @Success 200 {object} model.HistoryActionResponse
-// @Failure 401 {object} model.ResponseMessage
-// @Router /pdfm/log/summary [post]
+// @Param request body model.DeleteHistoryInput true "Payload Hapus History"
+// @Success 200 {object} model.ResponseMessage
+// @Failure 400 {object} model.ResponseMessage
+// @Router /pdfm/history/delete [delete]
 // @Security BearerAuth
-func CreateSummaryHistory(w http.ResponseWriter, r *http.Request) {
-	user, err := GetUserFromToken(r)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Unauthorized"})
+func DeleteHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
 		return
 	}
 
-	var req model.SummaryInput
+	var req model.DeleteHistoryInput
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Data tidak valid"})
+		writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidBody)
 		return
 	}
 
-	data := model.SummaryHistory{
-		ID:          primitive.NewObjectID(),
-		UserID:      user.ID,
-		FileName:    req.FileName,
-		SummaryText: req.SummaryText,
-		Language:    req.Language,
-		CreatedAt:   time.Now(),
+	if req.ID == "" {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.IDRequired)
+		return
 	}
 
-	_, err = atdb.InsertOneDoc(config.Mongoconn, "summary_history", data)
+	objectID, err := primitive.ObjectIDFromHex(req.ID)
 	if err != nil {
-		http.Error(w, "Gagal menyimpan data", http.StatusInternalServerError)
+		writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidID)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	// PERBAIKAN: Gunakan HistoryActionResponse
-	json.NewEncoder(w).Encode(model.HistoryActionResponse{
-		Message: "Log Summary berhasil disimpan",
-		ID:      data.ID,
-	})
-}
+	filter := bson.M{"_id": objectID, "user_id": user.ID}
+	if req.Type != "" {
+		if _, err := activity.Lookup(req.Type); err != nil {
+			writeMessage(w, lang, http.StatusBadRequest, i18n.HistoryTypeInvalid)
+			return
+		}
+		filter["type"] = req.Type
+	}
 
-// GetSummaryHistory godoc
-// @Summary Lihat Riwayat Summary
-// @Tags History - Summary
-// @Accept json
-// @Produce json
-// @Success 200 {array} model.SummaryHistory
-// @Failure 401 {object} model.ResponseMessage
-// @Router /pdfm/log/summary [get]
-// @Security BearerAuth
-func GetSummaryHistory(w http.ResponseWriter, r *http.Request) {
-	user, err := GetUserFromToken(r)
+	result, err := atdb.DeleteOneDoc(config.Mongoconn, activityCollection, filter)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Unauthorized"})
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.DeleteFailed)
 		return
 	}
-	data, err := atdb.GetAllDoc[[]model.SummaryHistory](config.Mongoconn, "summary_history", bson.M{"user_id": user.ID})
-	if err != nil {
-		http.Error(w, "Failed to fetch data", http.StatusInternalServerError)
+
+	if result.DeletedCount == 0 {
+		writeMessage(w, lang, http.StatusNotFound, i18n.HistoryNotFound)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
+
+	recordAudit(r, &user, auditHistoryDelete, "activity", objectID.Hex(), map[string]any{"type": req.Type})
+	writeMessage(w, lang, http.StatusOK, i18n.HistoryDeleted)
 }
 
 // ==========================================
-// 5. UNIFIED HISTORY
+// EXPORT & RERUN
 // ==========================================
 
-// GetAllHistory godoc
-// @Summary Lihat Semua Riwayat (Gabungan)
-// @Description Menggabungkan semua jenis riwayat (Merge, Compress, Convert, Summary) menjadi satu list
+// ExportHistory godoc
+// @Summary Export Seluruh Riwayat
+// @Description Mengunduh seluruh riwayat aktivitas user dalam format csv, json atau pdf
 // @Tags History - Unified
-// @Accept json
+// @Produce text/csv
 // @Produce json
-// @Success 200 {object} model.UnifiedHistoryResponse
+// @Produce application/pdf
+// @Param format query string false "csv (default), json, pdf"
+// @Success 200 {file} file
+// @Failure 400 {object} model.ResponseMessage
 // @Failure 401 {object} model.ResponseMessage
-// @Router /pdfm/history/all [get]
+// @Router /pdfm/history/export [get]
 // @Security BearerAuth
-func GetAllHistory(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-
-	user, err := GetUserFromToken(r)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Unauthorized: " + err.Error()})
-		return
-	}
-
-	var allHistory []model.HistoryItem
-
-	// 1. Ambil Merge History
-	mergeData, err := atdb.GetAllDoc[[]model.MergeHistory](config.Mongoconn, "merge_history", bson.M{"user_id": user.ID})
-	if err == nil && mergeData != nil {
-		for _, m := range mergeData {
-			fileCount := len(m.InputFiles)
-			allHistory = append(allHistory, model.HistoryItem{
-				ID:          m.ID.Hex(),
-				Type:        "merge",
-				Description: "Merged " + string(rune(fileCount+'0')) + " PDF files",
-				FileName:    m.OutputFile,
-				Details:     map[string]interface{}{"input_files": m.InputFiles},
-				CreatedAt:   m.CreatedAt,
-			})
-		}
+func ExportHistory(w http.ResponseWriter, r *http.Request) {
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
 	}
 
-	// 2. Ambil Compress History
-	compressData, err := atdb.GetAllDoc[[]model.CompressHistory](config.Mongoconn, "compress_history", bson.M{"user_id": user.ID})
-	if err == nil && compressData != nil {
-		for _, c := range compressData {
-			allHistory = append(allHistory, model.HistoryItem{
-				ID:          c.ID.Hex(),
-				Type:        "compress",
-				Description: "Compressed PDF file",
-				FileName:    c.FileName,
-				Details: map[string]interface{}{
-					"original_size":   c.OriginalSize,
-					"compressed_size": c.CompressedSize,
-					"status":          c.Status,
-				},
-				CreatedAt: c.CreatedAt,
-			})
-		}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" && format != "pdf" {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.ExportFormatInvalid)
+		return
 	}
 
-	// 3. Ambil Convert History
-	convertData, err := atdb.GetAllDoc[[]model.ConvertHistory](config.Mongoconn, "convert_history", bson.M{"user_id": user.ID})
-	if err == nil && convertData != nil {
-		for _, cv := range convertData {
-			allHistory = append(allHistory, model.HistoryItem{
-				ID:          cv.ID.Hex(),
-				Type:        "convert",
-				Description: "Converted " + cv.SourceFormat + " to " + cv.TargetFormat,
-				FileName:    cv.FileName,
-				Details: map[string]interface{}{
-					"source_format": cv.SourceFormat,
-					"target_format": cv.TargetFormat,
-				},
-				CreatedAt: cv.CreatedAt,
-			})
+	ensureActivityIndexes()
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	cur, err := config.Mongoconn.Collection(activityCollection).Find(r.Context(), bson.M{"user_id": user.ID}, opts)
+	if err != nil {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.FetchFailed)
+		return
+	}
+	defer cur.Close(r.Context())
+
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	filename := "pdfm-history-" + time.Now().In(loc).Format("20060102") + "." + format
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+
+	switch format {
+	case "csv":
+		// CSV dan JSON ditulis sambil membaca cursor agar riwayat panjang tidak dimuat sekaligus
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"id", "type", "file_name", "description", "created_at", "details"})
+		for cur.Next(r.Context()) {
+			var a model.Activity
+			if cur.Decode(&a) != nil {
+				continue
+			}
+			item := toHistoryItem(a)
+			details, _ := json.Marshal(item.Details)
+			cw.Write([]string{item.ID, item.Type, item.FileName, item.Description, item.CreatedAt.In(loc).Format(time.RFC3339), string(details)})
+		}
+		cw.Flush()
+
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		w.Write([]byte("["))
+		first := true
+		for cur.Next(r.Context()) {
+			var a model.Activity
+			if cur.Decode(&a) != nil {
+				continue
+			}
+			if !first {
+				w.Write([]byte(","))
+			}
+			first = false
+			enc.Encode(toHistoryItem(a))
+		}
+		w.Write([]byte("]"))
+
+	case "pdf":
+		var items []model.HistoryItem
+		for cur.Next(r.Context()) {
+			var a model.Activity
+			if cur.Decode(&a) == nil {
+				items = append(items, toHistoryItem(a))
+			}
+		}
+		var buf bytes.Buffer
+		if err := historyPDF(&buf, user, items, loc); err != nil {
+			w.Header().Del("Content-Disposition")
+			writeMessage(w, lang, http.StatusInternalServerError, i18n.ExportFailed)
+			return
 		}
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		w.Write(buf.Bytes())
 	}
+}
 
-	// 4. Ambil Summary History
-	summaryData, err := atdb.GetAllDoc[[]model.SummaryHistory](config.Mongoconn, "summary_history", bson.M{"user_id": user.ID})
-	if err == nil && summaryData != nil {
-		for _, s := range summaryData {
-			allHistory = append(allHistory, model.HistoryItem{
-				ID:          s.ID.Hex(),
-				Type:        "summary",
-				Description: "Generated PDF summary",
-				FileName:    s.FileName,
-				Details: map[string]interface{}{
-					"language": s.Language,
-				},
-				CreatedAt: s.CreatedAt,
-			})
+// historyPDF menulis riwayat sebagai tabel sederhana A4 landscape
+func historyPDF(out io.Writer, user model.PdfmUsers, items []model.HistoryItem, loc *time.Location) error {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle("Riwayat PDFM - "+user.Name, true)
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 14)
+	pdf.Cell(0, 10, tr("Riwayat Aktivitas PDFM - "+user.Name))
+	pdf.Ln(8)
+	pdf.SetFont("Arial", "", 9)
+	pdf.Cell(0, 6, "Dibuat "+time.Now().In(loc).Format("02 Jan 2006 15:04")+" WIB, total "+strconv.Itoa(len(items))+" aktivitas")
+	pdf.Ln(10)
+
+	widths := []float64{38, 22, 95, 122}
+	header := []string{"Waktu (WIB)", "Tipe", "Nama File", "Keterangan"}
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	for i, h := range header {
+		pdf.CellFormat(widths[i], 7, h, "1", 0, "L", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Arial", "", 8)
+	fit := func(s string, width float64) string {
+		s = tr(s)
+		for len(s) > 0 && pdf.GetStringWidth(s) > width-2 {
+			s = s[:len(s)-1]
 		}
+		return s
 	}
-
-	// Sort by CreatedAt descending
-	sort.Slice(allHistory, func(i, j int) bool {
-		return allHistory[i].CreatedAt.After(allHistory[j].CreatedAt)
-	})
-
-	if allHistory == nil {
-		allHistory = []model.HistoryItem{}
+	for _, item := range items {
+		row := []string{item.CreatedAt.In(loc).Format("2006-01-02 15:04"), item.Type, item.FileName, item.Description}
+		for i, col := range row {
+			pdf.CellFormat(widths[i], 6, fit(col, widths[i]), "1", 0, "L", false, 0, "")
+		}
+		pdf.Ln(-1)
 	}
-
-	// PERBAIKAN: Gunakan UnifiedHistoryResponse
-	json.NewEncoder(w).Encode(model.UnifiedHistoryResponse{
-		Status:  200,
-		Message: "History retrieved successfully",
-		History: allHistory,
-	})
+	return pdf.Output(out)
 }
 
-// ==========================================
-// 6. DELETE HISTORY ITEM
-// ==========================================
-
-// DeleteHistory godoc
-// @Summary Hapus Riwayat
-// @Description Menghapus satu item riwayat berdasarkan ID dan Tipe
+// RerunHistory godoc
+// @Summary Jalankan Ulang Operasi
+// @Description Menjalankan ulang aktivitas lama dengan parameter yang sama selama file input masih dalam masa retensi. Gagal dengan 410 "inputs expired" jika file input sudah tidak tersedia
 // @Tags History - Unified
-// @Accept json
 // @Produce json
-// @Param request body model.DeleteHistoryInput true "Payload Hapus History"
-// @Success 200 {object} model.ResponseMessage
-// @Failure 400 {object} model.ResponseMessage
-// @Router /pdfm/history/delete [delete]
+// @Param id path string true "ID aktivitas"
+// @Success 200 {object} model.RerunResponse
+// @Failure 404 {object} model.ResponseMessage
+// @Failure 410 {object} model.ResponseMessage
+// @Failure 422 {object} model.ResponseMessage
+// @Router /pdfm/history/{id}/rerun [post]
 // @Security BearerAuth
-func DeleteHistory(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
+func RerunHistory(w http.ResponseWriter, r *http.Request) {
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
 
-	user, err := GetUserFromToken(r)
+	objectID, err := primitive.ObjectIDFromHex(at.PathParam(r, "/pdfm/history/:id/rerun", "id"))
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Unauthorized: " + err.Error()})
+		writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidID)
 		return
 	}
 
-	var req model.DeleteHistoryInput
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Invalid request body"})
+	prev, err := atdb.GetOneDoc[model.Activity](config.Mongoconn, activityCollection, bson.M{"_id": objectID, "user_id": user.ID})
+	if err != nil {
+		writeMessage(w, lang, http.StatusNotFound, i18n.HistoryNotFound)
 		return
 	}
 
-	if req.ID == "" || req.Type == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "ID and type are required"})
+	tool, err := activity.Lookup(prev.Type)
+	if err != nil || tool.Run == nil {
+		writeMessage(w, lang, http.StatusUnprocessableEntity, i18n.RerunUnsupported, "type", prev.Type)
+		return
+	}
+	if !apiKeyAllows(r, tool.Type) {
+		writeMessage(w, lang, http.StatusForbidden, i18n.APIKeyScope, "scope", tool.Type)
 		return
 	}
 
-	objectID, err := primitive.ObjectIDFromHex(req.ID)
-	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Invalid ID format"})
+	// Semua file input harus masih tersedia; tanpa input yang tersimpan operasi tidak bisa diulang
+	if len(prev.Inputs) == 0 {
+		writeMessage(w, lang, http.StatusGone, i18n.RerunInputsMissing)
 		return
 	}
+	var inputs [][]byte
+	for _, id := range prev.Inputs {
+		_, data, err := config.FileStore.Get(r.Context(), id)
+		if errors.Is(err, storage.ErrExpired) || errors.Is(err, storage.ErrNotFound) {
+			writeMessage(w, lang, http.StatusGone, i18n.RerunInputsExpired)
+			return
+		}
+		if err != nil {
+			writeMessage(w, lang, http.StatusInternalServerError, i18n.RerunInputReadFailed)
+			return
+		}
+		inputs = append(inputs, data)
+	}
 
-	var collectionName string
-	switch req.Type {
-	case "merge":
-		collectionName = "merge_history"
-	case "compress":
-		collectionName = "compress_history"
-	case "convert":
-		collectionName = "convert_history"
-	case "summary":
-		collectionName = "summary_history"
-	default:
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Invalid history type"})
+	details, err := tool.Decode(prev.Details)
+	if err != nil {
+		writeMessage(w, lang, http.StatusUnprocessableEntity, i18n.RerunDetailsInvalid)
+		return
+	}
+	result, err := tool.Run(inputs, details)
+	if err != nil {
+		writeError(w, lang, http.StatusUnprocessableEntity, i18n.RerunFailed, err)
 		return
 	}
 
-	filter := bson.M{"_id": objectID, "user_id": user.ID}
-	result, err := config.Mongoconn.Collection(collectionName).DeleteOne(r.Context(), filter)
+	output, err := config.FileStore.Put(r.Context(), storage.Object{
+		Name:        tool.FileName(details),
+		ContentType: "application/pdf",
+		Owner:       user.ID.Hex(),
+		Purpose:     fileOutputPurpose,
+		ExpiresAt:   time.Now().Add(config.FileRetention),
+	}, result)
 	if err != nil {
-		http.Error(w, "Failed to delete", http.StatusInternalServerError)
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.SaveFailed)
 		return
 	}
 
-	if result.DeletedCount == 0 {
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(model.ResponseMessage{Message: "History item not found or not authorized"})
+	next, err := LogActivity(model.Activity{
+		UserID:  user.ID,
+		Type:    prev.Type,
+		Inputs:  prev.Inputs,
+		Output:  output.ID,
+		RerunOf: prev.ID,
+	}, details)
+	if err != nil {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.SaveFailed)
 		return
 	}
 
-	// PERBAIKAN: Gunakan ResponseMessage
-	json.NewEncoder(w).Encode(model.ResponseMessage{Message: "History deleted successfully"})
+	CreateTemplatedNotification(user.ID, prev.Type, i18n.NotifRerun, "refresh", next.FileName, "type", prev.Type, "file", next.FileName)
+
+	at.WriteJSON(w, http.StatusOK, model.RerunResponse{
+		Message:    "Operasi berhasil dijalankan ulang",
+		ActivityID: next.ID,
+		Output:     toStoredFile(output),
+	})
 }
 
-// GetUserFromToken (Helper ini harus tetap ada jika belum ada di file lain dalam package yg sama)
-func GetUserFromToken(r *http.Request) (model.PdfmUsers, error) {
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		return model.PdfmUsers{}, errors.New("token tidak ditemukan")
+// ==========================================
+// MIGRASI KOLEKSI RIWAYAT LAMA
+// ==========================================
+
+// MigrateHistory godoc
+// @Summary Migrasi Riwayat Lama ke activity_log (Admin Only)
+// @Description Menyalin merge_history, compress_history, convert_history dan summary_history ke activity_log. Aman dijalankan ulang karena _id lama dipertahankan (upsert)
+// @Tags History - Unified
+// @Produce json
+// @Success 200 {object} model.MigrationResult
+// @Failure 403 {object} model.ResponseMessage
+// @Router /pdfm/admin/activity/migrate [post]
+// @Security BearerAuth
+func MigrateHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if _, _, ok := adminFromRequest(w, r); !ok {
+		return
 	}
 
-	const bearerPrefix = "Bearer "
-	if len(authHeader) <= len(bearerPrefix) || authHeader[:len(bearerPrefix)] != bearerPrefix {
-		return model.PdfmUsers{}, errors.New("format token salah")
+	ensureActivityIndexes()
+	result := model.MigrationResult{Message: "Migrasi selesai", Migrated: map[string]int64{}}
+	for _, tool := range activity.Tools() {
+		if tool.LegacyCollection == "" {
+			continue
+		}
+		n, err := migrateLegacyHistory(r.Context(), tool)
+		result.Migrated[tool.Type] = n
+		if err != nil {
+			slog.ErrorContext(r.Context(), "migrasi riwayat gagal", "collection", tool.LegacyCollection, "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			result.Message = "Migrasi gagal pada " + tool.LegacyCollection + ": " + err.Error()
+			json.NewEncoder(w).Encode(result)
+			return
+		}
 	}
-	token := authHeader[len(bearerPrefix):]
 
-	tokenData, err := atdb.GetOneDoc[model.Token](config.Mongoconn, "tokens", bson.M{"token": token})
+	json.NewEncoder(w).Encode(result)
+}
+
+// migrateLegacyHistory menyalin satu koleksi lama ke activity_log dengan bulk upsert per batch
+func migrateLegacyHistory(ctx context.Context, tool activity.Tool) (int64, error) {
+	const batchSize = 500
+	cur, err := config.Mongoconn.Collection(tool.LegacyCollection).Find(ctx, bson.M{})
 	if err != nil {
-		return model.PdfmUsers{}, err
+		return 0, err
 	}
+	defer cur.Close(ctx)
 
-	if tokenData.ExpiresAt.Before(time.Now()) {
-		return model.PdfmUsers{}, errors.New("token sudah kadaluarsa")
+	var migrated int64
+	var batch []mongo.WriteModel
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		res, err := config.Mongoconn.Collection(activityCollection).BulkWrite(ctx, batch, options.BulkWrite().SetOrdered(false))
+		if res != nil {
+			migrated += res.UpsertedCount + res.MatchedCount
+		}
+		batch = batch[:0]
+		return err
 	}
 
-	user, err := atdb.GetOneDoc[model.PdfmUsers](config.Mongoconn, "users", bson.M{"email": tokenData.Email})
-	return user, err
-}
\ No newline at end of file
+	for cur.Next(ctx) {
+		var legacy bson.M
+		if err := cur.Decode(&legacy); err != nil {
+			return migrated, err
+		}
+		details, err := tool.Decode(legacy)
+		if err != nil {
+			return migrated, err
+		}
+		doc, err := activity.Encode(details)
+		if err != nil {
+			return migrated, err
+		}
+		id, ok := legacy["_id"].(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		a := model.Activity{
+			ID:       id,
+			Type:     tool.Type,
+			FileName: tool.FileName(details),
+			Details:  doc,
+		}
+		a.UserID, _ = legacy["user_id"].(primitive.ObjectID)
+		if t, ok := legacy["created_at"].(primitive.DateTime); ok {
+			a.CreatedAt = t.Time()
+		}
+		batch = append(batch, mongo.NewReplaceOneModel().SetFilter(bson.M{"_id": a.ID}).SetReplacement(a).SetUpsert(true))
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return migrated, err
+			}
+		}
+	}
+	if err := cur.Err(); err != nil {
+		return migrated, err
+	}
+	return migrated, flush()
+}
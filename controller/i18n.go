@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// requestLocale menentukan bahasa response; user boleh nil untuk request tanpa login
+func requestLocale(r *http.Request, user *model.PdfmUsers) string {
+	preferred := ""
+	if user != nil {
+		preferred = user.Language
+	}
+	return i18n.FromRequest(r, preferred)
+}
+
+// writeMessage menulis ResponseMessage berisi kode dan teks pesan dalam bahasa lang
+func writeMessage(w http.ResponseWriter, lang string, status int, code string, args ...string) {
+	w.Header().Set("Content-Language", lang)
+	at.WriteJSON(w, status, model.ResponseMessage{Code: code, Message: i18n.T(lang, code, args...)})
+}
+
+// writeError seperti writeMessage, dengan detail err ditambahkan di belakang teks pesan
+func writeError(w http.ResponseWriter, lang string, status int, code string, err error) {
+	msg := i18n.T(lang, code)
+	if err != nil {
+		msg += ": " + err.Error()
+	}
+	w.Header().Set("Content-Language", lang)
+	at.WriteJSON(w, status, model.ResponseMessage{Code: code, Message: msg})
+}
+
+// messageError adalah error dari helper controller yang membawa status HTTP dan kode katalog,
+// sehingga teksnya baru dibuat dalam bahasa request saat ditulis oleh writeMessageError
+type messageError struct {
+	status int
+	code   string
+	args   []string
+}
+
+func (e *messageError) Error() string {
+	return i18n.T(i18n.Default, e.code, e.args...)
+}
+
+// newMessageError membuat messageError; args berpasangan seperti pada writeMessage
+func newMessageError(status int, code string, args ...string) error {
+	return &messageError{status: status, code: code, args: args}
+}
+
+// writeMessageError menulis err dari helper; error selain messageError dianggap kesalahan server
+func writeMessageError(w http.ResponseWriter, lang string, err error) {
+	var me *messageError
+	if errors.As(err, &me) {
+		writeMessage(w, lang, me.status, me.code, me.args...)
+		return
+	}
+	writeMessage(w, lang, http.StatusInternalServerError, i18n.InternalError)
+}
+
+// userLocale mengambil bahasa yang disimpan user untuk notifikasi yang dibuat di luar request
+func userLocale(userID primitive.ObjectID) string {
+	user, err := atdb.GetOneDoc[model.PdfmUsers](config.Mongoconn, "users", bson.M{"_id": userID})
+	if err != nil || !i18n.Supported(user.Language) {
+		return i18n.Default
+	}
+	return user.Language
+}
+
+// CreateTemplatedNotification membuat notifikasi dari template katalog dalam bahasa user,
+// mis. CreateTemplatedNotification(id, "merge", i18n.NotifMerge, "check-circle", "hasil.pdf", "count", "3", "file", "hasil.pdf")
+func CreateTemplatedNotification(userID primitive.ObjectID, notifType, code, icon, fileName string, args ...string) error {
+	notification := model.Notification{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Type:      notifType,
+		Code:      code,
+		Message:   i18n.T(userLocale(userID), code, args...),
+		Icon:      icon,
+		CreatedAt: time.Now(),
+		FileName:  fileName,
+	}
+	return insertNotification(&notification)
+}
+
+// UpdateLanguage godoc
+// @Summary Ubah Bahasa
+// @Description Menyimpan bahasa pilihan user (id atau en) untuk pesan API dan notifikasi. Header Accept-Language atau query ?lang tetap bisa dipakai per request
+// @Tags User Profile
+// @Accept json
+// @Produce json
+// @Param request body model.LanguageInput true "Bahasa"
+// @Success 200 {object} model.ResponseMessage
+// @Failure 400 {object} model.ResponseMessage
+// @Router /pdfm/profile/language [put]
+// @Security BearerAuth
+func UpdateLanguage(w http.ResponseWriter, r *http.Request) {
+	user, userLang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.LanguageInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, userLang, http.StatusBadRequest, i18n.InvalidBody, err)
+		return
+	}
+	lang := i18n.Normalize(req.Language)
+	if lang == "" {
+		writeMessage(w, userLang, http.StatusBadRequest, i18n.LanguageUnknown)
+		return
+	}
+
+	_, err := config.Mongoconn.Collection("users").UpdateOne(r.Context(),
+		bson.M{"_id": user.ID}, bson.M{"$set": bson.M{"language": lang, "updatedAt": time.Now()}})
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.SaveFailed, err)
+		return
+	}
+	writeMessage(w, lang, http.StatusOK, i18n.LanguageUpdated)
+}
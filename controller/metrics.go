@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/metrics"
+	"github.com/gocroot/helper/notify"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// jobQueueDepth dihitung saat scrape: delivery notifikasi yang menunggu dikirim per channel,
+// pengumuman terjadwal, dan akun yang menunggu purge
+var jobQueueDepth = metrics.NewGaugeFunc("pdfm_job_queue_depth",
+	"Jumlah pekerjaan yang menunggu diproses per antrean", []string{"queue"}, collectJobQueueDepth)
+
+func collectJobQueueDepth() []metrics.Sample {
+	if config.Mongoconn == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Channel tanpa antrean tetap ditulis dengan nilai 0
+	depth := map[string]float64{}
+	for _, ch := range []string{notify.ChannelEmail, notify.ChannelWhatsApp} {
+		depth["notification_"+ch] = 0
+	}
+	pending := bson.M{"$in": bson.A{notify.StatusQueued, notify.StatusRetrying, notify.StatusSending}}
+	cursor, err := GetMongoCollection("notifications").Aggregate(ctx, bson.A{
+		bson.M{"$match": bson.M{"deliveries.status": pending}},
+		bson.M{"$unwind": "$deliveries"},
+		bson.M{"$match": bson.M{"deliveries.status": pending}},
+		bson.M{"$group": bson.M{"_id": "$deliveries.channel", "count": bson.M{"$sum": 1}}},
+	})
+	if err != nil {
+		slog.Error("gagal menghitung antrean notifikasi", "error", err)
+	} else {
+		var rows []struct {
+			Channel string  `bson:"_id"`
+			Count   float64 `bson:"count"`
+		}
+		cursor.All(ctx, &rows)
+		for _, row := range rows {
+			depth["notification_"+row.Channel] = row.Count
+		}
+	}
+
+	counts := []struct {
+		queue, collection string
+		filter            bson.M
+	}{
+		{"announcement_scheduled", announcementCollection, bson.M{"status": "scheduled"}},
+		{"account_deletion", "users", bson.M{"deletionScheduledAt": bson.M{"$exists": true}}},
+	}
+	for _, c := range counts {
+		n, err := GetMongoCollection(c.collection).CountDocuments(ctx, c.filter)
+		if err != nil {
+			slog.Error("gagal menghitung antrean", "queue", c.queue, "error", err)
+			continue
+		}
+		depth[c.queue] = float64(n)
+	}
+
+	samples := make([]metrics.Sample, 0, len(depth))
+	for queue, n := range depth {
+		samples = append(samples, metrics.Sample{Labels: []string{queue}, Value: n})
+	}
+	return samples
+}
+
+// GetMetrics godoc
+// @Summary Metrik Prometheus
+// @Description Metrik dalam format teks Prometheus: request HTTP per route dan status, operasi PDF per tool, kedalaman antrean, pesan WhatsApp keluar per profile, latensi dan error perintah MongoDB per koleksi, serta metrik runtime Go dan proses. Jika PDFM_METRICS_TOKEN diisi, kirim sebagai Bearer token.
+// @Tags Monitoring
+// @Produce plain
+// @Success 200 {string} string
+// @Failure 401 {object} model.ResponseMessage
+// @Router /metrics [get]
+func GetMetrics(w http.ResponseWriter, r *http.Request) {
+	if config.MetricsToken != "" {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(config.MetricsToken)) != 1 {
+			writeMessage(w, requestLocale(r, nil), http.StatusUnauthorized, i18n.Unauthorized)
+			return
+		}
+	}
+	metrics.Handler().ServeHTTP(w, r)
+}
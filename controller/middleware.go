@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/authn"
+	"github.com/gocroot/helper/i18n"
+)
+
+// CORS menambahkan header CORS untuk origin yang diizinkan dan menjawab preflight-nya
+func CORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if config.SetAccessControlHeaders(w, r) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireScope seperti RequireUser tetapi juga menerima personal API key (header X-API-Key) yang punya
+// scope tersebut. Scope yang diawali ":" dibaca dari parameter path, mis. ":type" untuk /log/:type,
+// dan harus berupa tipe tool yang terdaftar. Endpoint tanpa RequireScope menolak API key.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	param, fromPath := strings.CutPrefix(scope, ":")
+	if !fromPath && !knownAPIKeyScope(scope) {
+		panic("controller: scope API key tidak dikenal: " + scope)
+	}
+	return func(next http.Handler) http.Handler {
+		user := RequireUser(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-API-Key") == "" {
+				user.ServeHTTP(w, r)
+				return
+			}
+			scope := scope
+			if fromPath {
+				if scope = r.PathValue(param); !knownAPIKeyScope(scope) {
+					writeMessage(w, requestLocale(r, nil), http.StatusForbidden, i18n.APIKeyEndpoint)
+					return
+				}
+			}
+			r, ok := AuthenticateAPIKey(w, r, scope)
+			if !ok {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rejectAPIKey menolak API key di endpoint yang tidak dipasangi RequireScope
+func rejectAPIKey(w http.ResponseWriter, r *http.Request) bool {
+	if r.Header.Get("X-API-Key") == "" {
+		return false
+	}
+	writeMessage(w, requestLocale(r, nil), http.StatusForbidden, i18n.APIKeyEndpoint)
+	return true
+}
+
+// RequireUser menolak request tanpa token yang valid. Principal disimpan di context sehingga
+// handler yang memanggil GetUserFromToken tidak memverifikasi token untuk kedua kalinya.
+func RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rejectAPIKey(w, r) {
+			return
+		}
+		p, err := Authenticate(r)
+		if err != nil {
+			writeAuthError(w, requestLocale(r, nil), err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(authn.WithPrincipal(r.Context(), p)))
+	})
+}
+
+// RequireAdmin seperti RequireUser dan juga mensyaratkan role admin. Jangan dipasang di endpoint
+// yang juga dipanggil Cloud Scheduler lewat X-Cron-Secret.
+func RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rejectAPIKey(w, r) {
+			return
+		}
+		lang := requestLocale(r, nil)
+		p, err := Authenticate(r)
+		if err != nil {
+			writeError(w, lang, http.StatusUnauthorized, i18n.Unauthorized, err)
+			return
+		}
+		if p.User != nil {
+			lang = requestLocale(r, p.User)
+		}
+		if !p.HasRole(authn.RoleAdmin) {
+			writeMessage(w, lang, http.StatusForbidden, i18n.ForbiddenAdmin)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(authn.WithPrincipal(r.Context(), p)))
+	})
+}
+
+// MethodNotAllowed dipanggil router jika path dikenal tetapi method-nya tidak. Header Allow sudah diisi router.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeMessage(w, requestLocale(r, nil), http.StatusMethodNotAllowed, i18n.MethodNotAllowed)
+}
+
+// InternalError dipanggil jika handler panic sebelum menulis respons
+func InternalError(w http.ResponseWriter, r *http.Request) {
+	writeMessage(w, requestLocale(r, nil), http.StatusInternalServerError, i18n.InternalError)
+}
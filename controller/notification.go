@@ -3,13 +3,16 @@ package controller
 import (
 	"context"
 	"encoding/json"
-	"errors"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gocroot/config"
-	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/notify"
+	"github.com/gocroot/helper/paging"
 	"github.com/gocroot/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -17,54 +20,86 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// GetNotifications retrieves all notifications for the authenticated user
+// GetNotifications retrieves notifications for the authenticated user
 // GetNotifications godoc
 // @Summary Lihat Notifikasi
-// @Description Mengambil daftar notifikasi milik user, diurutkan dari yang terbaru
+// @Description Mengambil daftar notifikasi milik user, diurutkan dari yang terbaru, dengan cursor pagination
 // @Tags Notification
 // @Accept json
 // @Produce json
+// @Param type query string false "Filter tipe notifikasi, pisahkan dengan koma"
+// @Param read query bool false "true = sudah dibaca, false = belum dibaca"
+// @Param limit query int false "Jumlah item per halaman (default 50, maks 100)"
+// @Param cursor query string false "Cursor dari response sebelumnya (next_cursor)"
 // @Success 200 {object} model.NotificationResponse
-// @Failure 401 {object} model.NotificationActionResponse
+// @Failure 400 {object} model.ResponseMessage
+// @Failure 401 {object} model.ResponseMessage
 // @Router /pdfm/notifications [get]
 // @Security BearerAuth
 func GetNotifications(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
-	if r.Method == "OPTIONS" {
-		w.WriteHeader(http.StatusOK)
+	// Get user from token
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
 		return
 	}
-
-	// Get user from token
-	userID, err := GetUserIDFromToken(r)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		// PERBAIKAN: Gunakan struct Response
-		json.NewEncoder(w).Encode(model.NotificationActionResponse{Status: 401, Message: "Unauthorized: " + err.Error()})
+	userID := user.ID
+
+	q := r.URL.Query()
+	limit := paging.Limit(q.Get("limit"), 50, 100)
+	filter := bson.M{"user_id": userID}
+	if types := q.Get("type"); types != "" {
+		var list []string
+		for _, t := range strings.Split(types, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				list = append(list, t)
+			}
+		}
+		filter["type"] = bson.M{"$in": list}
+	}
+	switch q.Get("read") {
+	case "":
+	case "true":
+		filter["is_read"] = true
+	case "false":
+		filter["is_read"] = false
+	default:
+		writeMessage(w, lang, http.StatusBadRequest, i18n.NotificationReadParam)
 		return
 	}
+	if raw := q.Get("cursor"); raw != "" {
+		cur, err := paging.Decode(raw)
+		var id primitive.ObjectID
+		if err == nil {
+			id, err = primitive.ObjectIDFromHex(cur.ID)
+		}
+		if err != nil {
+			writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidCursor)
+			return
+		}
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": cur.Time}},
+			bson.M{"created_at": cur.Time, "_id": bson.M{"$lt": id}},
+		}
+	}
 
 	// Get MongoDB collection
+	ensureNotificationIndexes()
 	collection := GetMongoCollection("notifications")
 
-	// Find all notifications for this user, sorted by created_at descending
-	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(50)
-	cursor, err := collection.Find(context.Background(), bson.M{"user_id": userID}, opts)
+	// Sorted by created_at descending, _id sebagai tie-breaker cursor
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(int64(limit + 1))
+	cursor, err := collection.Find(context.Background(), filter, opts)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(model.NotificationActionResponse{Status: 500, Message: "Failed to fetch notifications"})
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.FetchFailed)
 		return
 	}
 	defer cursor.Close(context.Background())
 
 	var notifications []model.Notification
 	if err = cursor.All(context.Background(), &notifications); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(model.NotificationActionResponse{Status: 500, Message: "Failed to parse notifications"})
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.FetchFailed)
 		return
 	}
 
@@ -73,12 +108,18 @@ func GetNotifications(w http.ResponseWriter, r *http.Request) {
 		notifications = []model.Notification{}
 	}
 
-	// Response ini sudah benar pakai struct
-	json.NewEncoder(w).Encode(model.NotificationResponse{
+	response := model.NotificationResponse{
 		Status:        http.StatusOK,
 		Message:       "Notifications retrieved successfully",
 		Notifications: notifications,
-	})
+	}
+	if len(notifications) > limit {
+		response.Notifications = notifications[:limit]
+		last := notifications[limit-1]
+		response.HasMore = true
+		response.NextCursor = paging.Encode(paging.Cursor{Time: last.CreatedAt, ID: last.ID.Hex()})
+	}
+	json.NewEncoder(w).Encode(response)
 }
 
 // AddNotification creates a new notification for the authenticated user
@@ -90,56 +131,57 @@ func GetNotifications(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param request body model.NotificationRequest true "Payload Notifikasi"
 // @Success 201 {object} model.NotificationActionResponse
-// @Failure 400 {object} model.NotificationActionResponse
+// @Failure 400 {object} model.ResponseMessage
 // @Router /pdfm/notifications [post]
 // @Security BearerAuth
 func AddNotification(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
-	if r.Method == "OPTIONS" {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
-
-	userID, err := GetUserIDFromToken(r)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(model.NotificationActionResponse{Status: 401, Message: "Unauthorized"})
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
 		return
 	}
+	userID := user.ID
 
 	var req model.NotificationRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(model.NotificationActionResponse{Status: 400, Message: "Invalid request body"})
+		writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidBody)
 		return
 	}
 
-	if req.Type == "" || req.Message == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(model.NotificationActionResponse{Status: 400, Message: "Type and message are required"})
+	if req.Type == "" || (req.Message == "" && req.Code == "") {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.NotificationRequired)
 		return
 	}
 
+	// Pesan dari template katalog dibuat dalam bahasa user; params boleh memakai {file} dsb.
+	message := req.Message
+	if req.Code != "" {
+		if !i18n.Has(req.Code) {
+			writeMessage(w, lang, http.StatusBadRequest, i18n.NotificationCodeUnknown, "code", req.Code)
+			return
+		}
+		args := []string{"file", req.FileName}
+		for k, v := range req.Params {
+			args = append(args, k, v)
+		}
+		message = i18n.T(userLocale(userID), req.Code, args...)
+	}
+
 	notification := model.Notification{
 		ID:        primitive.NewObjectID(),
 		UserID:    userID,
 		Type:      req.Type,
-		Message:   req.Message,
+		Code:      req.Code,
+		Message:   message,
 		Icon:      req.Icon,
 		IsRead:    false,
 		CreatedAt: time.Now(),
 		FileName:  req.FileName,
 	}
 
-	collection := GetMongoCollection("notifications")
-	_, err = collection.InsertOne(context.Background(), notification)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(model.NotificationActionResponse{Status: 500, Message: "Failed to create notification"})
+	if err := insertNotification(&notification); err != nil {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.SaveFailed)
 		return
 	}
 
@@ -164,31 +206,21 @@ func AddNotification(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 func MarkAllAsRead(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "PUT, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
-	if r.Method == "OPTIONS" {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
-
-	userID, err := GetUserIDFromToken(r)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(model.NotificationActionResponse{Status: 401, Message: "Unauthorized"})
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
 		return
 	}
+	userID := user.ID
 
 	collection := GetMongoCollection("notifications")
-	_, err = collection.UpdateMany(
+	_, err := collection.UpdateMany(
 		context.Background(),
 		bson.M{"user_id": userID},
 		bson.M{"$set": bson.M{"is_read": true}},
 	)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(model.NotificationActionResponse{Status: 500, Message: "Failed to mark as read"})
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.SaveFailed)
 		return
 	}
 
@@ -211,71 +243,161 @@ func MarkAllAsRead(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 func ClearNotifications(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "DELETE, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
-	if r.Method == "OPTIONS" {
-		w.WriteHeader(http.StatusOK)
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
 		return
 	}
+	userID := user.ID
 
-	userID, err := GetUserIDFromToken(r)
+	collection := GetMongoCollection("notifications")
+	_, err := collection.DeleteMany(context.Background(), bson.M{"user_id": userID})
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(model.NotificationActionResponse{Status: 401, Message: "Unauthorized"})
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.DeleteFailed)
 		return
 	}
 
-	collection := GetMongoCollection("notifications")
-	_, err = collection.DeleteMany(context.Background(), bson.M{"user_id": userID})
+	// PERBAIKAN: Gunakan NotificationActionResponse
+	json.NewEncoder(w).Encode(model.NotificationActionResponse{
+		Status:  http.StatusOK,
+		Message: "All notifications cleared",
+	})
+}
+
+// MarkNotificationRead godoc
+// @Summary Tandai Satu Notifikasi Dibaca
+// @Description Mengubah status satu notifikasi milik user menjadi 'read'
+// @Tags Notification
+// @Produce json
+// @Param id path string true "ID notifikasi"
+// @Success 200 {object} model.NotificationActionResponse
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/notifications/{id}/read [put]
+// @Security BearerAuth
+func MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+	userID := user.ID
+
+	id, err := primitive.ObjectIDFromHex(at.PathParam(r, "/pdfm/notifications/:id/read", "id"))
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(model.NotificationActionResponse{Status: 500, Message: "Failed to clear notifications"})
+		writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	res, err := GetMongoCollection("notifications").UpdateOne(
+		context.Background(),
+		bson.M{"_id": id, "user_id": userID},
+		bson.M{"$set": bson.M{"is_read": true}},
+	)
+	if err != nil {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.SaveFailed)
+		return
+	}
+	if res.MatchedCount == 0 {
+		writeMessage(w, lang, http.StatusNotFound, i18n.NotificationNotFound)
 		return
 	}
 
-	// PERBAIKAN: Gunakan NotificationActionResponse
 	json.NewEncoder(w).Encode(model.NotificationActionResponse{
 		Status:  http.StatusOK,
-		Message: "All notifications cleared",
+		Message: "Notification marked as read",
+		ID:      id.Hex(),
 	})
 }
 
-// GetUserIDFromToken extracts user ID from the Authorization header token
-// Uses the existing Bearer token authentication system from pdfm.go
-func GetUserIDFromToken(r *http.Request) (primitive.ObjectID, error) {
-	// Get token from Authorization header
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		return primitive.NilObjectID, errors.New("missing token")
+// DeleteNotification godoc
+// @Summary Hapus Satu Notifikasi
+// @Description Menghapus satu notifikasi milik user
+// @Tags Notification
+// @Produce json
+// @Param id path string true "ID notifikasi"
+// @Success 200 {object} model.NotificationActionResponse
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/notifications/{id} [delete]
+// @Security BearerAuth
+func DeleteNotification(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
 	}
+	userID := user.ID
 
-	// Validate Bearer token format
-	const bearerPrefix = "Bearer "
-	if len(authHeader) <= len(bearerPrefix) || !strings.HasPrefix(authHeader, bearerPrefix) {
-		return primitive.NilObjectID, errors.New("invalid token format")
+	id, err := primitive.ObjectIDFromHex(at.GetParam(r))
+	if err != nil {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidID)
+		return
 	}
-	token := authHeader[len(bearerPrefix):]
 
-	// Validate token in database
-	tokenData, err := atdb.GetOneDoc[model.Token](config.Mongoconn, "tokens", bson.M{"token": token})
+	res, err := GetMongoCollection("notifications").DeleteOne(context.Background(), bson.M{"_id": id, "user_id": userID})
 	if err != nil {
-		return primitive.NilObjectID, errors.New("invalid token")
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.DeleteFailed)
+		return
 	}
+	if res.DeletedCount == 0 {
+		writeMessage(w, lang, http.StatusNotFound, i18n.NotificationNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(model.NotificationActionResponse{
+		Status:  http.StatusOK,
+		Message: "Notification deleted",
+		ID:      id.Hex(),
+	})
+}
+
+// GetUnreadCount godoc
+// @Summary Jumlah Notifikasi Belum Dibaca
+// @Description Jumlah notifikasi user yang belum dibaca (untuk badge)
+// @Tags Notification
+// @Produce json
+// @Success 200 {object} model.UnreadCountResponse
+// @Failure 401 {object} model.ResponseMessage
+// @Router /pdfm/notifications/unread-count [get]
+// @Security BearerAuth
+func GetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
 
-	// Check if token is expired
-	if tokenData.ExpiresAt.Before(time.Now()) {
-		return primitive.NilObjectID, errors.New("token expired")
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
 	}
+	userID := user.ID
 
-	// Get user from email in token
-	user, err := atdb.GetOneDoc[model.PdfmUsers](config.Mongoconn, "users", bson.M{"email": tokenData.Email})
+	ensureNotificationIndexes()
+	count, err := GetMongoCollection("notifications").CountDocuments(context.Background(), bson.M{"user_id": userID, "is_read": false})
 	if err != nil {
-		return primitive.NilObjectID, errors.New("user not found")
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.FetchFailed)
+		return
 	}
 
-	return user.ID, nil
+	json.NewEncoder(w).Encode(model.UnreadCountResponse{
+		Status:  http.StatusOK,
+		Message: "Unread count retrieved successfully",
+		Unread:  count,
+	})
+}
+
+var notificationIndexOnce indexOnce
+
+// ensureNotificationIndexes membuat index untuk listing berurutan dan hitungan unread
+func ensureNotificationIndexes() {
+	notificationIndexOnce.Do(func() error {
+		_, err := GetMongoCollection("notifications").Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "is_read", Value: 1}}},
+		})
+		if err != nil {
+			slog.Error("gagal membuat index notifications", "error", err)
+		}
+		return err
+	})
 }
 
 // GetMongoCollection returns a MongoDB collection
@@ -297,7 +419,35 @@ func CreateNotificationForUser(userID primitive.ObjectID, notifType, message, ic
 		FileName:  fileName,
 	}
 
-	collection := GetMongoCollection("notifications")
-	_, err := collection.InsertOne(context.Background(), notification)
-	return err
+	return insertNotification(&notification)
+}
+
+// insertNotification menyimpan notifikasi sesuai preferensi channel user, mengirimnya ke stream SSE
+// jika in_app aktif, lalu mengirim email/WhatsApp yang tidak tertahan quiet hours atau digest
+func insertNotification(n *model.Notification) error {
+	prefs := getNotificationPreferences(n.UserID)
+	now := time.Now()
+	n.Deliveries = notify.Plan(prefs, n.Type, now)
+	inApp := n.Deliveries[0].Status == notify.StatusSent
+	if !inApp {
+		n.IsRead = true // tetap disimpan sebagai catatan pengiriman, tetapi tidak menambah badge unread
+	}
+
+	if _, err := GetMongoCollection("notifications").InsertOne(context.Background(), n); err != nil {
+		return err
+	}
+	if inApp {
+		publishNotification(*n)
+	}
+
+	for _, d := range n.Deliveries {
+		if d.Channel != notify.ChannelInApp && notify.Due(d, now) {
+			pending := *n
+			pending.Deliveries = append([]model.NotificationDelivery(nil), n.Deliveries...)
+			to, _ := notificationRecipient(n.UserID, prefs)
+			go deliverDue(context.Background(), &pending, to, now)
+			break
+		}
+	}
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,461 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/atapi"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/gcallapi"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/notify"
+	"github.com/gocroot/model"
+	"github.com/whatsauth/itmodel"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const notificationPrefsCollection = "notification_preferences"
+
+// notificationDispatcher mengirim notifikasi ke channel eksternal
+var notificationDispatcher = &notify.Dispatcher{
+	Channels: map[string]notify.Channel{
+		notify.ChannelEmail:    notify.ChannelFunc(sendNotificationEmail),
+		notify.ChannelWhatsApp: notify.ChannelFunc(sendNotificationWhatsApp),
+	},
+	MaxAttempts: config.NotificationMaxAttempts,
+	Backoff:     config.NotificationBackoff,
+}
+
+func sendNotificationEmail(ctx context.Context, to notify.Recipient, msg notify.Message) error {
+	if to.Email == "" {
+		return notify.ErrNoAddress
+	}
+	return gcallapi.SendEmail(config.Mongoconn, to.Email, msg.Subject, msg.Body)
+}
+
+func sendNotificationWhatsApp(ctx context.Context, to notify.Recipient, msg notify.Message) error {
+	if to.Phone == "" {
+		return notify.ErrNoAddress
+	}
+	profile := config.Profile()
+	if profile.URLAPIText == "" {
+		return errors.New("profile WhatsApp belum dimuat")
+	}
+	dt := &itmodel.TextMessage{
+		To:       to.Phone,
+		IsGroup:  false,
+		Messages: "*" + msg.Subject + "*\n" + msg.Body,
+	}
+	status, _, err := atapi.PostWhatsApp(profile, dt, profile.URLAPIText)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return errors.New("WhatsApp API status " + strconv.Itoa(status))
+	}
+	return nil
+}
+
+var notificationPrefsIndexOnce indexOnce
+
+func ensureNotificationPrefsIndexes() {
+	notificationPrefsIndexOnce.Do(func() error {
+		ctx := context.Background()
+		_, err1 := GetMongoCollection(notificationPrefsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		if err1 != nil {
+			slog.Error("gagal membuat index", "collection", notificationPrefsCollection, "error", err1)
+		}
+		_, err2 := GetMongoCollection("notifications").Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "deliveries.status", Value: 1}, {Key: "deliveries.next_attempt", Value: 1}},
+		})
+		if err2 != nil {
+			slog.Error("gagal membuat index deliveries", "collection", "notifications", "error", err2)
+		}
+		return errors.Join(err1, err2)
+	})
+}
+
+// getNotificationPreferences mengambil preferensi user; user tanpa preferensi mendapat nilai default (in_app saja)
+func getNotificationPreferences(userID primitive.ObjectID) model.NotificationPreferences {
+	prefs, err := atdb.GetOneDoc[model.NotificationPreferences](config.Mongoconn, notificationPrefsCollection, bson.M{"user_id": userID})
+	if err != nil {
+		return model.NotificationPreferences{UserID: userID}
+	}
+	return prefs
+}
+
+// notificationRecipient mengembalikan alamat tujuan user. User yang dihapus atau disuspend mendapat
+// Recipient kosong dan active bernilai false, sehingga delivery email/WhatsApp-nya ditandai skipped.
+func notificationRecipient(userID primitive.ObjectID, prefs model.NotificationPreferences) (to notify.Recipient, active bool) {
+	user, err := atdb.GetOneDoc[model.PdfmUsers](config.Mongoconn, "users", activeUser(bson.M{"_id": userID}))
+	if err != nil {
+		return notify.Recipient{}, false
+	}
+	return notify.Recipient{Name: user.Name, Email: user.Email, Phone: prefs.WhatsAppNumber}, true
+}
+
+func notificationContent(n model.Notification) notify.Message {
+	body := n.Message
+	if n.FileName != "" {
+		body += "\nFile: " + n.FileName
+	}
+	return notify.Message{Type: n.Type, Subject: "PDFM - " + n.Type, Body: body}
+}
+
+// claimDelivery menandai delivery sebagai sending secara atomik sebelum dikirim. insertNotification dan
+// DispatchNotifications bisa memproses notifikasi yang sama bersamaan; hanya yang berhasil mengklaim yang mengirim.
+func claimDelivery(ctx context.Context, id primitive.ObjectID, d model.NotificationDelivery, now time.Time) bool {
+	err := GetMongoCollection("notifications").FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "deliveries": bson.M{"$elemMatch": bson.M{
+			"channel":      d.Channel,
+			"status":       d.Status,
+			"next_attempt": bson.M{"$lte": now},
+		}}},
+		bson.M{"$set": bson.M{
+			"deliveries.$.status":       notify.StatusSending,
+			"deliveries.$.next_attempt": now.Add(notify.SendingLease),
+		}},
+	).Err()
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		slog.ErrorContext(ctx, "gagal mengklaim delivery", "notification_id", id.Hex(), "channel", d.Channel, "error", err)
+	}
+	return err == nil
+}
+
+// deliverDue mencoba semua delivery eksternal n yang sudah jatuh tempo dan menyimpan statusnya.
+// Delivery yang sudah diklaim proses lain dilewati.
+func deliverDue(ctx context.Context, n *model.Notification, to notify.Recipient, now time.Time) (attempted, sent, failed int) {
+	msg := notificationContent(*n)
+	for i := range n.Deliveries {
+		d := &n.Deliveries[i]
+		if d.Channel == notify.ChannelInApp || !notify.Due(*d, now) || !claimDelivery(ctx, n.ID, *d, now) {
+			continue
+		}
+		notificationDispatcher.Attempt(ctx, d, to, msg, now)
+		attempted++
+		switch d.Status {
+		case notify.StatusSent:
+			sent++
+		case notify.StatusFailed:
+			failed++
+		}
+		_, err := GetMongoCollection("notifications").UpdateOne(ctx,
+			bson.M{"_id": n.ID, "deliveries.channel": d.Channel},
+			bson.M{"$set": bson.M{"deliveries.$": *d}},
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "gagal menyimpan status delivery", "notification_id", n.ID.Hex(), "channel", d.Channel, "error", err)
+		}
+	}
+	return
+}
+
+// GetNotificationPreferences godoc
+// @Summary Lihat Preferensi Notifikasi
+// @Description Channel per tipe notifikasi (in_app, email, whatsapp), quiet hours dan ringkasan harian
+// @Tags Notification
+// @Produce json
+// @Success 200 {object} model.NotificationPreferencesResponse
+// @Failure 401 {object} model.ResponseMessage
+// @Router /pdfm/notifications/preferences [get]
+// @Security BearerAuth
+func GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	user, _, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	prefs := getNotificationPreferences(user.ID)
+	if prefs.Channels == nil {
+		prefs.Channels = map[string][]string{"*": {notify.ChannelInApp}}
+	}
+	if prefs.Timezone == "" {
+		prefs.Timezone = notify.DefaultTimezone
+	}
+	json.NewEncoder(w).Encode(model.NotificationPreferencesResponse{
+		Status:      http.StatusOK,
+		Message:     "Preferences retrieved successfully",
+		Preferences: prefs,
+	})
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Ubah Preferensi Notifikasi
+// @Description Menyimpan channel per tipe notifikasi (key "*" untuk default), nomor WhatsApp, quiet hours (HH:MM) dan jam ringkasan harian
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Param request body model.NotificationPreferences true "Preferensi"
+// @Success 200 {object} model.NotificationPreferencesResponse
+// @Failure 400 {object} model.ResponseMessage
+// @Failure 401 {object} model.ResponseMessage
+// @Router /pdfm/notifications/preferences [put]
+// @Security BearerAuth
+func UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+	userID := user.ID
+
+	var req model.NotificationPreferences
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidBody, err)
+		return
+	}
+	if err := validateNotificationPreferences(&req); err != nil {
+		writeMessageError(w, lang, err)
+		return
+	}
+
+	// last_sent_at digest dipertahankan agar mengubah preferensi tidak memicu digest ganda
+	current := getNotificationPreferences(userID)
+	req.UserID = userID
+	req.Digest.LastSentAt = current.Digest.LastSentAt
+	req.UpdatedAt = time.Now()
+
+	ensureNotificationPrefsIndexes()
+	_, err := GetMongoCollection(notificationPrefsCollection).ReplaceOne(context.Background(),
+		bson.M{"user_id": userID}, req, options.Replace().SetUpsert(true))
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.SaveFailed, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(model.NotificationPreferencesResponse{
+		Status:      http.StatusOK,
+		Message:     "Preferences updated successfully",
+		Preferences: req,
+	})
+}
+
+// validateNotificationPreferences menormalkan nomor WhatsApp dan memeriksa preferensi; error-nya messageError
+func validateNotificationPreferences(p *model.NotificationPreferences) error {
+	if len(p.Channels) == 0 {
+		p.Channels = map[string][]string{"*": {notify.ChannelInApp}}
+	}
+	for typ, channels := range p.Channels {
+		for _, c := range channels {
+			if c != notify.ChannelInApp && c != notify.ChannelEmail && c != notify.ChannelWhatsApp {
+				return newMessageError(http.StatusBadRequest, i18n.PrefsChannelUnknown, "type", typ, "channel", c)
+			}
+			if c == notify.ChannelWhatsApp && p.WhatsAppNumber == "" {
+				return newMessageError(http.StatusBadRequest, i18n.PrefsWhatsAppRequired)
+			}
+		}
+	}
+	if p.WhatsAppNumber != "" {
+		p.WhatsAppNumber = strings.TrimPrefix(strings.ReplaceAll(p.WhatsAppNumber, " ", ""), "+")
+		if strings.HasPrefix(p.WhatsAppNumber, "0") {
+			p.WhatsAppNumber = "62" + p.WhatsAppNumber[1:]
+		}
+		if _, err := strconv.ParseUint(p.WhatsAppNumber, 10, 64); err != nil {
+			return newMessageError(http.StatusBadRequest, i18n.PrefsWhatsAppInvalid)
+		}
+	}
+	if p.Timezone != "" {
+		if _, err := time.LoadLocation(p.Timezone); err != nil {
+			return newMessageError(http.StatusBadRequest, i18n.PrefsTimezoneInvalid)
+		}
+	}
+	if p.QuietHours.Enabled {
+		if _, err := notify.ParseClock(p.QuietHours.Start); err != nil {
+			return newMessageError(http.StatusBadRequest, i18n.PrefsQuietHoursInvalid, "field", "quiet_hours.start")
+		}
+		if _, err := notify.ParseClock(p.QuietHours.End); err != nil {
+			return newMessageError(http.StatusBadRequest, i18n.PrefsQuietHoursInvalid, "field", "quiet_hours.end")
+		}
+	}
+	if p.Digest.Hour < 0 || p.Digest.Hour > 23 {
+		return newMessageError(http.StatusBadRequest, i18n.PrefsDigestHourInvalid)
+	}
+	return nil
+}
+
+// DispatchNotifications godoc
+// @Summary Jalankan Pengiriman Notifikasi
+// @Description Mengirim email/WhatsApp yang tertunda (quiet hours, retry), menerbitkan pengumuman terjadwal dan ringkasan harian yang jatuh tempo. Dipanggil scheduler dengan header X-Cron-Secret atau oleh admin
+// @Tags Notification
+// @Produce json
+// @Param X-Cron-Secret header string false "Secret scheduler (PDFM_CRON_SECRET)"
+// @Success 200 {object} model.DispatchResult
+// @Failure 401 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Router /pdfm/admin/notifications/dispatch [post]
+// @Security BearerAuth
+func DispatchNotifications(w http.ResponseWriter, r *http.Request) {
+	lang := requestLocale(r, nil)
+	if !isCronRequest(r) {
+		var ok bool
+		if _, lang, ok = adminFromRequest(w, r); !ok {
+			return
+		}
+	}
+
+	ensureNotificationPrefsIndexes()
+	ctx := r.Context()
+	now := time.Now()
+	result := model.DispatchResult{Status: http.StatusOK, Message: "Dispatch selesai"}
+
+	// 1. Delivery tertunda dan retry
+	filter := bson.M{"deliveries": bson.M{"$elemMatch": bson.M{
+		"status":       bson.M{"$in": bson.A{notify.StatusQueued, notify.StatusRetrying, notify.StatusSending}},
+		"next_attempt": bson.M{"$lte": now},
+	}}}
+	cursor, err := GetMongoCollection("notifications").Find(ctx, filter, options.Find().SetLimit(500))
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.DispatchQueueFailed, err)
+		return
+	}
+	var pending []model.Notification
+	cursor.All(ctx, &pending)
+
+	recipients := map[primitive.ObjectID]notify.Recipient{}
+	for i := range pending {
+		n := &pending[i]
+		to, ok := recipients[n.UserID]
+		if !ok {
+			to, _ = notificationRecipient(n.UserID, getNotificationPreferences(n.UserID))
+			recipients[n.UserID] = to
+		}
+		attempted, sent, failed := deliverDue(ctx, n, to, now)
+		result.Attempted += attempted
+		result.Sent += sent
+		result.Failed += failed
+	}
+
+	// 2. Pengumuman terjadwal dan kedaluwarsa
+	result.Announcements, result.Expired = runAnnouncementSchedule(ctx, now)
+
+	// 3. Ringkasan harian
+	var digestUsers []model.NotificationPreferences
+	cursor, err = GetMongoCollection(notificationPrefsCollection).Find(ctx, bson.M{"digest.enabled": true})
+	if err == nil {
+		cursor.All(ctx, &digestUsers)
+	}
+	for _, prefs := range digestUsers {
+		if notify.DigestDue(prefs, now) && sendDigest(ctx, prefs, now) {
+			result.Digests++
+		}
+	}
+
+	at.WriteJSON(w, http.StatusOK, result)
+}
+
+// claimDigest mengklaim ringkasan harian user secara atomik: hanya berhasil jika last_sent_at belum berubah
+// sejak preferensi dibaca dan tidak ada dispatch lain yang lease-nya masih berlaku
+func claimDigest(ctx context.Context, prefs model.NotificationPreferences, now time.Time) bool {
+	filter := bson.M{
+		"user_id": prefs.UserID,
+		"$or": bson.A{
+			bson.M{"digest.claimed_until": bson.M{"$exists": false}},
+			bson.M{"digest.claimed_until": bson.M{"$lte": now}},
+		},
+	}
+	if prefs.Digest.LastSentAt.IsZero() {
+		filter["digest.last_sent_at"] = bson.M{"$exists": false}
+	} else {
+		filter["digest.last_sent_at"] = prefs.Digest.LastSentAt
+	}
+	err := GetMongoCollection(notificationPrefsCollection).FindOneAndUpdate(ctx, filter,
+		bson.M{"$set": bson.M{"digest.claimed_until": now.Add(notify.SendingLease)}}).Err()
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		slog.ErrorContext(ctx, "gagal mengklaim digest", "user_id", prefs.UserID.Hex(), "error", err)
+	}
+	return err == nil
+}
+
+// sendDigest mengklaim lalu mengirim ringkasan harian user. Jika semua channel berhasil, last_sent_at
+// diperbarui; jika tidak, klaim dilepas sehingga dicoba lagi pada dispatch berikutnya.
+func sendDigest(ctx context.Context, prefs model.NotificationPreferences, now time.Time) bool {
+	if !claimDigest(ctx, prefs, now) {
+		return false
+	}
+	sent := deliverDigest(ctx, prefs, now)
+	update := bson.M{"$unset": bson.M{"digest.claimed_until": ""}}
+	if sent {
+		update["$set"] = bson.M{"digest.last_sent_at": now}
+	}
+	_, err := GetMongoCollection(notificationPrefsCollection).UpdateOne(ctx, bson.M{"user_id": prefs.UserID}, update)
+	if err != nil {
+		slog.ErrorContext(ctx, "gagal menyimpan status digest", "user_id", prefs.UserID.Hex(), "error", err)
+	}
+	return sent && err == nil
+}
+
+// deliverDigest mengirim satu ringkasan per channel berisi semua notifikasi berstatus digest milik user.
+// Hanya notifikasi yang masuk ke ringkasan yang ditandai terkirim.
+func deliverDigest(ctx context.Context, prefs model.NotificationPreferences, now time.Time) bool {
+	cursor, err := GetMongoCollection("notifications").Find(ctx,
+		bson.M{"user_id": prefs.UserID, "deliveries.status": notify.StatusDigest},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return false
+	}
+	var items []model.Notification
+	if err := cursor.All(ctx, &items); err != nil {
+		return false
+	}
+
+	lines := map[string][]string{}
+	included := map[string]bson.A{}
+	for _, n := range items {
+		for _, d := range n.Deliveries {
+			if d.Status == notify.StatusDigest {
+				lines[d.Channel] = append(lines[d.Channel], "- "+n.Message)
+				included[d.Channel] = append(included[d.Channel], n.ID)
+			}
+		}
+	}
+
+	to, active := notificationRecipient(prefs.UserID, prefs)
+	if !active {
+		return false
+	}
+	complete := true
+	for channel, list := range lines {
+		d := model.NotificationDelivery{Channel: channel, Status: notify.StatusQueued}
+		msg := notify.Message{
+			Type:    "digest",
+			Subject: "PDFM - Ringkasan harian (" + strconv.Itoa(len(list)) + " notifikasi)",
+			Body:    strings.Join(list, "\n"),
+		}
+		notificationDispatcher.Attempt(ctx, &d, to, msg, now)
+		if d.Status != notify.StatusSent && d.Status != notify.StatusSkipped {
+			complete = false
+			continue
+		}
+		_, err := GetMongoCollection("notifications").UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": included[channel]}},
+			bson.M{"$set": bson.M{
+				"deliveries.$[d].status":   d.Status,
+				"deliveries.$[d].attempts": d.Attempts,
+				"deliveries.$[d].sent_at":  now,
+			}},
+			options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+				bson.M{"d.channel": channel, "d.status": notify.StatusDigest},
+			}}),
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "gagal memperbarui status digest", "user_id", prefs.UserID.Hex(), "channel", channel, "error", err)
+		}
+	}
+
+	return complete
+}
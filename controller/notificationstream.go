@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/pubsub"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Jumlah notifikasi terlewat yang dikirim ulang saat klien menyambung dengan Last-Event-ID
+const sseResumeLimit = 100
+
+// notificationBroker meneruskan notifikasi baru ke stream SSE milik user (topic = hex user ID)
+var notificationBroker = newNotificationBroker()
+
+func newNotificationBroker() pubsub.Broker {
+	if config.NotificationPubSub != "mongo" {
+		return pubsub.NewMemory()
+	}
+	return pubsub.NewChangeStream(GetMongoCollection("notifications"), func(doc bson.Raw) (string, pubsub.Message, bool) {
+		var n model.Notification
+		if err := bson.Unmarshal(doc, &n); err != nil {
+			return "", pubsub.Message{}, false
+		}
+		return n.UserID.Hex(), notificationMessage(n), true
+	})
+}
+
+func notificationMessage(n model.Notification) pubsub.Message {
+	data, _ := json.Marshal(n)
+	return pubsub.Message{ID: n.ID.Hex(), Data: data}
+}
+
+// publishNotification mengirim notifikasi yang baru disimpan ke stream SSE user
+func publishNotification(n model.Notification) {
+	notificationBroker.Publish(n.UserID.Hex(), notificationMessage(n))
+}
+
+func writeSSE(w http.ResponseWriter, event string, msg pubsub.Message) {
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", msg.ID, event, msg.Data)
+}
+
+// StreamNotifications godoc
+// @Summary Stream Notifikasi (SSE)
+// @Description Server-Sent Events berisi notifikasi baru (event "notification"). Kirim header Last-Event-ID (atau query last_event_id) untuk menerima notifikasi yang terlewat. EventSource tidak bisa mengirim header Authorization, jadi token boleh dikirim lewat query token
+// @Tags Notification
+// @Produce text/event-stream
+// @Param token query string false "Token login (alternatif header Authorization)"
+// @Param last_event_id query string false "ID notifikasi terakhir yang diterima"
+// @Success 200 {string} string "text/event-stream"
+// @Failure 401 {object} model.ResponseMessage
+// @Router /pdfm/notifications/stream [get]
+// @Security BearerAuth
+func StreamNotifications(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") == "" && r.URL.Query().Get("token") != "" {
+		r.Header.Set("Authorization", "Bearer "+r.URL.Query().Get("token"))
+	}
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+	userID := user.ID
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.StreamUnsupported)
+		return
+	}
+
+	// Subscribe sebelum membaca backlog agar tidak ada notifikasi yang jatuh di antaranya
+	events, cancel := notificationBroker.Subscribe(userID.Hex())
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 5000\n\n")
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+	if lastOID, err := primitive.ObjectIDFromHex(lastID); err == nil {
+		opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(sseResumeLimit)
+		cursor, err := GetMongoCollection("notifications").Find(r.Context(), bson.M{"user_id": userID, "_id": bson.M{"$gt": lastOID}}, opts)
+		if err == nil {
+			var missed []model.Notification
+			cursor.All(r.Context(), &missed)
+			for _, n := range missed {
+				msg := notificationMessage(n)
+				writeSSE(w, "notification", msg)
+				lastID = msg.ID
+			}
+		}
+	} else {
+		lastID = ""
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(config.SSEHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case msg, open := <-events:
+			if !open {
+				return
+			}
+			// ObjectID hex berurutan waktu; lewati yang sudah terkirim dari backlog
+			if lastID != "" && msg.ID <= lastID {
+				continue
+			}
+			writeSSE(w, "notification", msg)
+			lastID = msg.ID
+			flusher.Flush()
+		}
+	}
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -39,13 +40,13 @@ func HandleOrder(w http.ResponseWriter, r *http.Request) {
 		IsGroup:  false,
 		Messages: message,
 	}
-	_, _, err = atapi.PostStructWithToken[model.Response]("token", config.WAAPIToken, newmsg, config.WAAPIMessage)
+	_, _, err = atapi.PostStructWithToken[model.Response]("token", config.WAAPIToken(), newmsg, config.WAAPIMessage)
 	if err != nil {
 		http.Error(w, "Gagal Mengirim pesan", http.StatusBadRequest)
 		return
 	}
-	// Cetak data order ke terminal (bisa diganti dengan logic lain, misal menyimpan ke database)
-	fmt.Printf("Received Order: %+v\n", orderRequest)
+	// Data pembeli (nama, nomor WA, alamat) tidak ikut dicatat di log
+	slog.InfoContext(r.Context(), "order diterima", "lapak", namalapak, "items", len(orderRequest.Orders), "total", orderRequest.Total)
 
 	// Kirim response kembali ke client
 	w.Header().Set("Content-Type", "application/json")
@@ -2,17 +2,21 @@ package controller
 
 import (
 	"encoding/json"
-	"fmt"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/gocroot/config"
 	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/authn"
+	"github.com/gocroot/helper/i18n"
 	"github.com/gocroot/model"
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	// "golang.org/x/crypto/bcrypt"
 )
 
@@ -27,23 +31,20 @@ import (
 // @Failure 400 {object} model.ResponseMessage
 // @Router /pdfm/register [post]
 func RegisterHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Metode tidak diizinkan", http.StatusMethodNotAllowed)
-		return
-	}
+	lang := requestLocale(r, nil)
 
 	// PERBAIKAN: Gunakan model.RegisterInput sesuai Swagger
 	var req model.RegisterInput
 	decoder := json.NewDecoder(r.Body)
 	err := decoder.Decode(&req)
 	if err != nil {
-		http.Error(w, "Data tidak valid: "+err.Error(), http.StatusBadRequest)
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidBody, err)
 		return
 	}
 
 	// Validasi field wajib
 	if req.Name == "" || req.Email == "" || req.Password == "" {
-		http.Error(w, "Name, Email, dan Password wajib diisi", http.StatusBadRequest)
+		writeMessage(w, lang, http.StatusBadRequest, i18n.RegisterRequired)
 		return
 	}
 
@@ -62,12 +63,11 @@ func RegisterHandler(w http.ResponseWriter, r *http.Request) {
 	// Simpan data ke database
 	_, err = atdb.InsertOneDoc(config.Mongoconn, "users", registrationData)
 	if err != nil {
-		http.Error(w, "Gagal menyimpan data: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, lang, http.StatusInternalServerError, i18n.SaveFailed, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Registrasi berhasil"})
+	writeMessage(w, lang, http.StatusOK, i18n.RegisterSuccess)
 }
 
 // GetUser menangani login dan menghasilkan token sederhana
@@ -81,15 +81,18 @@ func RegisterHandler(w http.ResponseWriter, r *http.Request) {
 // @Failure 401 {object} model.ResponseMessage
 // @Router /pdfm/login [post]
 func GetUser(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Metode tidak diizinkan", http.StatusMethodNotAllowed)
-		return
-	}
+	lang := requestLocale(r, nil)
 
 	// PERBAIKAN: Gunakan model.LoginInput sesuai Swagger
 	var req model.LoginInput
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Data tidak valid: "+err.Error(), http.StatusBadRequest)
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidBody, err)
+		return
+	}
+
+	// Akun yang dibuat lewat login Google tidak punya password
+	if req.Password == "" {
+		writeMessage(w, lang, http.StatusUnauthorized, i18n.LoginInvalid)
 		return
 	}
 
@@ -97,12 +100,22 @@ func GetUser(w http.ResponseWriter, r *http.Request) {
 	filter := bson.M{"email": req.Email, "password": req.Password}
 	var user model.PdfmUsers
 	user, err := atdb.GetOneDoc[model.PdfmUsers](config.Mongoconn, "users", filter)
-	if err != nil {
-		http.Error(w, "Email atau password salah", http.StatusUnauthorized)
+	if err != nil || !user.DeletedAt.IsZero() {
+		writeMessage(w, lang, http.StatusUnauthorized, i18n.LoginInvalid)
+		return
+	}
+	if user.Suspended {
+		writeMessage(w, lang, http.StatusForbidden, i18n.AccountSuspended)
 		return
 	}
 
-	// Buat token unik (UUID)
+	startSession(w, r, user, "password", model.LoginResponse{})
+}
+
+// startSession membuat token sesi (UUID) untuk user, mencatat login di background lalu menulis LoginResponse.
+// Dipakai login email/password dan login Google sehingga keduanya menghasilkan sesi yang sama.
+func startSession(w http.ResponseWriter, r *http.Request, user model.PdfmUsers, method string, response model.LoginResponse) {
+	lang := requestLocale(r, &user)
 	token := uuid.New().String()
 	expiresAt := time.Now().Add(24 * time.Hour)
 
@@ -111,11 +124,12 @@ func GetUser(w http.ResponseWriter, r *http.Request) {
 		Email:     user.Email,
 		ExpiresAt: expiresAt,
 	}
-	_, err = atdb.InsertOneDoc(config.Mongoconn, "tokens", tokenData)
+	_, err := atdb.InsertOneDoc(config.Mongoconn, "tokens", tokenData)
 	if err != nil {
-		http.Error(w, "Gagal menyimpan token", http.StatusInternalServerError)
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.TokenSaveFailed)
 		return
 	}
+	ensureTokenTTLIndex()
 
 	// Autologing background
 	go func() {
@@ -127,16 +141,16 @@ func GetUser(w http.ResponseWriter, r *http.Request) {
 			IPAddress: r.RemoteAddr,
 			UserAgent: r.UserAgent(),
 			LoginAt:   time.Now(),
+			Method:    method,
 		}
 		atdb.InsertOneDoc(config.Mongoconn, "login_logs", loginLog)
 	}()
 
-	response := model.LoginResponse{
-		Token:    token,
-		UserName: user.Name,
-		IsAdmin:  user.IsAdmin,
-		Message:  "Login berhasil",
-	}
+	response.Token = token
+	response.UserName = user.Name
+	response.IsAdmin = user.IsAdmin
+	response.Message = i18n.T(lang, i18n.LoginSuccess)
+	response.Language = lang
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
@@ -152,41 +166,49 @@ func GetUser(w http.ResponseWriter, r *http.Request) {
 // @Router /pdfm/logout [post]
 // @Security BearerAuth
 func LogoutHandler(w http.ResponseWriter, r *http.Request) {
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		http.Error(w, "Token tidak ditemukan", http.StatusBadRequest)
+	lang := requestLocale(r, nil)
+	token, err := authn.BearerToken(r)
+	if errors.Is(err, authn.ErrNoCredentials) {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.TokenMissing)
 		return
 	}
-
-	const bearerPrefix = "Bearer "
-	if len(authHeader) <= len(bearerPrefix) || authHeader[:len(bearerPrefix)] != bearerPrefix {
-		http.Error(w, "Format token tidak valid", http.StatusUnauthorized)
+	if err != nil {
+		writeMessage(w, lang, http.StatusUnauthorized, i18n.TokenInvalidFormat)
 		return
 	}
-	token := authHeader[len(bearerPrefix):]
 
-	_, err := atdb.DeleteOneDoc(config.Mongoconn, "tokens", bson.M{"token": token})
+	_, err = atdb.DeleteOneDoc(config.Mongoconn, "tokens", bson.M{"token": token})
 	if err != nil {
-		http.Error(w, "Gagal logout", http.StatusInternalServerError)
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.LogoutFailed)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(model.ResponseMessage{Message: "Logout berhasil"})
+	writeMessage(w, lang, http.StatusOK, i18n.LogoutSuccess)
 }
 
 // GetUsers godoc
 // @Summary Ambil Semua Data User (Admin)
-// @Description Mengambil list semua pengguna yang terdaftar
+// @Description Mengambil list semua pengguna yang terdaftar (tanpa password dan tanpa user yang dihapus). Untuk pencarian dan pagination gunakan /pdfm/admin/users
 // @Tags User Management
 // @Accept json
 // @Produce json
 // @Success 200 {array} model.PdfmUsers
+// @Failure 403 {object} model.ResponseMessage
 // @Router /pdfm/get/users [get]
+// @Security BearerAuth
 func GetUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := atdb.GetAllDoc[[]model.PdfmUsers](config.Mongoconn, "users", bson.M{})
+	_, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+	cursor, err := GetMongoCollection("users").Find(r.Context(), notDeleted, options.Find().SetProjection(bson.M{"password": 0}))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
+		return
+	}
+	users := []model.PdfmUsers{}
+	if err := cursor.All(r.Context(), &users); err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -202,35 +224,40 @@ func GetUsers(w http.ResponseWriter, r *http.Request) {
 // @Param id query string false "User ID"
 // @Param name query string false "User Name"
 // @Success 200 {object} model.PdfmUsers
+// @Failure 403 {object} model.ResponseMessage
 // @Router /pdfm/getoneadmin/users [get]
+// @Security BearerAuth
 func GetOneUserAdmin(w http.ResponseWriter, r *http.Request) {
+	_, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
 	id := r.URL.Query().Get("id")
 	var filter bson.M
 
 	if id != "" {
 		objectID, err := primitive.ObjectIDFromHex(id)
 		if err != nil {
-			http.Error(w, "Invalid user ID format", http.StatusBadRequest)
+			writeMessage(w, lang, http.StatusBadRequest, i18n.UserInvalidID)
 			return
 		}
 		filter = bson.M{"_id": objectID}
 	} else {
 		name := r.URL.Query().Get("name")
 		if name == "" {
-			http.Error(w, "Missing user identifier", http.StatusBadRequest)
+			writeMessage(w, lang, http.StatusBadRequest, i18n.UserIDRequired)
 			return
 		}
 		filter = bson.M{"name": bson.M{"$regex": name, "$options": "i"}}
 	}
 
-	fmt.Printf("Filter: %+v\n", filter)
-
 	user, err := atdb.GetOneDoc[model.PdfmUsers](config.Mongoconn, "users", filter)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		http.Error(w, "User not found", http.StatusNotFound)
+		slog.DebugContext(r.Context(), "user tidak ditemukan", "error", err)
+		writeMessage(w, lang, http.StatusNotFound, i18n.UserNotFound)
 		return
 	}
+	user.Password = ""
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(user)
@@ -247,30 +274,11 @@ func GetOneUserAdmin(w http.ResponseWriter, r *http.Request) {
 // @Router /pdfm/getone/users [get]
 // @Security BearerAuth
 func GetOneUser(w http.ResponseWriter, r *http.Request) {
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		http.Error(w, "Missing token", http.StatusUnauthorized)
-		return
-	}
-
-	const bearerPrefix = "Bearer "
-	if len(authHeader) <= len(bearerPrefix) || authHeader[:len(bearerPrefix)] != bearerPrefix {
-		http.Error(w, "Invalid token format", http.StatusUnauthorized)
-		return
-	}
-	token := authHeader[len(bearerPrefix):]
-
-	tokenData, err := atdb.GetOneDoc[model.Token](config.Mongoconn, "tokens", bson.M{"token": token})
-	if err != nil || tokenData.ExpiresAt.Before(time.Now()) {
-		http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
-		return
-	}
-
-	user, err := atdb.GetOneDoc[model.PdfmUsers](config.Mongoconn, "users", bson.M{"email": tokenData.Email})
-	if err != nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+	user, _, ok := sessionUser(w, r)
+	if !ok {
 		return
 	}
+	user.Password = ""
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(user)
@@ -284,12 +292,18 @@ func GetOneUser(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param request body model.RegisterInput true "Create Payload"
 // @Success 200 {object} model.PdfmUsers
+// @Failure 403 {object} model.ResponseMessage
 // @Router /pdfm/create/users [post]
+// @Security BearerAuth
 func CreateUser(w http.ResponseWriter, r *http.Request) {
+	admin, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
 	// PERBAIKAN: Gunakan model.RegisterInput
 	var req model.RegisterInput
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidBody, err)
 		return
 	}
 
@@ -305,53 +319,61 @@ func CreateUser(w http.ResponseWriter, r *http.Request) {
 
 	count, err := atdb.GetCountDoc(config.Mongoconn, "users", bson.M{"email": newUser.Email})
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
 		return
 	}
 	if count > 0 {
-		http.Error(w, "Email already exists", http.StatusConflict)
+		writeMessage(w, lang, http.StatusConflict, i18n.UserEmailExists)
 		return
 	}
 
 	if _, err := atdb.InsertOneDoc(config.Mongoconn, "users", newUser); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
 		return
 	}
 
+	recordAudit(r, &admin, auditUserCreate, "user", newUser.ID.Hex(), map[string]any{"email": newUser.Email})
+	newUser.Password = ""
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(newUser)
 }
 
 // UpdateUser godoc
-// @Summary Update Data User
+// @Summary Update Data User (Admin)
 // @Description Memperbarui data user (nama, password, dll)
 // @Tags User Management
 // @Accept json
 // @Produce json
 // @Param request body model.UpdateUserInput true "Update Payload"
 // @Success 200 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
 // @Router /pdfm/update/users [put]
+// @Security BearerAuth
 func UpdateUser(w http.ResponseWriter, r *http.Request) {
+	admin, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
 	// PERBAIKAN: Gunakan model.UpdateUserInput
 	var req model.UpdateUserInput
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeMessage(w, lang, http.StatusBadRequest, i18n.InvalidBody)
 		return
 	}
 
 	if req.ID == "" {
-		http.Error(w, "User ID is required", http.StatusBadRequest)
+		writeMessage(w, lang, http.StatusBadRequest, i18n.UserIDRequired)
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(req.ID)
 	if err != nil {
-		http.Error(w, "Invalid ID format", http.StatusBadRequest)
+		writeMessage(w, lang, http.StatusBadRequest, i18n.UserInvalidID)
 		return
 	}
 
 	if req.Name == "" || req.Email == "" {
-		http.Error(w, "Name and Email cannot be empty", http.StatusBadRequest)
+		writeMessage(w, lang, http.StatusBadRequest, i18n.UserNameEmailEmpty)
 		return
 	}
 
@@ -368,49 +390,64 @@ func UpdateUser(w http.ResponseWriter, r *http.Request) {
 
 	result, err := atdb.UpdateWithPipeline(config.Mongoconn, "users", filter, []bson.M{pipeline})
 	if err != nil {
-		http.Error(w, "Failed to update user: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, lang, http.StatusInternalServerError, i18n.UserUpdateFailed, err)
 		return
 	}
 
 	if result.MatchedCount == 0 {
-		http.Error(w, "User not found", http.StatusNotFound)
+		writeMessage(w, lang, http.StatusNotFound, i18n.UserNotFound)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(model.ResponseMessage{Message: "User updated successfully"})
+	recordAudit(r, &admin, auditUserUpdate, "user", req.ID, map[string]any{
+		"email":            req.Email,
+		"is_support":       req.IsSupport,
+		"password_changed": req.Password != "",
+	})
+	writeMessage(w, lang, http.StatusOK, i18n.UserUpdated)
 }
 
 // DeleteUser godoc
-// @Summary Hapus User
-// @Description Menghapus user berdasarkan ID
+// @Summary Hapus User (Admin)
+// @Description Menghapus user berdasarkan ID (soft delete, bisa dipulihkan lewat /pdfm/admin/users/{id}/restore)
 // @Tags User Management
 // @Accept json
 // @Produce json
 // @Param request body model.DeleteUserInput true "Payload Hapus"
 // @Success 200 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
 // @Router /pdfm/delete/users [delete]
+// @Security BearerAuth
 func DeleteUser(w http.ResponseWriter, r *http.Request) {
+	admin, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
 	// PERBAIKAN: Gunakan model.DeleteUserInput
 	var req model.DeleteUserInput
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidBody, err)
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(req.ID)
 	if err != nil {
-		http.Error(w, "Invalid user ID format", http.StatusBadRequest)
+		writeMessage(w, lang, http.StatusBadRequest, i18n.UserInvalidID)
 		return
 	}
 
-	if _, err := atdb.DeleteOneDoc(config.Mongoconn, "users", bson.M{"_id": objectID}); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	// User yang tidak ada atau sudah dihapus tetap dianggap berhasil seperti hard delete sebelumnya
+	user, err := softDeleteUser(r.Context(), objectID, admin.ID)
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+	case err != nil:
+		writeError(w, lang, http.StatusInternalServerError, i18n.InternalError, err)
 		return
+	default:
+		recordAudit(r, &admin, auditUserRemove, "user", req.ID, map[string]any{"email": user.Email})
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(model.ResponseMessage{Message: "User deleted successfully"})
+	writeMessage(w, lang, http.StatusOK, i18n.UserDeleted)
 }
 
 // ConfirmPaymentHandler godoc
@@ -423,20 +460,17 @@ func DeleteUser(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} model.PaymentResponse
 // @Router /pdfm/payment [post]
 func ConfirmPaymentHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
+	lang := requestLocale(r, nil)
 
 	// PERBAIKAN: Gunakan model.PaymentInput
 	var req model.PaymentInput
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid input: "+err.Error(), http.StatusBadRequest)
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidBody, err)
 		return
 	}
 
 	if req.Amount < 1 {
-		http.Error(w, "Minimal donasi adalah Rp1", http.StatusBadRequest)
+		writeMessage(w, lang, http.StatusBadRequest, i18n.PaymentMinimum)
 		return
 	}
 
@@ -444,13 +478,10 @@ func ConfirmPaymentHandler(w http.ResponseWriter, r *http.Request) {
 	var user model.PdfmUsers
 	user, err := atdb.GetOneDoc[model.PdfmUsers](config.Mongoconn, "users", filter)
 	if err != nil {
-		log.Printf("Error finding user: %v", err)
-		http.Error(w, "User not found: "+err.Error(), http.StatusNotFound)
+		writeError(w, lang, http.StatusNotFound, i18n.UserNotFound, err)
 		return
 	}
 
-	log.Printf("[ConfirmPaymentHandler] User found: ID=%s, Name=%s, Email='%s'", user.ID.Hex(), user.Name, user.Email)
-
 	pipeline := []bson.M{
 		{"$set": bson.M{
 			"isSupport": true,
@@ -460,8 +491,8 @@ func ConfirmPaymentHandler(w http.ResponseWriter, r *http.Request) {
 
 	_, err = atdb.UpdateWithPipeline(config.Mongoconn, "users", filter, pipeline)
 	if err != nil {
-		log.Printf("Error updating user: %v", err)
-		http.Error(w, "Failed to update user: "+err.Error(), http.StatusInternalServerError)
+		slog.ErrorContext(r.Context(), "gagal mengubah status supporter", "user_id", user.ID.Hex(), "error", err)
+		writeError(w, lang, http.StatusInternalServerError, i18n.UserUpdateFailed, err)
 		return
 	}
 
@@ -476,18 +507,21 @@ func ConfirmPaymentHandler(w http.ResponseWriter, r *http.Request) {
 		CreatedAt:     time.Now(),
 	}
 
-	insertedID, err := atdb.InsertOneDoc(config.Mongoconn, "invoices", invoice)
-	if err != nil {
-		log.Printf("Error creating invoice: %v", err)
-		http.Error(w, "Failed to create invoice: "+err.Error(), http.StatusInternalServerError)
+	if _, err := atdb.InsertOneDoc(config.Mongoconn, "invoices", invoice); err != nil {
+		slog.ErrorContext(r.Context(), "gagal membuat invoice", "user_id", user.ID.Hex(), "error", err)
+		writeError(w, lang, http.StatusInternalServerError, i18n.SaveFailed, err)
 		return
 	}
-
-	log.Printf("[ConfirmPaymentHandler] Invoice created successfully with ID: %s", insertedID.Hex())
+	recordAudit(r, nil, auditPaymentConfirm, "user", user.ID.Hex(), map[string]any{
+		"invoice_id": invoice.ID.Hex(),
+		"amount":     invoice.Amount,
+		"status":     invoice.Status,
+		"is_support": true,
+	})
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(model.PaymentResponse{
-		Message:     "Pembayaran telah dilakukan, terima kasih!",
+		Message:     i18n.T(lang, i18n.PaymentSuccess),
 		InvoiceId:   invoice.ID,
 		InvoiceDate: invoice.CreatedAt,
 		AmountPaid:  invoice.Amount,
@@ -504,33 +538,8 @@ func ConfirmPaymentHandler(w http.ResponseWriter, r *http.Request) {
 // @Router /pdfm/invoices [get]
 // @Security BearerAuth
 func GetInvoicesHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		http.Error(w, "Missing token", http.StatusUnauthorized)
-		return
-	}
-
-	const bearerPrefix = "Bearer "
-	if len(authHeader) <= len(bearerPrefix) || authHeader[:len(bearerPrefix)] != bearerPrefix {
-		http.Error(w, "Invalid token format", http.StatusUnauthorized)
-		return
-	}
-	token := authHeader[len(bearerPrefix):]
-
-	tokenData, err := atdb.GetOneDoc[model.Token](config.Mongoconn, "tokens", bson.M{"token": token})
-	if err != nil || tokenData.ExpiresAt.Before(time.Now()) {
-		http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
-		return
-	}
-
-	user, err := atdb.GetOneDoc[model.PdfmUsers](config.Mongoconn, "users", bson.M{"email": tokenData.Email})
-	if err != nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
 		return
 	}
 
@@ -548,7 +557,7 @@ func GetInvoicesHandler(w http.ResponseWriter, r *http.Request) {
 
 	invoices, err := atdb.GetAllDoc[[]model.Invoice](config.Mongoconn, "invoices", filter)
 	if err != nil {
-		http.Error(w, "Oops! We couldn't fetch the invoices.", http.StatusInternalServerError)
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.InvoiceFetchFailed)
 		return
 	}
 
@@ -559,80 +568,43 @@ func GetInvoicesHandler(w http.ResponseWriter, r *http.Request) {
 // UploadProfilePhotoHandler handles uploading profile photo (Base64)
 // UploadProfilePhotoHandler godoc
 // @Summary Upload Foto Profil
-// @Description Mengganti foto profil (Format Base64)
+// @Description Mengganti foto profil. Kirim JSON base64/data URL atau multipart field "photo" (JPEG, PNG, GIF, WebP, maks 5 MB). Foto divalidasi, EXIF dibuang, lalu disimpan sebagai avatar 64 dan 256 px
 // @Tags User Profile
 // @Accept json
+// @Accept multipart/form-data
 // @Produce json
 // @Param request body model.UploadProfilePhotoInput true "Payload Foto Base64"
 // @Success 200 {object} model.ProfilePhotoResponse
+// @Failure 413 {object} model.ResponseMessage
+// @Failure 422 {object} model.ResponseMessage
 // @Router /pdfm/profile/photo [post]
 // @Security BearerAuth
 func UploadProfilePhotoHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		http.Error(w, "Missing token", http.StatusUnauthorized)
-		return
-	}
-
-	const bearerPrefix = "Bearer "
-	if len(authHeader) <= len(bearerPrefix) || authHeader[:len(bearerPrefix)] != bearerPrefix {
-		http.Error(w, "Invalid token format", http.StatusUnauthorized)
-		return
-	}
-	token := authHeader[len(bearerPrefix):]
-
-	tokenData, err := atdb.GetOneDoc[model.Token](config.Mongoconn, "tokens", bson.M{"token": token})
-	if err != nil || tokenData.ExpiresAt.Before(time.Now()) {
-		http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
-		return
-	}
-
-	// PERBAIKAN: Gunakan model.UploadProfilePhotoInput
-	var req model.UploadProfilePhotoInput
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	if req.ProfilePhoto == "" {
-		http.Error(w, "Profile photo is required", http.StatusBadRequest)
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
 		return
 	}
 
-	filter := bson.M{"email": tokenData.Email}
-	pipeline := []bson.M{
-		{"$set": bson.M{
-			"profilePhoto": req.ProfilePhoto,
-			"updatedAt":    time.Now(),
-		}},
-	}
-
-	result, err := atdb.UpdateWithPipeline(config.Mongoconn, "users", filter, pipeline)
-	if err != nil {
-		http.Error(w, "Failed to update profile photo: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	if result.MatchedCount == 0 {
-		http.Error(w, "User not found", http.StatusNotFound)
-		return
+	data, err := readProfilePhoto(w, r)
+	if err == nil {
+		var photo []model.PhotoVariant
+		if photo, err = saveProfilePhoto(r.Context(), user, data); err == nil {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(model.ProfilePhotoResponse{
+				Message: i18n.T(lang, i18n.PhotoUpdated),
+				Photos:  photo,
+			})
+			return
+		}
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(model.ProfilePhotoResponse{
-		Message: "Profile photo updated successfully",
-	})
+	status, code := photoErrorCode(err)
+	writeError(w, lang, status, code, err)
 }
 
 // GetProfilePhotoHandler returns the profile photo for authenticated user
 // GetProfilePhotoHandler godoc
 // @Summary Lihat Foto Profil
-// @Description Mengambil string Base64 foto profil user
+// @Description Mengambil URL avatar per ukuran beserta avatar 256 px sebagai data URL (untuk client lama). Gunakan /pdfm/profile/photo/{size} untuk mengambil gambarnya langsung
 // @Tags User Profile
 // @Accept json
 // @Produce json
@@ -640,38 +612,22 @@ func UploadProfilePhotoHandler(w http.ResponseWriter, r *http.Request) {
 // @Router /pdfm/profile/photo [get]
 // @Security BearerAuth
 func GetProfilePhotoHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	user, _, ok := sessionUser(w, r)
+	if !ok {
 		return
 	}
 
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		http.Error(w, "Missing token", http.StatusUnauthorized)
-		return
-	}
-
-	const bearerPrefix = "Bearer "
-	if len(authHeader) <= len(bearerPrefix) || authHeader[:len(bearerPrefix)] != bearerPrefix {
-		http.Error(w, "Invalid token format", http.StatusUnauthorized)
-		return
-	}
-	token := authHeader[len(bearerPrefix):]
-
-	tokenData, err := atdb.GetOneDoc[model.Token](config.Mongoconn, "tokens", bson.M{"token": token})
-	if err != nil || tokenData.ExpiresAt.Before(time.Now()) {
-		http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
-		return
-	}
-
-	user, err := atdb.GetOneDoc[model.PdfmUsers](config.Mongoconn, "users", bson.M{"email": tokenData.Email})
-	if err != nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+	if err := migrateLegacyPhoto(r.Context(), &user); err != nil {
+		// Data lama yang tidak bisa diproses tetap dikirim apa adanya
+		slog.ErrorContext(r.Context(), "gagal migrasi foto profil lama", "user_id", user.ID.Hex(), "error", err)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(model.ProfilePhotoResponse{ProfilePhoto: user.ProfilePhoto})
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(model.ProfilePhotoResponse{
-		ProfilePhoto: user.ProfilePhoto,
+		ProfilePhoto: photoDataURL(r.Context(), user.Photo),
+		Photos:       withPhotoURLs(user.Photo),
 	})
 }
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/storage"
+	"github.com/gocroot/model"
+)
+
+// Purpose file di storage yang dipakai tool pdfm
+const (
+	fileInputPurpose  = "tool-input"
+	fileOutputPurpose = "tool-output"
+)
+
+func toStoredFile(obj storage.Object) model.StoredFile {
+	return model.StoredFile{
+		ID:          obj.ID,
+		Name:        obj.Name,
+		ContentType: obj.ContentType,
+		Size:        obj.Size,
+		ExpiresAt:   obj.ExpiresAt,
+	}
+}
+
+// UploadToolFile godoc
+// @Summary Unggah File Input Tool
+// @Description Menyimpan file PDF input sementara (sesuai masa retensi) agar operasinya bisa dijalankan ulang. ID file dikirim di field "inputs" saat menyimpan log
+// @Tags Files
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "File PDF"
+// @Success 200 {object} model.StoredFileResponse
+// @Failure 400 {object} model.ResponseMessage
+// @Failure 401 {object} model.ResponseMessage
+// @Failure 413 {object} model.ResponseMessage
+// @Router /pdfm/files [post]
+// @Security BearerAuth
+func UploadToolFile(w http.ResponseWriter, r *http.Request) {
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+	if !apiKeyAllows(r, scopeFiles) {
+		writeMessage(w, lang, http.StatusForbidden, i18n.APIKeyScope, "scope", scopeFiles)
+		return
+	}
+	maxMB := strconv.FormatInt(config.MaxUploadSize>>20, 10)
+
+	r.Body = http.MaxBytesReader(w, r.Body, config.MaxUploadSize+1<<20)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeMessage(w, lang, http.StatusRequestEntityTooLarge, i18n.FileTooLarge, "max", maxMB)
+			return
+		}
+		writeMessage(w, lang, http.StatusBadRequest, i18n.FileRequired)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, config.MaxUploadSize+1))
+	if err != nil {
+		writeError(w, lang, http.StatusBadRequest, i18n.FileReadFailed, err)
+		return
+	}
+	if int64(len(data)) > config.MaxUploadSize {
+		writeMessage(w, lang, http.StatusRequestEntityTooLarge, i18n.FileTooLarge, "max", maxMB)
+		return
+	}
+	if http.DetectContentType(data) != "application/pdf" {
+		writeMessage(w, lang, http.StatusBadRequest, i18n.FilePDFOnly)
+		return
+	}
+
+	obj, err := config.FileStore.Put(r.Context(), storage.Object{
+		Name:        header.Filename,
+		ContentType: "application/pdf",
+		Owner:       user.ID.Hex(),
+		Purpose:     fileInputPurpose,
+		ExpiresAt:   time.Now().Add(config.FileRetention),
+	}, data)
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.SaveFailed, err)
+		return
+	}
+
+	w.Header().Set("Content-Language", lang)
+	at.WriteJSON(w, http.StatusOK, model.StoredFileResponse{
+		Message: i18n.T(lang, i18n.FileUploaded),
+		File:    toStoredFile(obj),
+	})
+}
+
+// DownloadToolFile godoc
+// @Summary Unduh File Input/Output Tool
+// @Description Mengunduh file milik user selama masih dalam masa retensi
+// @Tags Files
+// @Produce application/pdf
+// @Param id path string true "ID file"
+// @Success 200 {file} file
+// @Failure 401 {object} model.ResponseMessage
+// @Failure 404 {object} model.ResponseMessage
+// @Failure 410 {object} model.ResponseMessage
+// @Router /pdfm/files/{id} [get]
+// @Security BearerAuth
+func DownloadToolFile(w http.ResponseWriter, r *http.Request) {
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+	if !apiKeyAllows(r, scopeFiles) {
+		writeMessage(w, lang, http.StatusForbidden, i18n.APIKeyScope, "scope", scopeFiles)
+		return
+	}
+
+	obj, data, err := config.FileStore.Get(r.Context(), at.GetParam(r))
+	if errors.Is(err, storage.ErrExpired) {
+		writeMessage(w, lang, http.StatusGone, i18n.FileExpired)
+		return
+	}
+	if err != nil || obj.Owner != user.ID.Hex() || (obj.Purpose != fileInputPurpose && obj.Purpose != fileOutputPurpose) {
+		writeMessage(w, lang, http.StatusNotFound, i18n.FileNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", obj.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": obj.Name}))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
+}
@@ -0,0 +1,288 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/avatar"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/storage"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const profilePhotoPurpose = "profile-photo"
+
+// errPhotoTooLarge dikembalikan readProfilePhoto jika foto melebihi config.ProfilePhotoMaxSize
+var errPhotoTooLarge = errors.New("foto melebihi batas ukuran")
+
+// readProfilePhoto membaca foto dari multipart field "photo" atau dari JSON profilePhoto (data URL/base64)
+func readProfilePhoto(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		r.Body = http.MaxBytesReader(w, r.Body, config.ProfilePhotoMaxSize+1<<20)
+		file, _, err := r.FormFile("photo")
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return nil, errPhotoTooLarge
+			}
+			return nil, avatar.ErrEmpty
+		}
+		defer file.Close()
+		data, err := io.ReadAll(io.LimitReader(file, config.ProfilePhotoMaxSize+1))
+		if err != nil {
+			return nil, avatar.ErrInvalidData
+		}
+		if int64(len(data)) > config.ProfilePhotoMaxSize {
+			return nil, errPhotoTooLarge
+		}
+		return data, nil
+	}
+
+	// Base64 sekitar 4/3 ukuran aslinya
+	r.Body = http.MaxBytesReader(w, r.Body, config.ProfilePhotoMaxSize*4/3+64<<10)
+	var req model.UploadProfilePhotoInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, errPhotoTooLarge
+		}
+		return nil, avatar.ErrInvalidData
+	}
+	data, err := avatar.DecodeDataURL(req.ProfilePhoto)
+	if err == nil && int64(len(data)) > config.ProfilePhotoMaxSize {
+		return nil, errPhotoTooLarge
+	}
+	return data, err
+}
+
+// putAvatar memproses foto menjadi avatar lalu menyimpan setiap ukurannya ke storage.
+// Jika salah satu gagal disimpan, file yang sudah tersimpan dihapus lagi.
+func putAvatar(ctx context.Context, owner primitive.ObjectID, data []byte) ([]model.PhotoVariant, error) {
+	variants, err := avatar.Process(data)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make([]model.PhotoVariant, 0, len(variants))
+	for _, v := range variants {
+		obj, err := config.FileStore.Put(ctx, storage.Object{
+			Name:        "avatar-" + strconv.Itoa(v.Size) + ".jpg",
+			ContentType: avatar.ContentType,
+			Owner:       owner.Hex(),
+			Purpose:     profilePhotoPurpose,
+		}, v.Data)
+		if err != nil {
+			deleteAvatar(ctx, stored)
+			return nil, err
+		}
+		stored = append(stored, model.PhotoVariant{Size: v.Size, FileID: obj.ID, ETag: v.ETag})
+	}
+	return stored, nil
+}
+
+// deleteAvatar menghapus file avatar dari storage; file yang sudah tidak ada diabaikan
+func deleteAvatar(ctx context.Context, photo []model.PhotoVariant) {
+	for _, v := range photo {
+		if err := config.FileStore.Delete(ctx, v.FileID); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			slog.ErrorContext(ctx, "gagal menghapus avatar", "file_id", v.FileID, "error", err)
+		}
+	}
+}
+
+// photoUpdate mengganti referensi avatar user dan membuang data URL lama
+func photoUpdate(photo []model.PhotoVariant) bson.M {
+	return bson.M{
+		"$set":   bson.M{"photo": photo, "updatedAt": time.Now()},
+		"$unset": bson.M{"profilePhoto": ""},
+	}
+}
+
+// saveProfilePhoto memproses foto menjadi avatar, menyimpannya ke storage lalu mengganti referensi di user.
+// File avatar lama dihapus setelah referensi baru tersimpan.
+func saveProfilePhoto(ctx context.Context, user model.PdfmUsers, data []byte) ([]model.PhotoVariant, error) {
+	stored, err := putAvatar(ctx, user.ID, data)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := GetMongoCollection("users").UpdateOne(ctx, bson.M{"_id": user.ID}, photoUpdate(stored)); err != nil {
+		deleteAvatar(ctx, stored)
+		return nil, err
+	}
+	deleteAvatar(ctx, user.Photo)
+	return withPhotoURLs(stored), nil
+}
+
+// migrateLegacyPhoto mengubah data URL lama di profilePhoto menjadi avatar di storage saat pertama kali diakses.
+// Fungsi ini dipanggil dari handler GET yang bisa berjalan bersamaan, jadi referensi hanya disimpan jika user
+// belum punya photo. Request yang kalah menghapus upload-nya sendiri dan memakai avatar yang sudah tersimpan.
+func migrateLegacyPhoto(ctx context.Context, user *model.PdfmUsers) error {
+	if len(user.Photo) > 0 || user.ProfilePhoto == "" {
+		return nil
+	}
+	data, err := avatar.DecodeDataURL(user.ProfilePhoto)
+	if err != nil {
+		return err
+	}
+	stored, err := putAvatar(ctx, user.ID, data)
+	if err != nil {
+		return err
+	}
+	users := GetMongoCollection("users")
+	res, err := users.UpdateOne(ctx, bson.M{"_id": user.ID, "photo": bson.M{"$exists": false}}, photoUpdate(stored))
+	if err != nil {
+		deleteAvatar(ctx, stored)
+		return err
+	}
+	if res.MatchedCount == 0 {
+		deleteAvatar(ctx, stored)
+		var current model.PdfmUsers
+		if err := users.FindOne(ctx, bson.M{"_id": user.ID}).Decode(&current); err != nil {
+			return err
+		}
+		user.Photo, user.ProfilePhoto = withPhotoURLs(current.Photo), current.ProfilePhoto
+		return nil
+	}
+	user.Photo, user.ProfilePhoto = withPhotoURLs(stored), ""
+	return nil
+}
+
+// withPhotoURLs mengisi URL endpoint avatar untuk setiap ukuran
+func withPhotoURLs(photo []model.PhotoVariant) []model.PhotoVariant {
+	out := make([]model.PhotoVariant, len(photo))
+	for i, v := range photo {
+		v.URL = "/pdfm/profile/photo/" + strconv.Itoa(v.Size)
+		out[i] = v
+	}
+	return out
+}
+
+// photoDataURL mengembalikan avatar terbesar sebagai data URL untuk client lama yang masih membaca string base64
+func photoDataURL(ctx context.Context, photo []model.PhotoVariant) string {
+	if len(photo) == 0 {
+		return ""
+	}
+	largest := photo[0]
+	for _, v := range photo {
+		if v.Size > largest.Size {
+			largest = v
+		}
+	}
+	obj, data, err := config.FileStore.Get(ctx, largest.FileID)
+	if err != nil {
+		return ""
+	}
+	return "data:" + obj.ContentType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// photoErrorCode memetakan error pemrosesan foto ke status dan kode pesan
+func photoErrorCode(err error) (int, string) {
+	switch {
+	case errors.Is(err, errPhotoTooLarge):
+		return http.StatusRequestEntityTooLarge, i18n.PhotoTooLarge
+	case errors.Is(err, avatar.ErrEmpty):
+		return http.StatusBadRequest, i18n.PhotoRequired
+	case errors.Is(err, avatar.ErrInvalidData), errors.Is(err, avatar.ErrUnsupported), errors.Is(err, avatar.ErrTooLarge):
+		return http.StatusUnprocessableEntity, i18n.PhotoInvalid
+	default:
+		return http.StatusInternalServerError, i18n.SaveFailed
+	}
+}
+
+// GetProfilePhotoSize godoc
+// @Summary Ambil Avatar
+// @Description Mengunduh avatar JPEG user yang login dalam ukuran 64 atau 256 px. Mendukung If-None-Match (304) dengan ETag. Admin boleh mengambil avatar user lain lewat ?user_id=
+// @Tags User Profile
+// @Produce image/jpeg
+// @Param size path int true "Ukuran avatar (64 atau 256)"
+// @Param user_id query string false "ID user lain (khusus admin)"
+// @Success 200 {file} file
+// @Success 304 "Not Modified"
+// @Failure 401 {object} model.ResponseMessage
+// @Failure 404 {object} model.ResponseMessage
+// @Router /pdfm/profile/photo/{size} [get]
+// @Security BearerAuth
+func GetProfilePhotoSize(w http.ResponseWriter, r *http.Request) {
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	size, ok := avatar.ValidSize(at.GetParam(r))
+	if !ok {
+		writeMessage(w, lang, http.StatusNotFound, i18n.PhotoSizeInvalid)
+		return
+	}
+
+	if other := r.URL.Query().Get("user_id"); other != "" && other != user.ID.Hex() {
+		if !user.IsAdmin {
+			writeMessage(w, lang, http.StatusForbidden, i18n.ForbiddenAdmin)
+			return
+		}
+		id, err := primitive.ObjectIDFromHex(other)
+		if err != nil {
+			writeMessage(w, lang, http.StatusBadRequest, i18n.UserInvalidID)
+			return
+		}
+		var target model.PdfmUsers
+		if err := GetMongoCollection("users").FindOne(r.Context(), bson.M{"_id": id}).Decode(&target); err != nil {
+			writeMessage(w, lang, http.StatusNotFound, i18n.UserNotFound)
+			return
+		}
+		user = target
+	}
+
+	if err := migrateLegacyPhoto(r.Context(), &user); err != nil {
+		slog.ErrorContext(r.Context(), "gagal migrasi foto profil lama", "user_id", user.ID.Hex(), "error", err)
+	}
+	var variant *model.PhotoVariant
+	for i := range user.Photo {
+		if user.Photo[i].Size == size {
+			variant = &user.Photo[i]
+		}
+	}
+	if variant == nil {
+		writeMessage(w, lang, http.StatusNotFound, i18n.PhotoNotFound)
+		return
+	}
+
+	// URL avatar tetap sama setelah foto diganti, jadi browser harus selalu revalidasi dengan ETag
+	w.Header().Set("ETag", variant.ETag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if match := r.Header.Get("If-None-Match"); match != "" && etagMatches(match, variant.ETag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	obj, data, err := config.FileStore.Get(r.Context(), variant.FileID)
+	if err != nil {
+		writeMessage(w, lang, http.StatusNotFound, i18n.PhotoNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", obj.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// etagMatches memeriksa header If-None-Match yang bisa berisi beberapa ETag, "*" atau weak ETag (W/)
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -18,7 +18,7 @@ import (
 
 func PostKatalogBuku(respw http.ResponseWriter, req *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(req)
@@ -113,7 +113,7 @@ func PostKatalogBuku(respw http.ResponseWriter, req *http.Request) {
 }
 
 func PostDataProject(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
@@ -166,7 +166,7 @@ func PostDataProject(respw http.ResponseWriter, req *http.Request) {
 }
 
 func GetDataProject(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
@@ -204,7 +204,7 @@ func GetDataProject(respw http.ResponseWriter, req *http.Request) {
 
 // untuk manager
 func GetEditorApprovedProject(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
@@ -243,7 +243,7 @@ func GetEditorApprovedProject(respw http.ResponseWriter, req *http.Request) {
 
 func PutMetaDataProject(respw http.ResponseWriter, req *http.Request) {
 	// Decode token from header
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error: Token Tidak Valid"
@@ -306,7 +306,7 @@ func PutMetaDataProject(respw http.ResponseWriter, req *http.Request) {
 
 func PutPublishProject(respw http.ResponseWriter, req *http.Request) {
 	// Decode token from header
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error: Token Tidak Valid"
@@ -375,7 +375,7 @@ func PutPublishProject(respw http.ResponseWriter, req *http.Request) {
 
 func PutDataProject(respw http.ResponseWriter, req *http.Request) {
 	// Decode token from header
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error: Token Tidak Valid"
@@ -444,7 +444,7 @@ func PutDataProject(respw http.ResponseWriter, req *http.Request) {
 
 func DeleteDataProject(respw http.ResponseWriter, req *http.Request) {
 	// Dekode token dari header permintaan
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
@@ -503,7 +503,7 @@ func DeleteDataProject(respw http.ResponseWriter, req *http.Request) {
 }
 
 func GetDataMemberProject(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
@@ -540,7 +540,7 @@ func GetDataMemberProject(respw http.ResponseWriter, req *http.Request) {
 }
 
 func GetDataEditorProject(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
@@ -578,7 +578,7 @@ func GetDataEditorProject(respw http.ResponseWriter, req *http.Request) {
 
 func PostDataMemberProject(respw http.ResponseWriter, req *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(req)
@@ -635,7 +635,7 @@ func PostDataMemberProject(respw http.ResponseWriter, req *http.Request) {
 
 func PostDataEditorProject(respw http.ResponseWriter, req *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(req)
@@ -691,7 +691,7 @@ func PostDataEditorProject(respw http.ResponseWriter, req *http.Request) {
 
 func PUtApprovedEditorProject(respw http.ResponseWriter, req *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(req)
@@ -738,7 +738,7 @@ func PUtApprovedEditorProject(respw http.ResponseWriter, req *http.Request) {
 
 func PostDataMenuProject(respw http.ResponseWriter, req *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(req)
@@ -789,7 +789,7 @@ func PostDataMenuProject(respw http.ResponseWriter, req *http.Request) {
 
 func DeleteDataMenuProject(respw http.ResponseWriter, req *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(req)
@@ -849,7 +849,7 @@ func DeleteDataMenuProject(respw http.ResponseWriter, req *http.Request) {
 
 func DeleteDataMemberProject(respw http.ResponseWriter, req *http.Request) {
 	var respn model.Response
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(req)
@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"context"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/retention"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var tokenTTLOnce indexOnce
+
+// ensureTokenTTLIndex memasang TTL index pada tokens.expiresAt sehingga Mongo menghapus token
+// kedaluwarsa sendiri (monitor TTL berjalan tiap ±60 detik)
+func ensureTokenTTLIndex() {
+	tokenTTLOnce.Do(func() error {
+		_, err := config.Mongoconn.Collection("tokens").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		})
+		if err != nil {
+			slog.Error("gagal membuat TTL index", "collection", "tokens", "error", err)
+		}
+		return err
+	})
+}
+
+// runRetention menjalankan semua RetentionPolicies, token kedaluwarsa dan file storage kedaluwarsa.
+// Jika dryRun bernilai true, hanya menghitung dokumen yang akan dihapus.
+func runRetention(ctx context.Context, now time.Time, dryRun bool) (model.RetentionReport, error) {
+	ensureTokenTTLIndex()
+
+	report := model.RetentionReport{DryRun: dryRun, GeneratedAt: now, Items: []model.RetentionItem{}}
+
+	supporters, err := atdb.GetAllDistinct[primitive.ObjectID](config.Mongoconn, bson.M{"isSupport": true}, "_id", "users")
+	if err != nil {
+		return report, err
+	}
+	report.Supporters = len(supporters)
+
+	for _, p := range config.RetentionPolicies {
+		for _, plan := range p.Plans() {
+			cutoff := p.Cutoff(now, plan)
+			if cutoff.IsZero() {
+				continue
+			}
+			item := model.RetentionItem{Collection: p.Collection, Cutoff: cutoff}
+			filter := bson.M{p.TimeField: bson.M{"$lt": cutoff}}
+			if p.PerPlan() {
+				item.Plan = plan
+				if plan == retention.PlanSupporter {
+					if len(supporters) == 0 {
+						report.Items = append(report.Items, item)
+						continue
+					}
+					filter[p.UserField] = bson.M{"$in": supporters}
+				} else {
+					filter[p.UserField] = bson.M{"$nin": supporters}
+				}
+			}
+			report.Items = append(report.Items, purgeCollection(p.Collection, filter, item, dryRun))
+		}
+	}
+
+	// Token kedaluwarsa: TTL index sudah menangani, ini untuk sisa yang belum tersapu monitor
+	report.Items = append(report.Items, purgeCollection("tokens",
+		bson.M{"expiresAt": bson.M{"$lt": now}},
+		model.RetentionItem{Collection: "tokens", Cutoff: now}, dryRun))
+
+	report.Items = append(report.Items, purgeExpiredFiles(ctx, now, dryRun))
+	return report, nil
+}
+
+func purgeCollection(collection string, filter bson.M, item model.RetentionItem, dryRun bool) model.RetentionItem {
+	count, err := atdb.GetCountDoc(config.Mongoconn, collection, filter)
+	if err != nil {
+		item.Error = err.Error()
+		return item
+	}
+	item.Matched = count
+	if dryRun || count == 0 {
+		return item
+	}
+	res, err := atdb.DeleteManyDocs(config.Mongoconn, collection, filter)
+	if err != nil {
+		item.Error = err.Error()
+		return item
+	}
+	item.Deleted = res.DeletedCount
+	return item
+}
+
+func purgeExpiredFiles(ctx context.Context, now time.Time, dryRun bool) model.RetentionItem {
+	item := model.RetentionItem{Collection: "pdfm_files", Cutoff: now}
+	expired, err := config.FileStore.ListExpired(ctx, now)
+	if err != nil {
+		item.Error = err.Error()
+		return item
+	}
+	item.Matched = int64(len(expired))
+	if dryRun {
+		return item
+	}
+	for _, obj := range expired {
+		if err := config.FileStore.Delete(ctx, obj.ID); err == nil {
+			item.Deleted++
+		}
+	}
+	return item
+}
+
+// GetRetentionReport godoc
+// @Summary Laporan Retensi Data (Admin Only)
+// @Description Dry run retention purger: jumlah dokumen per koleksi dan plan yang akan dihapus, tanpa menghapus apa pun
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} model.RetentionReportResponse
+// @Failure 401 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Router /pdfm/admin/retention [get]
+// @Security BearerAuth
+func GetRetentionReport(w http.ResponseWriter, r *http.Request) {
+	_, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := runRetention(r.Context(), time.Now(), true)
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.RetentionFailed, err)
+		return
+	}
+
+	w.Header().Set("Content-Language", lang)
+	at.WriteJSON(w, http.StatusOK, model.RetentionReportResponse{
+		Status:  http.StatusOK,
+		Message: i18n.T(lang, i18n.RetentionDryRun),
+		Report:  report,
+	})
+}
+
+// PurgeRetention godoc
+// @Summary Jalankan Retention Purger
+// @Description Menghapus riwayat, notifikasi, login log, token dan file yang melewati masa retensi. Dipanggil admin atau scheduler dengan header X-Cron-Secret. Tambahkan ?dry_run=true untuk simulasi
+// @Tags Admin
+// @Produce json
+// @Param dry_run query bool false "Simulasi tanpa menghapus"
+// @Param X-Cron-Secret header string false "Secret scheduler (PDFM_CRON_SECRET)"
+// @Success 200 {object} model.RetentionReportResponse
+// @Failure 401 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Router /pdfm/admin/retention/purge [post]
+// @Security BearerAuth
+func PurgeRetention(w http.ResponseWriter, r *http.Request) {
+	lang := requestLocale(r, nil)
+	var actor *model.PdfmUsers
+	if !isCronRequest(r) {
+		admin, adminLang, ok := adminFromRequest(w, r)
+		if !ok {
+			return
+		}
+		actor, lang = &admin, adminLang
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	report, err := runRetention(r.Context(), time.Now(), dryRun)
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.RetentionFailed, err)
+		return
+	}
+
+	code := i18n.RetentionPurged
+	if dryRun {
+		code = i18n.RetentionDryRun
+	} else {
+		recordAudit(r, actor, auditRetentionPurge, "collection", "", map[string]any{"items": report.Items})
+	}
+	w.Header().Set("Content-Language", lang)
+	at.WriteJSON(w, http.StatusOK, model.RetentionReportResponse{
+		Status:  http.StatusOK,
+		Message: i18n.T(lang, code),
+		Report:  report,
+	})
+}
+
+// isCronRequest bernilai true jika request membawa X-Cron-Secret yang cocok dengan PDFM_CRON_SECRET
+func isCronRequest(r *http.Request) bool {
+	secret := r.Header.Get("X-Cron-Secret")
+	return config.CronSecret != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(config.CronSecret)) == 1
+}
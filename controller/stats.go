@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/activity"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const statsTimezone = "Asia/Jakarta"
+
+// statsFacet adalah hasil mentah $facet statistik pada activity_log
+type statsFacet struct {
+	PerType []struct {
+		Type  string `bson:"_id"`
+		Count int64  `bson:"count"`
+	} `bson:"per_type"`
+	Compression []struct {
+		Files      int64 `bson:"files"`
+		Original   int64 `bson:"original"`
+		Compressed int64 `bson:"compressed"`
+		Saved      int64 `bson:"saved"`
+	} `bson:"compression"`
+	Conversions []struct {
+		Pair struct {
+			Source string `bson:"source"`
+			Target string `bson:"target"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	} `bson:"conversions"`
+	Heatmap []struct {
+		Slot struct {
+			Day  int `bson:"d"`
+			Hour int `bson:"h"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	} `bson:"heatmap"`
+	Days []struct {
+		Day string `bson:"_id"`
+	} `bson:"days"`
+	Users []struct {
+		N int64 `bson:"n"`
+	} `bson:"users"`
+}
+
+// computeUsageStats menghitung statistik activity_log untuk filter match dalam satu agregasi
+func computeUsageStats(ctx context.Context, match bson.M, scope string) (model.UsageStats, error) {
+	localTime := func(op string) bson.M {
+		return bson.M{op: bson.M{"date": "$created_at", "timezone": statsTimezone}}
+	}
+	facets := bson.M{
+		"per_type": bson.A{
+			bson.M{"$group": bson.M{"_id": "$type", "count": bson.M{"$sum": 1}}},
+		},
+		"compression": bson.A{
+			bson.M{"$match": bson.M{"type": "compress"}},
+			bson.M{"$group": bson.M{
+				"_id":        nil,
+				"files":      bson.M{"$sum": 1},
+				"original":   bson.M{"$sum": "$details.original_size"},
+				"compressed": bson.M{"$sum": "$details.compressed_size"},
+				"saved": bson.M{"$sum": bson.M{"$max": bson.A{0, bson.M{
+					"$subtract": bson.A{"$details.original_size", "$details.compressed_size"},
+				}}}},
+			}},
+		},
+		"conversions": bson.A{
+			bson.M{"$match": bson.M{"type": "convert"}},
+			bson.M{"$group": bson.M{
+				"_id":   bson.M{"source": "$details.source_format", "target": "$details.target_format"},
+				"count": bson.M{"$sum": 1},
+			}},
+			bson.M{"$sort": bson.M{"count": -1}},
+			bson.M{"$limit": 5},
+		},
+		"heatmap": bson.A{
+			bson.M{"$group": bson.M{
+				"_id":   bson.M{"d": localTime("$dayOfWeek"), "h": localTime("$hour")},
+				"count": bson.M{"$sum": 1},
+			}},
+		},
+		"days": bson.A{
+			bson.M{"$group": bson.M{"_id": bson.M{"$dateToString": bson.M{
+				"format": "%Y-%m-%d", "date": "$created_at", "timezone": statsTimezone,
+			}}}},
+		},
+	}
+	if scope == "system" {
+		facets["users"] = bson.A{
+			bson.M{"$group": bson.M{"_id": "$user_id"}},
+			bson.M{"$count": "n"},
+		}
+	}
+
+	pipeline := bson.A{bson.M{"$match": match}, bson.M{"$facet": facets}}
+	ensureActivityIndexes()
+	rows, err := atdb.AggregateDoc[statsFacet](config.Mongoconn, activityCollection, pipeline)
+	if err != nil {
+		return model.UsageStats{}, err
+	}
+
+	stats := model.UsageStats{
+		Scope:          scope,
+		PerType:        map[string]int64{},
+		TopConversions: []model.ConversionPair{},
+		Timezone:       statsTimezone,
+	}
+	for _, typ := range activity.Types() {
+		stats.PerType[typ] = 0
+	}
+	if len(rows) == 0 {
+		return stats, nil
+	}
+	f := rows[0]
+
+	for _, t := range f.PerType {
+		stats.PerType[t.Type] = t.Count
+		stats.TotalOperations += t.Count
+	}
+	if len(f.Compression) > 0 {
+		c := f.Compression[0]
+		stats.Compression = model.CompressionStats{
+			Files:           c.Files,
+			OriginalBytes:   c.Original,
+			CompressedBytes: c.Compressed,
+			BytesSaved:      c.Saved,
+		}
+	}
+	for _, c := range f.Conversions {
+		stats.TopConversions = append(stats.TopConversions, model.ConversionPair{
+			SourceFormat: c.Pair.Source,
+			TargetFormat: c.Pair.Target,
+			Count:        c.Count,
+		})
+	}
+	for _, h := range f.Heatmap {
+		// $dayOfWeek: 1 = Minggu ... 7 = Sabtu
+		if h.Slot.Day >= 1 && h.Slot.Day <= 7 && h.Slot.Hour >= 0 && h.Slot.Hour < 24 {
+			stats.Heatmap[h.Slot.Day-1][h.Slot.Hour] = h.Count
+		}
+	}
+	var days []string
+	for _, d := range f.Days {
+		days = append(days, d.Day)
+	}
+	loc, _ := time.LoadLocation(statsTimezone)
+	stats.ActiveDays = len(days)
+	stats.CurrentStreak, stats.LongestStreak = activity.Streaks(days, time.Now().In(loc))
+	if len(f.Users) > 0 {
+		stats.ActiveUsers = f.Users[0].N
+	}
+	return stats, nil
+}
+
+// GetMyStats godoc
+// @Summary Statistik Pemakaian Saya
+// @Description Total operasi per tipe, byte yang dihemat kompresi, pasangan konversi terbanyak, heatmap hari/jam (Asia/Jakarta) dan streak
+// @Tags Stats
+// @Produce json
+// @Success 200 {object} model.UsageStatsResponse
+// @Failure 401 {object} model.ResponseMessage
+// @Router /pdfm/stats [get]
+// @Security BearerAuth
+func GetMyStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	user, lang, ok := sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := computeUsageStats(r.Context(), bson.M{"user_id": user.ID}, "user")
+	if err != nil {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.StatsFailed)
+		return
+	}
+
+	json.NewEncoder(w).Encode(model.UsageStatsResponse{
+		Status:  http.StatusOK,
+		Message: "Stats retrieved successfully",
+		Stats:   stats,
+	})
+}
+
+// GetSystemStats godoc
+// @Summary Statistik Pemakaian Seluruh Sistem (Admin Only)
+// @Description Statistik yang sama dengan /pdfm/stats untuk semua user, ditambah jumlah user aktif
+// @Tags Stats
+// @Produce json
+// @Success 200 {object} model.UsageStatsResponse
+// @Failure 401 {object} model.ResponseMessage
+// @Failure 403 {object} model.ResponseMessage
+// @Router /pdfm/admin/stats [get]
+// @Security BearerAuth
+func GetSystemStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	_, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := computeUsageStats(r.Context(), bson.M{}, "system")
+	if err != nil {
+		writeMessage(w, lang, http.StatusInternalServerError, i18n.StatsFailed)
+		return
+	}
+
+	json.NewEncoder(w).Encode(model.UsageStatsResponse{
+		Status:  http.StatusOK,
+		Message: "Stats retrieved successfully",
+		Stats:   stats,
+	})
+}
@@ -26,7 +26,7 @@ import (
 )
 
 func GetDataUserFromApi(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid "
@@ -45,7 +45,7 @@ func GetDataUserFromApi(respw http.ResponseWriter, req *http.Request) {
 }
 
 func GetDataUser(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid "
@@ -68,7 +68,7 @@ func GetDataUser(respw http.ResponseWriter, req *http.Request) {
 
 // melakukan pengecekan apakah suda link device klo ada generate token 5tahun
 func PutTokenDataUser(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid "
@@ -109,7 +109,7 @@ func PutTokenDataUser(respw http.ResponseWriter, req *http.Request) {
 }
 
 func PostDataUser(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
@@ -209,7 +209,7 @@ func PostDataUser(respw http.ResponseWriter, req *http.Request) {
 }
 
 func PostDataBioUser(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
@@ -17,7 +17,6 @@ import (
 	"github.com/gocroot/helper/phone"
 	"github.com/gocroot/helper/report"
 	"github.com/gocroot/helper/tiket"
-	"github.com/gocroot/helper/watoken"
 	"github.com/gocroot/helper/whatsauth"
 	"github.com/gocroot/mod/helpdesk"
 	"github.com/gocroot/model"
@@ -119,7 +118,7 @@ func PostPresensi(respw http.ResponseWriter, req *http.Request) {
 		at.WriteJSON(respw, http.StatusForbidden, resp)
 		return
 	}
-	res, err := report.TambahPoinPresensibyPhoneNumber(config.Mongoconn, presensi.PhoneNumber, presensi.Lokasi, presensi.Skor, config.WAAPIToken, config.WAAPIMessage, "presensi")
+	res, err := report.TambahPoinPresensibyPhoneNumber(config.Mongoconn, presensi.PhoneNumber, presensi.Lokasi, presensi.Skor, config.WAAPIToken(), config.WAAPIMessage, "presensi")
 	if err != nil {
 		resp.Info = "Tambah Poin Presensi gagal"
 		resp.Response = err.Error()
@@ -133,7 +132,7 @@ func PostPresensi(respw http.ResponseWriter, req *http.Request) {
 
 // testimoni dari useng lms pamong
 func PostTestimoni(respw http.ResponseWriter, req *http.Request) {
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid "
@@ -395,7 +394,7 @@ func PostMasukanTiket(respw http.ResponseWriter, req *http.Request) {
 		IsGroup:  false,
 		Messages: message,
 	}
-	go atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken, dt, config.WAAPIMessage)
+	go atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken(), dt, config.WAAPIMessage)
 
 	at.WriteJSON(respw, http.StatusOK, respn)
 }
@@ -403,7 +402,7 @@ func PostMasukanTiket(respw http.ResponseWriter, req *http.Request) {
 func PostMeeting(w http.ResponseWriter, r *http.Request) {
 	var respn model.Response
 	//otorisasi dan validasi inputan
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
+	payload, err := decodeLoginToken(r)
 	if err != nil {
 		respn.Status = "Error : Token Tidak Valid"
 		respn.Info = at.GetSecretFromHeader(r)
@@ -497,7 +496,7 @@ func PostMeeting(w http.ResponseWriter, r *http.Request) {
 		IsGroup:  true,
 		Messages: message,
 	}
-	_, resp, err := atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken, dt, config.WAAPIMessage)
+	_, resp, err := atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken(), dt, config.WAAPIMessage)
 	if err != nil {
 		resp.Info = "Tidak berhak"
 		resp.Response = err.Error()
@@ -509,7 +508,7 @@ func PostMeeting(w http.ResponseWriter, r *http.Request) {
 
 func PostLaporan(respw http.ResponseWriter, req *http.Request) {
 	//otorisasi dan validasi inputan
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
@@ -593,7 +592,7 @@ func PostLaporan(respw http.ResponseWriter, req *http.Request) {
 		IsGroup:  false,
 		Messages: message,
 	}
-	_, resp, err := atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken, dt, config.WAAPIMessage)
+	_, resp, err := atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken(), dt, config.WAAPIMessage)
 	if err != nil {
 		resp.Info = "Tidak berhak"
 		resp.Response = err.Error()
@@ -606,7 +605,7 @@ func PostLaporan(respw http.ResponseWriter, req *http.Request) {
 
 func PostFeedback(respw http.ResponseWriter, req *http.Request) {
 	//otorisasi dan validasi inputan
-	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
+	payload, err := decodeLoginToken(req)
 	if err != nil {
 		var respn model.Response
 		respn.Status = "Error : Token Tidak Valid"
@@ -689,7 +688,7 @@ func PostFeedback(respw http.ResponseWriter, req *http.Request) {
 		IsGroup:  false,
 		Messages: message,
 	}
-	_, resp, err := atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken, dt, config.WAAPIMessage)
+	_, resp, err := atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken(), dt, config.WAAPIMessage)
 	if err != nil {
 		resp.Info = "Tidak berhak"
 		resp.Response = err.Error()
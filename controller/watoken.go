@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/watoken"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const revokedTokenCollection = "revoked_tokens"
+
+// watokenMaxLifetime adalah umur token PASETO terpanjang yang diterbitkan (token device 43830 jam),
+// dipakai sebagai masa simpan pencabutan jika waktu kedaluwarsa token tidak diketahui
+const watokenMaxLifetime = 43830 * time.Hour
+
+var (
+	revokedTokenIndexOnce indexOnce
+	// revokedTokens menyimpan hasil positif dari database agar token yang dicabut tidak dicari berulang
+	revokedTokens = watoken.NewRevocationList()
+)
+
+// watokenVerifyOptions dipakai semua verifikasi token di header Login. Token lama dari server
+// WhatsAuth tidak memiliki aud sehingga tetap diterima.
+var watokenVerifyOptions = watoken.VerifyOptions{
+	Audience:        config.WatokenAudience,
+	AllowNoAudience: true,
+	Revoked:         watokenRevoked,
+}
+
+func ensureRevokedTokenIndexes() {
+	revokedTokenIndexOnce.Do(func() error {
+		_, err := GetMongoCollection(revokedTokenCollection).Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+			{Keys: bson.D{{Key: "jti", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		})
+		if err != nil {
+			slog.Error("gagal membuat index", "collection", "revoked_tokens", "error", err)
+		}
+		return err
+	})
+}
+
+// watokenKeyring adalah keyring aplikasi ditambah kunci publik WhatsAuth dari profil untuk token
+// yang diterbitkan server WhatsAuth
+func watokenKeyring() *watoken.Keyring {
+	return config.WatokenKeyring.WithVerifyKey("whatsauth", config.PublicKeyWhatsAuth())
+}
+
+// decodeLoginToken memverifikasi token PASETO di header Login, pengganti watoken.Decode dengan
+// satu kunci publik
+func decodeLoginToken(r *http.Request) (watoken.Payload[any], error) {
+	return watokenKeyring().Verify(at.GetLoginFromHeader(r), watokenVerifyOptions)
+}
+
+// signLoginToken menerbitkan token sesi PASETO dengan key aktif keyring. Token device yang
+// diverifikasi API WhatsApp tetap ditandatangani dengan PRKEY.
+func signLoginToken(id, alias string, duration time.Duration) (string, error) {
+	token, _, err := config.WatokenKeyring.Sign(watoken.Claims{
+		ID:       id,
+		Alias:    alias,
+		Audience: config.WatokenAudience,
+		Duration: duration,
+	}, time.Now())
+	return token, err
+}
+
+// watokenRevoked memeriksa daftar pencabutan. Jika database tidak bisa diakses token dianggap belum
+// dicabut agar login tidak ikut terputus.
+func watokenRevoked(jti string) bool {
+	if revokedTokens.Revoked(jti) {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var doc model.RevokedToken
+	err := GetMongoCollection(revokedTokenCollection).FindOne(ctx, bson.M{"jti": jti}).Decode(&doc)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			slog.Error("gagal memeriksa pencabutan token", "jti", jti, "error", err)
+		}
+		return false
+	}
+	cacheRevokedToken(jti, doc.ExpiresAt)
+	return true
+}
+
+// cacheRevokedToken menyimpan pencabutan di cache memori. Entri yang token-nya sudah kedaluwarsa
+// dibuang setiap kali entri baru masuk sehingga cache tidak tumbuh terus di instance yang hidup lama.
+func cacheRevokedToken(jti string, until time.Time) {
+	revokedTokens.Prune(time.Now())
+	revokedTokens.Revoke(jti, until)
+}
+
+// RevokeWatoken godoc
+// @Summary Cabut Token PASETO (Admin)
+// @Description Mencabut token PASETO (header Login) berdasarkan token atau jti-nya. Token yang diterbitkan sebelum keyring dipakai tidak memiliki jti sehingga tidak bisa dicabut satu per satu; rotasi key untuk mencabut semuanya
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body model.RevokeTokenInput true "Token atau jti"
+// @Success 200 {object} model.ResponseMessage
+// @Failure 400 {object} model.ResponseMessage
+// @Failure 422 {object} model.ResponseMessage
+// @Router /pdfm/admin/tokens/revoke [post]
+// @Security BearerAuth
+func RevokeWatoken(w http.ResponseWriter, r *http.Request) {
+	admin, lang, ok := adminFromRequest(w, r)
+	if !ok {
+		return
+	}
+	var req model.RevokeTokenInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, lang, http.StatusBadRequest, i18n.InvalidBody, err)
+		return
+	}
+
+	doc := model.RevokedToken{
+		Jti:       strings.TrimSpace(req.Jti),
+		ExpiresAt: req.ExpiresAt,
+		Reason:    req.Reason,
+		RevokedAt: time.Now(),
+		RevokedBy: admin.ID,
+	}
+	if req.Token != "" {
+		// Token kedaluwarsa atau sudah dicabut tetap boleh dicatat, jadi hanya tanda tangan yang diperiksa
+		opt := watokenVerifyOptions
+		opt.Revoked, opt.IgnoreExpiry = nil, true
+		payload, err := watokenKeyring().Verify(strings.TrimSpace(req.Token), opt)
+		if err != nil {
+			writeError(w, lang, http.StatusUnprocessableEntity, i18n.TokenInvalid, err)
+			return
+		}
+		doc.Jti, doc.Subject, doc.ExpiresAt = payload.Jti, payload.Id, payload.Exp
+	}
+	if doc.Jti == "" {
+		writeMessage(w, lang, http.StatusUnprocessableEntity, i18n.TokenNoJti)
+		return
+	}
+	if doc.ExpiresAt.IsZero() {
+		doc.ExpiresAt = doc.RevokedAt.Add(watokenMaxLifetime)
+	}
+
+	ensureRevokedTokenIndexes()
+	_, err := GetMongoCollection(revokedTokenCollection).UpdateOne(r.Context(),
+		bson.M{"jti": doc.Jti}, bson.M{"$setOnInsert": doc}, options.Update().SetUpsert(true))
+	if err != nil {
+		writeError(w, lang, http.StatusInternalServerError, i18n.SaveFailed, err)
+		return
+	}
+	cacheRevokedToken(doc.Jti, doc.ExpiresAt)
+	recordAudit(r, &admin, auditTokenRevoke, "token", doc.Jti, map[string]any{"subject": doc.Subject, "reason": doc.Reason})
+	writeMessage(w, lang, http.StatusOK, i18n.TokenRevoked)
+}
@@ -0,0 +1,148 @@
+package activity
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gocroot/helper/metrics"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Tool mendeskripsikan satu tool PDF yang mencatat aktivitas ke activity_log.
+// Buat dengan NewTool agar fungsi-fungsinya bekerja pada struct detail yang bertipe.
+type Tool struct {
+	// Type adalah diskriminator yang disimpan di field "type"
+	Type string
+	// LegacyCollection adalah koleksi riwayat lama yang dimigrasi ke activity_log (boleh kosong)
+	LegacyCollection string
+	// NewDetails mengembalikan pointer ke struct detail kosong (skema payload tipe ini)
+	NewDetails func() interface{}
+	// FileName mengambil nama file utama dari detail
+	FileName func(d interface{}) string
+	// Validate memeriksa field wajib sebelum disimpan
+	Validate func(d interface{}) error
+	// Describe membuat deskripsi singkat untuk ditampilkan di riwayat
+	Describe func(d interface{}) string
+	// Run menjalankan ulang operasi dari file input tersimpan (nil jika tool tidak bisa di-rerun).
+	// Run boleh memperbarui d, mis. ukuran hasil kompresi yang baru.
+	Run func(inputs [][]byte, d interface{}) ([]byte, error)
+}
+
+// NewTool membuat Tool dengan struct detail T
+func NewTool[T any](typ, legacyCollection string, fileName func(*T) string, validate func(*T) error, describe func(*T) string) Tool {
+	return Tool{
+		Type:             typ,
+		LegacyCollection: legacyCollection,
+		NewDetails:       func() interface{} { return new(T) },
+		FileName:         func(d interface{}) string { return fileName(d.(*T)) },
+		Validate:         func(d interface{}) error { return validate(d.(*T)) },
+		Describe:         func(d interface{}) string { return describe(d.(*T)) },
+	}
+}
+
+// WithRunner menambahkan fungsi rerun bertipe ke tool yang dibuat dengan NewTool. Setiap
+// pemanggilan Run dicatat ke metrics (jumlah, durasi, byte input dan hasil per tool).
+func WithRunner[T any](t Tool, run func(inputs [][]byte, d *T) ([]byte, error)) Tool {
+	typ := t.Type
+	t.Run = func(inputs [][]byte, d interface{}) ([]byte, error) {
+		start := time.Now()
+		out, err := run(inputs, d.(*T))
+		metrics.PDFDuration.WithLabelValues(typ).Observe(time.Since(start).Seconds())
+		for _, in := range inputs {
+			metrics.PDFBytesIn.WithLabelValues(typ).Add(float64(len(in)))
+		}
+		if err != nil {
+			metrics.PDFOperations.WithLabelValues(typ, metrics.ResultFailure).Inc()
+			return nil, err
+		}
+		metrics.PDFOperations.WithLabelValues(typ, metrics.ResultSuccess).Inc()
+		metrics.PDFBytesOut.WithLabelValues(typ).Add(float64(len(out)))
+		return out, nil
+	}
+	return t
+}
+
+var (
+	ErrUnknownType = errors.New("tipe aktivitas tidak dikenal")
+
+	mu    sync.RWMutex
+	tools = map[string]Tool{}
+)
+
+// Register mendaftarkan tool baru. Dipanggil dari init() sehingga panic jika tipe dobel.
+func Register(t Tool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if t.Type == "" || t.NewDetails == nil || t.FileName == nil || t.Validate == nil || t.Describe == nil {
+		panic("activity: tool harus dibuat dengan NewTool")
+	}
+	if _, ok := tools[t.Type]; ok {
+		panic("activity: tipe " + t.Type + " sudah terdaftar")
+	}
+	tools[t.Type] = t
+}
+
+// Lookup mencari tool berdasarkan tipe
+func Lookup(typ string) (Tool, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	t, ok := tools[typ]
+	if !ok {
+		return Tool{}, ErrUnknownType
+	}
+	return t, nil
+}
+
+// Tools mengembalikan semua tool terdaftar, diurutkan berdasarkan tipe
+func Tools() []Tool {
+	mu.RLock()
+	defer mu.RUnlock()
+	list := make([]Tool, 0, len(tools))
+	for _, t := range tools {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Type < list[j].Type })
+	return list
+}
+
+// Types mengembalikan semua nama tipe terdaftar
+func Types() []string {
+	var types []string
+	for _, t := range Tools() {
+		types = append(types, t.Type)
+	}
+	return types
+}
+
+// Encode mengubah detail menjadi dokumen bson untuk disimpan di field "details"
+func Encode(d interface{}) (bson.M, error) {
+	raw, err := bson.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	err = bson.Unmarshal(raw, &doc)
+	return doc, err
+}
+
+// Decode membaca dokumen detail (atau dokumen koleksi lama) ke struct detail milik tool
+func (t Tool) Decode(doc bson.M) (interface{}, error) {
+	d := t.NewDetails()
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	err = bson.Unmarshal(raw, d)
+	return d, err
+}
+
+// Description membuat deskripsi dari dokumen detail; jika gagal decode dipakai nama tipe saja
+func (t Tool) Description(doc bson.M) string {
+	d, err := t.Decode(doc)
+	if err != nil {
+		return t.Type
+	}
+	return t.Describe(d)
+}
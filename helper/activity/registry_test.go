@@ -0,0 +1,92 @@
+package activity
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gocroot/helper/metrics"
+	"github.com/gocroot/model"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestBuiltinTools(t *testing.T) {
+	want := []string{"compress", "convert", "merge", "summary"}
+	got := Types()
+	if len(got) != len(want) {
+		t.Fatalf("Types() = %v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Types()[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+	if _, err := Lookup("rotate"); err != ErrUnknownType {
+		t.Errorf("Lookup tipe asing err = %v", err)
+	}
+}
+
+func TestMergeRoundTrip(t *testing.T) {
+	tool, _ := Lookup("merge")
+	d := &model.MergeDetails{InputFiles: make([]string, 12), OutputFile: "hasil.pdf"}
+	if err := tool.Validate(d); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Encode(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Deskripsi harus benar untuk jumlah file di atas 9
+	if got := tool.Description(doc); got != "Merged 12 PDF files" {
+		t.Errorf("Description = %q", got)
+	}
+	if got := tool.FileName(d); got != "hasil.pdf" {
+		t.Errorf("FileName = %q", got)
+	}
+}
+
+func TestDecodeLegacyDocument(t *testing.T) {
+	tool, _ := Lookup("compress")
+	legacy := bson.M{"_id": "x", "user_id": "y", "file_name": "a.pdf", "original_size": int64(10), "compressed_size": int64(4), "status": "success"}
+	d, err := tool.Decode(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := d.(*model.CompressDetails)
+	if c.FileName != "a.pdf" || c.OriginalSize != 10 || c.CompressedSize != 4 {
+		t.Errorf("decode legacy salah: %+v", c)
+	}
+	if err := tool.Validate(&model.CompressDetails{}); err == nil {
+		t.Error("validasi seharusnya gagal tanpa file_name")
+	}
+}
+
+func TestRunnerMetrics(t *testing.T) {
+	tool := WithRunner(NewTool("metrics-test", "",
+		func(d *model.CompressDetails) string { return d.FileName },
+		func(d *model.CompressDetails) error { return nil },
+		func(d *model.CompressDetails) string { return "" },
+	), func(inputs [][]byte, d *model.CompressDetails) ([]byte, error) {
+		if len(inputs) == 0 {
+			return nil, errors.New("tanpa input")
+		}
+		return inputs[0][:2], nil
+	})
+	tool.Run([][]byte{[]byte("abcde")}, &model.CompressDetails{})
+	tool.Run(nil, &model.CompressDetails{})
+
+	if testutil.ToFloat64(metrics.PDFOperations.WithLabelValues("metrics-test", metrics.ResultSuccess)) != 1 ||
+		testutil.ToFloat64(metrics.PDFOperations.WithLabelValues("metrics-test", metrics.ResultFailure)) != 1 {
+		t.Error("jumlah operasi tidak tercatat per hasil")
+	}
+	if testutil.ToFloat64(metrics.PDFBytesIn.WithLabelValues("metrics-test")) != 5 || testutil.ToFloat64(metrics.PDFBytesOut.WithLabelValues("metrics-test")) != 2 {
+		t.Error("byte input/hasil tidak sesuai")
+	}
+	var m dto.Metric
+	metrics.PDFDuration.WithLabelValues("metrics-test").(prometheus.Metric).Write(&m)
+	if m.GetHistogram().GetSampleCount() != 2 {
+		t.Error("durasi tidak tercatat")
+	}
+}
@@ -0,0 +1,41 @@
+package activity
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/gocroot/model"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// runMerge menggabungkan ulang file input sesuai urutan penyimpanan
+func runMerge(inputs [][]byte, d *model.MergeDetails) ([]byte, error) {
+	if len(inputs) < 2 {
+		return nil, errors.New("merge membutuhkan minimal 2 file input")
+	}
+	readers := make([]io.ReadSeeker, len(inputs))
+	for i, in := range inputs {
+		readers[i] = bytes.NewReader(in)
+	}
+	var out bytes.Buffer
+	if err := api.MergeRaw(readers, &out, false, nil); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// runCompress mengoptimasi ulang file input dan memperbarui ukuran pada detail
+func runCompress(inputs [][]byte, d *model.CompressDetails) ([]byte, error) {
+	if len(inputs) != 1 {
+		return nil, errors.New("compress membutuhkan tepat 1 file input")
+	}
+	var out bytes.Buffer
+	if err := api.Optimize(bytes.NewReader(inputs[0]), &out, nil); err != nil {
+		return nil, err
+	}
+	d.OriginalSize = int64(len(inputs[0]))
+	d.CompressedSize = int64(out.Len())
+	d.Status = "success"
+	return out.Bytes(), nil
+}
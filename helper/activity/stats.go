@@ -0,0 +1,47 @@
+package activity
+
+import (
+	"sort"
+	"time"
+)
+
+// DayLayout adalah format hari yang dipakai hasil $dateToString pada agregasi statistik
+const DayLayout = "2006-01-02"
+
+// Streaks menghitung streak hari aktif berturut-turut dari daftar hari (format DayLayout).
+// current adalah streak yang berakhir hari ini atau kemarin (belum putus), longest adalah streak terpanjang.
+func Streaks(days []string, today time.Time) (current, longest int) {
+	var dates []time.Time
+	for _, d := range days {
+		t, err := time.Parse(DayLayout, d)
+		if err == nil {
+			dates = append(dates, t)
+		}
+	}
+	if len(dates) == 0 {
+		return 0, 0
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	run := 0
+	for i, d := range dates {
+		switch {
+		case i > 0 && d.Equal(dates[i-1]):
+			continue
+		case i > 0 && d.Sub(dates[i-1]) == 24*time.Hour:
+			run++
+		default:
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+
+	todayDate, _ := time.Parse(DayLayout, today.Format(DayLayout))
+	last := dates[len(dates)-1]
+	if gap := todayDate.Sub(last); gap == 0 || gap == 24*time.Hour {
+		current = run
+	}
+	return current, longest
+}
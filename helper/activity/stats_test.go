@@ -0,0 +1,28 @@
+package activity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStreaks(t *testing.T) {
+	today := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name             string
+		days             []string
+		current, longest int
+	}{
+		{"kosong", nil, 0, 0},
+		{"hari ini saja", []string{"2025-03-10"}, 1, 1},
+		{"berakhir kemarin", []string{"2025-03-07", "2025-03-08", "2025-03-09"}, 3, 3},
+		{"putus", []string{"2025-03-01", "2025-03-02", "2025-03-03", "2025-03-05"}, 0, 3},
+		{"tidak urut dan dobel", []string{"2025-03-10", "2025-03-09", "2025-03-09", "2025-03-01"}, 2, 2},
+		{"lintas bulan", []string{"2025-02-27", "2025-02-28", "2025-03-01"}, 0, 3},
+	}
+	for _, c := range cases {
+		cur, long := Streaks(c.days, today)
+		if cur != c.current || long != c.longest {
+			t.Errorf("%s: Streaks = (%d, %d), want (%d, %d)", c.name, cur, long, c.current, c.longest)
+		}
+	}
+}
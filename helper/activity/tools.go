@@ -0,0 +1,63 @@
+package activity
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gocroot/model"
+)
+
+// Tool bawaan pdfm. Tool baru cukup menambahkan Register di sini (atau di init paket lain)
+// tanpa perlu handler, route, maupun koleksi baru.
+func init() {
+	Register(WithRunner(NewTool("merge", "merge_history",
+		func(d *model.MergeDetails) string { return d.OutputFile },
+		func(d *model.MergeDetails) error {
+			if len(d.InputFiles) == 0 || d.OutputFile == "" {
+				return errors.New("input_files dan output_file wajib diisi")
+			}
+			return nil
+		},
+		func(d *model.MergeDetails) string {
+			return "Merged " + strconv.Itoa(len(d.InputFiles)) + " PDF files"
+		},
+	), runMerge))
+
+	Register(WithRunner(NewTool("compress", "compress_history",
+		func(d *model.CompressDetails) string { return d.FileName },
+		func(d *model.CompressDetails) error {
+			if d.FileName == "" {
+				return errors.New("file_name wajib diisi")
+			}
+			if d.OriginalSize < 0 || d.CompressedSize < 0 {
+				return errors.New("ukuran file tidak boleh negatif")
+			}
+			return nil
+		},
+		func(d *model.CompressDetails) string { return "Compressed PDF file" },
+	), runCompress))
+
+	Register(NewTool("convert", "convert_history",
+		func(d *model.ConvertDetails) string { return d.FileName },
+		func(d *model.ConvertDetails) error {
+			if d.FileName == "" || d.SourceFormat == "" || d.TargetFormat == "" {
+				return errors.New("file_name, source_format dan target_format wajib diisi")
+			}
+			return nil
+		},
+		func(d *model.ConvertDetails) string {
+			return "Converted " + d.SourceFormat + " to " + d.TargetFormat
+		},
+	))
+
+	Register(NewTool("summary", "summary_history",
+		func(d *model.SummaryDetails) string { return d.FileName },
+		func(d *model.SummaryDetails) error {
+			if d.FileName == "" {
+				return errors.New("file_name wajib diisi")
+			}
+			return nil
+		},
+		func(d *model.SummaryDetails) string { return "Generated PDF summary" },
+	))
+}
@@ -0,0 +1,147 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Prefix menandai API key pdfm sehingga mudah dikenali (mis. oleh secret scanner)
+const Prefix = "pdfm_"
+
+// ScopeAll memberi akses ke semua scope
+const ScopeAll = "*"
+
+// displayLength adalah panjang awal key yang disimpan apa adanya untuk ditampilkan di daftar key
+const displayLength = len(Prefix) + 6
+
+var (
+	ErrMalformed    = errors.New("format API key tidak valid")
+	ErrUnknownScope = errors.New("scope tidak dikenal")
+	ErrNoScope      = errors.New("minimal satu scope")
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Generate membuat API key baru. plain hanya ditampilkan sekali ke user; yang disimpan adalah
+// hash dan display (awalan key untuk membedakan key di daftar).
+func Generate() (plain, display, hash string, err error) {
+	raw := make([]byte, 25)
+	if _, err = rand.Read(raw); err != nil {
+		return "", "", "", err
+	}
+	plain = Prefix + strings.ToLower(encoding.EncodeToString(raw))
+	return plain, plain[:displayLength], Hash(plain), nil
+}
+
+// Hash adalah sha256 hex dari key. Key berentropi tinggi sehingga tidak perlu bcrypt dan
+// hash bisa dipakai langsung sebagai index pencarian.
+func Hash(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// Valid memeriksa format key sebelum mencarinya di database
+func Valid(plain string) bool {
+	if !strings.HasPrefix(plain, Prefix) || len(plain) != len(Prefix)+40 {
+		return false
+	}
+	_, err := encoding.DecodeString(strings.ToUpper(plain[len(Prefix):]))
+	return err == nil
+}
+
+// NormalizeScopes membuang duplikat dan spasi, memvalidasi setiap scope dengan known, lalu mengurutkan.
+// ScopeAll menggantikan semua scope lain.
+func NormalizeScopes(scopes []string, known func(string) bool) ([]string, error) {
+	seen := map[string]bool{}
+	var out []string
+	for _, s := range scopes {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s == "" || seen[s] {
+			continue
+		}
+		if s == ScopeAll {
+			return []string{ScopeAll}, nil
+		}
+		if !known(s) {
+			return nil, errors.New(ErrUnknownScope.Error() + ": " + s)
+		}
+		seen[s] = true
+		out = append(out, s)
+	}
+	if len(out) == 0 {
+		return nil, ErrNoScope
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// Allows bernilai true jika scopes mencakup scope
+func Allows(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == ScopeAll || s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Limiters menyimpan rate limiter per key dengan batas per menit yang bisa berbeda tiap key
+type Limiters struct {
+	mu sync.Mutex
+	m  map[string]*limiter
+}
+
+type limiter struct {
+	perMinute int
+	l         *rate.Limiter
+}
+
+func NewLimiters() *Limiters {
+	return &Limiters{m: map[string]*limiter{}}
+}
+
+// Allow mengambil satu token dari limiter key id. Jika perMinute berubah, limiter dibuat ulang.
+// retryAfter adalah perkiraan waktu tunggu jika request ditolak.
+func (ls *Limiters) Allow(id string, perMinute int, now time.Time) (ok bool, retryAfter time.Duration) {
+	if perMinute <= 0 {
+		return true, 0
+	}
+	ls.mu.Lock()
+	entry, exists := ls.m[id]
+	if !exists || entry.perMinute != perMinute {
+		entry = &limiter{perMinute: perMinute, l: rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute)}
+		ls.m[id] = entry
+	}
+	ls.mu.Unlock()
+
+	r := entry.l.ReserveN(now, 1)
+	delay := r.DelayFrom(now)
+	if delay == 0 {
+		return true, 0
+	}
+	r.CancelAt(now)
+	return false, delay
+}
+
+// Window adalah jendela satu menit tempat request sebuah key dihitung bersama oleh semua instance
+func Window(now time.Time) time.Time {
+	return now.Truncate(time.Minute)
+}
+
+// AllowWindow memeriksa hitungan request (termasuk request ini) pada jendela menit berjalan.
+// retryAfter adalah sisa waktu sampai jendela berikutnya jika request ditolak.
+func AllowWindow(count, perMinute int, now time.Time) (ok bool, retryAfter time.Duration) {
+	if perMinute <= 0 || count <= perMinute {
+		return true, 0
+	}
+	return false, Window(now).Add(time.Minute).Sub(now)
+}
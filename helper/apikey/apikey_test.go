@@ -0,0 +1,100 @@
+package apikey
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerate(t *testing.T) {
+	plain, display, hash, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Valid(plain) {
+		t.Errorf("key hasil Generate tidak valid: %q", plain)
+	}
+	if !strings.HasPrefix(plain, display) || len(display) != displayLength {
+		t.Errorf("display = %q untuk key %q", display, plain)
+	}
+	if hash != Hash(plain) || len(hash) != 64 || strings.Contains(hash, plain) {
+		t.Errorf("hash = %q", hash)
+	}
+	other, _, _, _ := Generate()
+	if other == plain {
+		t.Error("dua key tidak boleh sama")
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, in := range []string{"", "pdfm_", "abc_" + strings.Repeat("a", 40), "pdfm_" + strings.Repeat("1", 40), "pdfm_" + strings.Repeat("a", 39)} {
+		if Valid(in) {
+			t.Errorf("Valid(%q) seharusnya false", in)
+		}
+	}
+}
+
+func TestNormalizeScopes(t *testing.T) {
+	known := func(s string) bool { return s == "merge" || s == "compress" || s == "files" }
+	got, err := NormalizeScopes([]string{" Merge", "files", "merge", ""}, known)
+	if err != nil || !reflect.DeepEqual(got, []string{"files", "merge"}) {
+		t.Errorf("NormalizeScopes = %v, %v", got, err)
+	}
+	if got, _ := NormalizeScopes([]string{"merge", "*"}, known); !reflect.DeepEqual(got, []string{ScopeAll}) {
+		t.Errorf("ScopeAll = %v", got)
+	}
+	if _, err := NormalizeScopes([]string{"split"}, known); err == nil {
+		t.Error("scope tidak dikenal harus ditolak")
+	}
+	if _, err := NormalizeScopes(nil, known); err != ErrNoScope {
+		t.Errorf("tanpa scope err = %v", err)
+	}
+}
+
+func TestAllows(t *testing.T) {
+	if !Allows([]string{"merge"}, "merge") || Allows([]string{"merge"}, "compress") || !Allows([]string{ScopeAll}, "compress") {
+		t.Error("Allows tidak sesuai")
+	}
+}
+
+func TestLimiters(t *testing.T) {
+	ls := NewLimiters()
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		if ok, _ := ls.Allow("k1", 3, now); !ok {
+			t.Fatalf("request %d seharusnya diizinkan", i+1)
+		}
+	}
+	ok, retry := ls.Allow("k1", 3, now)
+	if ok || retry <= 0 || retry > 20*time.Second {
+		t.Errorf("request ke-4 = %v, retry %v", ok, retry)
+	}
+	if ok, _ := ls.Allow("k2", 3, now); !ok {
+		t.Error("limiter key lain harus terpisah")
+	}
+	// Batas baru membuat limiter baru
+	if ok, _ := ls.Allow("k1", 10, now); !ok {
+		t.Error("perubahan batas harus mereset limiter")
+	}
+	if ok, _ := ls.Allow("k1", 3, now.Add(20*time.Second)); !ok {
+		t.Error("setelah menunggu, request harus diizinkan lagi")
+	}
+}
+
+func TestAllowWindow(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 15, 40, 0, time.UTC)
+	if got := Window(now); !got.Equal(time.Date(2024, 5, 1, 10, 15, 0, 0, time.UTC)) {
+		t.Errorf("Window = %v", got)
+	}
+	if ok, _ := AllowWindow(3, 3, now); !ok {
+		t.Error("request ke-3 dari batas 3 seharusnya diizinkan")
+	}
+	ok, retry := AllowWindow(4, 3, now)
+	if ok || retry != 20*time.Second {
+		t.Errorf("request ke-4 = %v, retry %v", ok, retry)
+	}
+	if ok, _ := AllowWindow(100, 0, now); !ok {
+		t.Error("batas 0 berarti tanpa batas")
+	}
+}
@@ -9,3 +9,15 @@ func TestGetPresensiThisMonth(t *testing.T) {
 	print(uri)
 
 }
+
+func TestPathParams(t *testing.T) {
+	params, ok := PathParams("/pdfm/history/65b1/rerun", "/pdfm/history/:id/rerun")
+	if !ok || params["id"] != "65b1" {
+		t.Errorf("PathParams = %v, %v", params, ok)
+	}
+	for _, path := range []string{"/pdfm/history//rerun", "/pdfm/history/65b1", "/pdfm/history/65b1/rerun/x", "/pdfm/log/65b1/rerun"} {
+		if MatchPath(path, "/pdfm/history/:id/rerun") {
+			t.Errorf("MatchPath(%q) seharusnya false", path)
+		}
+	}
+}
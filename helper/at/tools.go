@@ -16,6 +16,42 @@ func URLParam(reqpath string, url string) bool {
 	return prefix == urls[0]
 }
 
+// PathParams mencocokkan path dengan pola yang boleh berisi parameter ":nama" di segmen mana pun,
+// mis. "/pdfm/history/:id/rerun", lalu mengembalikan nilai parameternya.
+func PathParams(reqpath string, pattern string) (map[string]string, bool) {
+	reqsegs := strings.Split(strings.Trim(reqpath, "/"), "/")
+	patsegs := strings.Split(strings.Trim(pattern, "/"), "/")
+	if len(reqsegs) != len(patsegs) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, seg := range patsegs {
+		if strings.HasPrefix(seg, ":") {
+			if reqsegs[i] == "" {
+				return nil, false
+			}
+			params[seg[1:]] = reqsegs[i]
+			continue
+		}
+		if seg != reqsegs[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// MatchPath bernilai true jika path cocok dengan pola PathParams
+func MatchPath(reqpath string, pattern string) bool {
+	_, ok := PathParams(reqpath, pattern)
+	return ok
+}
+
+// PathParam mengambil satu parameter dari path sesuai pola, kosong jika tidak cocok
+func PathParam(r *http.Request, pattern string, name string) string {
+	params, _ := PathParams(r.URL.Path, pattern)
+	return params[name]
+}
+
 func GetParam(r *http.Request) string {
 	return r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
 }
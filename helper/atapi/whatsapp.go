@@ -0,0 +1,24 @@
+package atapi
+
+import (
+	"net/http"
+
+	"github.com/gocroot/helper/metrics"
+	"github.com/whatsauth/itmodel"
+)
+
+// PostWhatsApp mengirim pesan lewat API WhatsApp milik profile (URLAPIText, URLAPIImage atau
+// URLAPIDoc) dan mencatat hasilnya ke metrics per nomor profile. Status selain 200 dihitung gagal.
+func PostWhatsApp(profile itmodel.Profile, msg interface{}, urltarget string) (statusCode int, result itmodel.Response, err error) {
+	statusCode, result, err = PostStructWithToken[itmodel.Response]("Token", profile.Token, msg, urltarget)
+	outcome := metrics.ResultSuccess
+	if err != nil || statusCode != http.StatusOK {
+		outcome = metrics.ResultFailure
+	}
+	label := profile.Phonenumber
+	if label == "" {
+		label = "unknown"
+	}
+	metrics.WhatsAppMessages.WithLabelValues(label, outcome).Inc()
+	return
+}
@@ -15,10 +15,10 @@ import (
 )
 
 func MongoConnect(mconn DBInfo) (db *mongo.Database, err error) {
-	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(mconn.DBString))
+	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(mconn.DBString).SetMonitor(mconn.Monitor))
 	if err != nil {
 		mconn.DBString = SRVLookup(mconn.DBString)
-		client, err = mongo.Connect(context.TODO(), options.Client().ApplyURI(mconn.DBString))
+		client, err = mongo.Connect(context.TODO(), options.Client().ApplyURI(mconn.DBString).SetMonitor(mconn.Monitor))
 		if err != nil {
 			return
 		}
@@ -106,6 +106,18 @@ func GetRandomDoc[T any](db *mongo.Database, collection string, size uint) (resu
 	return
 }
 
+// AggregateDoc menjalankan aggregation pipeline dan men-decode seluruh hasilnya ke []T
+func AggregateDoc[T any](db *mongo.Database, collection string, pipeline interface{}) (result []T, err error) {
+	ctx := context.Background()
+	cursor, err := db.Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return
+	}
+	defer cursor.Close(ctx)
+	err = cursor.All(ctx, &result)
+	return
+}
+
 func GetAllDoc[T any](db *mongo.Database, collection string, filter bson.M) (doc T, err error) {
 	ctx := context.TODO()
 	cur, err := db.Collection(collection).Find(ctx, filter)
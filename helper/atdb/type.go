@@ -1,8 +1,12 @@
 package atdb
 
+import "go.mongodb.org/mongo-driver/event"
+
 type DBInfo struct {
 	DBString string
 	DBName   string
+	// Monitor opsional, dipasang di client untuk mengamati setiap perintah (mis. metrik latensi)
+	Monitor *event.CommandMonitor
 }
 
 type NewLiburNasional struct {
@@ -78,7 +78,7 @@ func SendWhatsAppPassword(respw http.ResponseWriter, phoneNumber string, passwor
     }
 
     // Send WhatsApp message
-    _, resp, err := atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken, dt, config.WAAPIMessage)
+    _, resp, err := atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken(), dt, config.WAAPIMessage)
     if err != nil {
 		resp.Info = "message: unauthorized"
 		resp.Response = err.Error()
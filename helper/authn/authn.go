@@ -0,0 +1,170 @@
+package authn
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocroot/helper/watoken"
+	"github.com/gocroot/model"
+)
+
+// Skema token yang menghasilkan Principal
+const (
+	SchemeSession = "session" // token UUID pdfm di header Authorization: Bearer
+	SchemePASETO  = "paseto"  // watoken PASETO di header Login (domyikado/Bukupedia)
+	SchemeAPIKey  = "apikey"  // personal API key di header X-API-Key
+)
+
+// Role yang dipakai untuk otorisasi
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+var (
+	// ErrNoCredentials berarti request tidak membawa kredensial untuk skema tersebut, sehingga
+	// Chain mencoba authenticator berikutnya
+	ErrNoCredentials = errors.New("token tidak ditemukan")
+	ErrMalformed     = errors.New("format token salah")
+	ErrInvalid       = errors.New("token tidak valid")
+	ErrExpired       = errors.New("token sudah kadaluarsa")
+)
+
+// Principal adalah identitas hasil autentikasi, apa pun skema tokennya
+type Principal struct {
+	Scheme    string
+	Subject   string // ID user pdfm (hex) atau nomor telepon untuk PASETO
+	Name      string
+	Email     string
+	Roles     []string
+	Scopes    []string // scope API key atau token PASETO; kosong berarti tidak dibatasi scope
+	KeyID     string   // ID API key
+	TokenID   string   // jti token PASETO, untuk pencabutan
+	ExpiresAt time.Time
+	// Supporter berarti pemilik token berlangganan paket supporter (PdfmUsers.IsSupport). Ini atribut
+	// paket, bukan role staf, sehingga tidak memberi akses tambahan apa pun.
+	Supporter bool
+	// User adalah akun pdfm pemilik token; nil untuk token PASETO yang bukan akun pdfm
+	User *model.PdfmUsers
+}
+
+// HasRole bernilai true jika principal memiliki role tersebut
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// UserRoles mengembalikan role akun pdfm berdasarkan flag IsAdmin. IsSupport adalah paket berbayar,
+// bukan role (lihat Principal.Supporter).
+func UserRoles(user model.PdfmUsers) []string {
+	roles := []string{RoleUser}
+	if user.IsAdmin {
+		roles = append(roles, RoleAdmin)
+	}
+	return roles
+}
+
+// Authenticator mengubah kredensial di request menjadi Principal.
+// ErrNoCredentials dikembalikan jika request tidak membawa kredensial skema tersebut.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// AuthenticatorFunc mengubah fungsi biasa menjadi Authenticator
+type AuthenticatorFunc func(r *http.Request) (*Principal, error)
+
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (*Principal, error) {
+	return f(r)
+}
+
+// Chain mencoba setiap authenticator berurutan. Principal yang sudah ada di context request
+// (mis. dari middleware API key) selalu dipakai lebih dulu.
+type Chain []Authenticator
+
+func (c Chain) Authenticate(r *http.Request) (*Principal, error) {
+	if p := FromContext(r.Context()); p != nil {
+		return p, nil
+	}
+	for _, a := range c {
+		p, err := a.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return p, err
+	}
+	return nil, ErrNoCredentials
+}
+
+// BearerToken membaca token dari header Authorization: Bearer <token>
+func BearerToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", ErrNoCredentials
+	}
+	const prefix = "Bearer "
+	if len(header) <= len(prefix) || !strings.HasPrefix(header, prefix) {
+		return "", ErrMalformed
+	}
+	return header[len(prefix):], nil
+}
+
+// Session mengautentikasi token UUID pdfm. Resolve mencari token di database dan mengembalikan
+// Principal pemiliknya, atau ErrInvalid/ErrExpired.
+type Session struct {
+	Resolve func(ctx context.Context, token string) (*Principal, error)
+}
+
+func (s Session) Authenticate(r *http.Request) (*Principal, error) {
+	token, err := BearerToken(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.Resolve(r.Context(), token)
+}
+
+// PASETO mengautentikasi watoken di header Login dengan keyring. Keyring berupa fungsi karena
+// kunci publik WhatsAuth baru tersedia setelah profil dimuat dari database.
+type PASETO struct {
+	Keyring func() *watoken.Keyring
+	Options watoken.VerifyOptions
+}
+
+func (p PASETO) Authenticate(r *http.Request) (*Principal, error) {
+	token := r.Header.Get("Login")
+	if token == "" {
+		return nil, ErrNoCredentials
+	}
+	payload, err := p.Keyring().Verify(token, p.Options)
+	if err != nil {
+		return nil, errors.Join(ErrInvalid, err)
+	}
+	return &Principal{
+		Scheme:    SchemePASETO,
+		Subject:   payload.Id,
+		Name:      payload.Alias,
+		Roles:     []string{RoleUser},
+		Scopes:    payload.Scope,
+		TokenID:   payload.Jti,
+		ExpiresAt: payload.Exp,
+	}, nil
+}
+
+type contextKey struct{}
+
+// WithPrincipal menyimpan principal di context request
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext mengembalikan principal yang disimpan WithPrincipal, atau nil
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(contextKey{}).(*Principal)
+	return p
+}
@@ -0,0 +1,78 @@
+package authn
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocroot/helper/watoken"
+	"github.com/gocroot/model"
+)
+
+func TestBearerToken(t *testing.T) {
+	cases := map[string]error{"": ErrNoCredentials, "Bearer ": ErrMalformed, "Basic abc": ErrMalformed, "Bearer abc": nil}
+	for header, want := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			r.Header.Set("Authorization", header)
+		}
+		token, err := BearerToken(r)
+		if err != want || (want == nil && token != "abc") {
+			t.Errorf("BearerToken(%q) = %q, %v", header, token, err)
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	privateKey, publicKey := watoken.GenerateKey()
+	session := Session{Resolve: func(_ context.Context, token string) (*Principal, error) {
+		if token != "uuid-ok" {
+			return nil, ErrInvalid
+		}
+		user := model.PdfmUsers{Name: "Pipo", IsAdmin: true, IsSupport: true}
+		return &Principal{Scheme: SchemeSession, Roles: UserRoles(user), User: &user}, nil
+	}}
+	ring := (&watoken.Keyring{Keys: []watoken.Key{watoken.NewKey(time.Now())}}).WithVerifyKey("whatsauth", publicKey)
+	chain := Chain{session, PASETO{Keyring: func() *watoken.Keyring { return ring }}}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer uuid-ok")
+	p, err := chain.Authenticate(r)
+	if err != nil || p.Scheme != SchemeSession || !p.HasRole(RoleAdmin) || len(p.Roles) != 2 {
+		t.Errorf("session = %+v, %v", p, err)
+	}
+
+	r.Header.Set("Authorization", "Bearer salah")
+	if _, err := chain.Authenticate(r); err != ErrInvalid {
+		t.Errorf("token salah err = %v", err)
+	}
+
+	token, _ := watoken.EncodeforHours("6281234", "Pipo", privateKey, 1)
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Login", token)
+	p, err = chain.Authenticate(r)
+	if err != nil || p.Scheme != SchemePASETO || p.Subject != "6281234" || p.Name != "Pipo" || p.User != nil {
+		t.Errorf("paseto = %+v, %v", p, err)
+	}
+	signed, jti, _ := ring.Sign(watoken.Claims{ID: "6289", Scopes: []string{"bc"}}, time.Now())
+	r.Header.Set("Login", signed)
+	if p, err := chain.Authenticate(r); err != nil || p.TokenID != jti || len(p.Scopes) != 1 {
+		t.Errorf("paseto keyring = %+v, %v", p, err)
+	}
+	r.Header.Set("Login", "v4.public.rusak")
+	if _, err := chain.Authenticate(r); !errors.Is(err, ErrInvalid) {
+		t.Errorf("paseto rusak err = %v", err)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, err := chain.Authenticate(r); err != ErrNoCredentials {
+		t.Errorf("tanpa kredensial err = %v", err)
+	}
+	key := &Principal{Scheme: SchemeAPIKey, Scopes: []string{"merge"}}
+	if p, _ := chain.Authenticate(r.WithContext(WithPrincipal(r.Context(), key))); p != key {
+		t.Error("principal di context harus dipakai lebih dulu")
+	}
+}
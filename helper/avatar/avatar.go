@@ -0,0 +1,167 @@
+package avatar
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+	"strconv"
+	"strings"
+
+	// Decoder format yang diterima sebagai foto profil
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Sizes adalah ukuran avatar (px, persegi) yang dihasilkan dari setiap foto
+var Sizes = []int{64, 256}
+
+// ContentType adalah tipe semua avatar hasil proses
+const ContentType = "image/jpeg"
+
+// MaxPixels membatasi resolusi foto sumber agar foto kecil yang berdimensi raksasa tidak menghabiskan memori.
+// Decode 16 MP ke RGBA butuh sekitar 64 MB dan Orient bisa menyalinnya sekali lagi.
+const MaxPixels = 16_000_000
+
+var (
+	ErrEmpty       = errors.New("foto kosong")
+	ErrInvalidData = errors.New("data foto tidak valid")
+	ErrUnsupported = errors.New("format foto harus JPEG, PNG, GIF atau WebP")
+	ErrTooLarge    = errors.New("resolusi foto terlalu besar")
+)
+
+// allowedTypes adalah tipe hasil sniffing isi file (bukan dari nama file atau header data URL)
+var allowedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// Variant adalah satu ukuran avatar hasil proses
+type Variant struct {
+	Size int
+	Data []byte
+	ETag string
+}
+
+// DecodeDataURL menerima data URL ("data:image/png;base64,...") atau base64 polos
+func DecodeDataURL(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, ErrEmpty
+	}
+	if strings.HasPrefix(s, "data:") {
+		comma := strings.IndexByte(s, ',')
+		if comma < 0 || !strings.HasSuffix(s[:comma], ";base64") {
+			return nil, ErrInvalidData
+		}
+		s = s[comma+1:]
+	}
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		if data, err = base64.RawStdEncoding.DecodeString(s); err != nil {
+			return nil, ErrInvalidData
+		}
+	}
+	return data, nil
+}
+
+// SourceType mendeteksi tipe gambar dari isi data dan menolak tipe yang tidak didukung
+func SourceType(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", ErrEmpty
+	}
+	contentType := http.DetectContentType(data)
+	if !allowedTypes[contentType] {
+		return "", ErrUnsupported
+	}
+	return contentType, nil
+}
+
+// Process memvalidasi foto, memutar sesuai orientasi EXIF, memotong persegi di tengah lalu
+// mengecilkan ke setiap ukuran di Sizes. Hasil di-encode ulang sebagai JPEG sehingga seluruh
+// metadata (EXIF, lokasi GPS, dsb.) ikut terbuang.
+func Process(data []byte) ([]Variant, error) {
+	contentType, err := SourceType(data)
+	if err != nil {
+		return nil, err
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidData
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidData
+	}
+	if contentType == "image/jpeg" {
+		src = Orient(src, Orientation(data))
+	}
+	square := cropSquare(src)
+
+	variants := make([]Variant, 0, len(Sizes))
+	for _, size := range Sizes {
+		dst := image.NewRGBA(image.Rect(0, 0, size, size))
+		// Latar putih untuk gambar transparan karena JPEG tidak punya kanal alpha
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.CatmullRom.Scale(dst, dst.Bounds(), square, square.Bounds(), draw.Over, nil)
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, err
+		}
+		variants = append(variants, Variant{Size: size, Data: buf.Bytes(), ETag: ETag(buf.Bytes())})
+	}
+	return variants, nil
+}
+
+// ValidSize bernilai true jika size adalah salah satu ukuran di Sizes
+func ValidSize(raw string) (int, bool) {
+	size, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, false
+	}
+	for _, s := range Sizes {
+		if s == size {
+			return size, true
+		}
+	}
+	return 0, false
+}
+
+// ETag adalah strong ETag dari isi file
+func ETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// cropSquare mengambil area persegi terbesar di tengah gambar
+func cropSquare(img image.Image) image.Image {
+	b := img.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+	rect := image.Rect(x0, y0, x0+side, y0+side)
+	if sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst
+}
@@ -0,0 +1,149 @@
+package avatar
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// withOrientation menyisipkan segmen APP1 EXIF berisi tag Orientation setelah SOI
+func withOrientation(jpg []byte, orientation uint16) []byte {
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1}
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry[0:], 0x0112)
+	binary.BigEndian.PutUint16(entry[2:], 3) // SHORT
+	binary.BigEndian.PutUint32(entry[4:], 1)
+	binary.BigEndian.PutUint16(entry[8:], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0)
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	seg := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:], uint16(len(payload)+2))
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, seg...)
+	out = append(out, payload...)
+	return append(out, jpg[2:]...)
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestOrientation(t *testing.T) {
+	plain := encodeJPEG(t, solid(8, 4, color.White))
+	if got := Orientation(plain); got != 1 {
+		t.Errorf("Orientation tanpa EXIF = %d", got)
+	}
+	if got := Orientation(withOrientation(plain, 6)); got != 6 {
+		t.Errorf("Orientation = %d, want 6", got)
+	}
+	if got := Orientation([]byte("bukan jpeg")); got != 1 {
+		t.Errorf("Orientation data rusak = %d", got)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	img.Set(1, 0, color.RGBA{0, 0, 255, 255})
+	rotated := Orient(img, 6)
+	if b := rotated.Bounds(); b.Dx() != 1 || b.Dy() != 2 {
+		t.Fatalf("ukuran setelah rotasi = %v", b)
+	}
+	if r, _, _, _ := rotated.At(0, 0).RGBA(); r>>8 != 255 {
+		t.Errorf("piksel (0,0) seharusnya merah")
+	}
+	if _, _, b, _ := rotated.At(0, 1).RGBA(); b>>8 != 255 {
+		t.Errorf("piksel (0,1) seharusnya biru")
+	}
+}
+
+func TestProcess(t *testing.T) {
+	src := withOrientation(encodeJPEG(t, solid(300, 200, color.RGBA{200, 10, 10, 255})), 6)
+	variants, err := Process(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(variants) != len(Sizes) {
+		t.Fatalf("jumlah varian = %d", len(variants))
+	}
+	for i, v := range variants {
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(v.Data))
+		if err != nil || format != "jpeg" || cfg.Width != Sizes[i] || cfg.Height != Sizes[i] {
+			t.Errorf("varian %d: %v %s %dx%d", Sizes[i], err, format, cfg.Width, cfg.Height)
+		}
+		if bytes.Contains(v.Data, []byte("Exif")) {
+			t.Errorf("varian %d masih berisi EXIF", Sizes[i])
+		}
+		if v.ETag != ETag(v.Data) || len(v.ETag) != 34 {
+			t.Errorf("ETag = %q", v.ETag)
+		}
+	}
+
+	var buf bytes.Buffer
+	png.Encode(&buf, solid(10, 30, color.Transparent))
+	if _, err := Process(buf.Bytes()); err != nil {
+		t.Errorf("PNG transparan: %v", err)
+	}
+	if _, err := Process([]byte("%PDF-1.4 bukan gambar")); err != ErrUnsupported {
+		t.Errorf("PDF err = %v", err)
+	}
+	if _, err := Process(nil); err != ErrEmpty {
+		t.Errorf("kosong err = %v", err)
+	}
+
+	// Header GIF 5000x4000 (20 MP) tanpa data piksel: harus ditolak sebelum decode
+	huge := []byte("GIF89a")
+	huge = binary.LittleEndian.AppendUint16(huge, 5000)
+	huge = binary.LittleEndian.AppendUint16(huge, 4000)
+	huge = append(huge, 0, 0, 0, ';')
+	if _, err := Process(huge); err != ErrTooLarge {
+		t.Errorf("20 MP err = %v", err)
+	}
+}
+
+func TestDecodeDataURL(t *testing.T) {
+	raw := []byte{0x89, 'P', 'N', 'G'}
+	enc := base64.StdEncoding.EncodeToString(raw)
+	for _, in := range []string{"data:image/png;base64," + enc, enc, base64.RawStdEncoding.EncodeToString(raw)} {
+		got, err := DecodeDataURL(in)
+		if err != nil || !bytes.Equal(got, raw) {
+			t.Errorf("DecodeDataURL(%q) = %v, %v", in, got, err)
+		}
+	}
+	for _, in := range []string{"", "data:image/png,abc", "!!!"} {
+		if _, err := DecodeDataURL(in); err == nil {
+			t.Errorf("DecodeDataURL(%q) seharusnya error", in)
+		}
+	}
+}
+
+func TestValidSize(t *testing.T) {
+	if size, ok := ValidSize("256"); !ok || size != 256 {
+		t.Errorf("ValidSize(256) = %d, %v", size, ok)
+	}
+	for _, in := range []string{"128", "abc", ""} {
+		if _, ok := ValidSize(in); ok {
+			t.Errorf("ValidSize(%q) seharusnya false", in)
+		}
+	}
+}
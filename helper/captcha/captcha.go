@@ -0,0 +1,99 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TurnstileEndpoint adalah endpoint verifikasi Cloudflare Turnstile
+const TurnstileEndpoint = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+
+var (
+	// ErrInvalid berarti token CAPTCHA ditolak penyedia (salah, kedaluwarsa, atau sudah dipakai)
+	ErrInvalid = errors.New("invalid captcha")
+	// ErrNotConfigured berarti secret CAPTCHA belum diset sehingga semua token ditolak
+	ErrNotConfigured = errors.New("captcha secret is not configured")
+)
+
+// Verifier memeriksa token CAPTCHA yang dikirim frontend. Error selain ErrInvalid berarti
+// penyedia CAPTCHA tidak bisa dihubungi.
+type Verifier interface {
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// VerifierFunc mengubah fungsi biasa menjadi Verifier, berguna untuk test
+type VerifierFunc func(ctx context.Context, token, remoteIP string) error
+
+func (f VerifierFunc) Verify(ctx context.Context, token, remoteIP string) error {
+	return f(ctx, token, remoteIP)
+}
+
+// Disabled menerima semua token; hanya untuk pengembangan lokal
+type Disabled struct{}
+
+func (Disabled) Verify(context.Context, string, string) error { return nil }
+
+// Turnstile memverifikasi token Cloudflare Turnstile. Endpoint dan Client boleh kosong.
+type Turnstile struct {
+	Secret   string
+	Endpoint string
+	Client   *http.Client
+}
+
+type turnstileResult struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+func (t Turnstile) Verify(ctx context.Context, token, remoteIP string) error {
+	if t.Secret == "" {
+		return ErrNotConfigured
+	}
+	if strings.TrimSpace(token) == "" {
+		return ErrInvalid
+	}
+	endpoint := t.Endpoint
+	if endpoint == "" {
+		endpoint = TurnstileEndpoint
+	}
+	client := t.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	form := url.Values{"secret": {t.Secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("turnstile status %d", resp.StatusCode)
+	}
+
+	var result turnstileResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if !result.Success {
+		if len(result.ErrorCodes) > 0 {
+			return fmt.Errorf("%w: %s", ErrInvalid, strings.Join(result.ErrorCodes, ", "))
+		}
+		return ErrInvalid
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package captcha
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTurnstile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("secret") != "rahasia" || r.Form.Get("remoteip") != "10.0.0.1" {
+			t.Errorf("form = %v", r.Form)
+		}
+		if r.Form.Get("response") == "ok" {
+			w.Write([]byte(`{"success":true}`))
+			return
+		}
+		w.Write([]byte(`{"success":false,"error-codes":["timeout-or-duplicate"]}`))
+	}))
+	defer srv.Close()
+
+	v := Turnstile{Secret: "rahasia", Endpoint: srv.URL}
+	if err := v.Verify(context.Background(), "ok", "10.0.0.1"); err != nil {
+		t.Fatalf("token valid ditolak: %v", err)
+	}
+	if err := v.Verify(context.Background(), "lama", "10.0.0.1"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("token lama = %v, want ErrInvalid", err)
+	}
+	if err := v.Verify(context.Background(), "", ""); !errors.Is(err, ErrInvalid) {
+		t.Errorf("token kosong = %v, want ErrInvalid", err)
+	}
+}
+
+func TestTurnstileNotConfigured(t *testing.T) {
+	if err := (Turnstile{}).Verify(context.Background(), "ok", ""); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("tanpa secret = %v, want ErrNotConfigured", err)
+	}
+}
+
+func TestTurnstileUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	err := Turnstile{Secret: "x", Endpoint: srv.URL}.Verify(context.Background(), "ok", "")
+	if err == nil || errors.Is(err, ErrInvalid) {
+		t.Errorf("penyedia error harus dibedakan dari token invalid, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package googleid
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/api/idtoken"
+)
+
+var (
+	ErrNoSubject     = errors.New("ID token tidak berisi sub")
+	ErrNoEmail       = errors.New("ID token tidak berisi email")
+	ErrEmailUnverify = errors.New("email akun Google belum terverifikasi")
+)
+
+// Identity adalah klaim ID token Google yang dipakai untuk login
+type Identity struct {
+	Subject       string // ID akun Google, tetap walaupun email berubah
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+}
+
+// Verifier memverifikasi ID token untuk audience (OAuth client ID) tertentu.
+// Controller menyimpannya sebagai variabel sehingga test bisa memakai verifier palsu tanpa jaringan.
+type Verifier interface {
+	Verify(ctx context.Context, idToken, audience string) (Identity, error)
+}
+
+// VerifierFunc mengubah fungsi biasa menjadi Verifier
+type VerifierFunc func(ctx context.Context, idToken, audience string) (Identity, error)
+
+func (f VerifierFunc) Verify(ctx context.Context, idToken, audience string) (Identity, error) {
+	return f(ctx, idToken, audience)
+}
+
+// Google memverifikasi tanda tangan, issuer, audience dan masa berlaku token dengan kunci publik Google
+var Google Verifier = VerifierFunc(func(ctx context.Context, idToken, audience string) (Identity, error) {
+	payload, err := idtoken.Validate(ctx, idToken, audience)
+	if err != nil {
+		return Identity{}, err
+	}
+	return FromClaims(payload.Subject, payload.Claims)
+})
+
+// FromClaims membaca Identity dari klaim token yang sudah diverifikasi. Email wajib ada dan terverifikasi
+// karena dipakai untuk menautkan akun yang sudah ada.
+func FromClaims(subject string, claims map[string]interface{}) (Identity, error) {
+	id := Identity{Subject: subject}
+	if id.Subject == "" {
+		id.Subject, _ = claims["sub"].(string)
+	}
+	email, _ := claims["email"].(string)
+	id.Email = strings.ToLower(strings.TrimSpace(email))
+	id.Name, _ = claims["name"].(string)
+	id.Picture, _ = claims["picture"].(string)
+	// email_verified biasanya boolean, tetapi beberapa token lama mengirimnya sebagai string
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		id.EmailVerified = v
+	case string:
+		id.EmailVerified = v == "true"
+	}
+
+	switch {
+	case id.Subject == "":
+		return id, ErrNoSubject
+	case id.Email == "":
+		return id, ErrNoEmail
+	case !id.EmailVerified:
+		return id, ErrEmailUnverify
+	}
+	if id.Name == "" {
+		id.Name = id.Email[:strings.Index(id.Email+"@", "@")]
+	}
+	return id, nil
+}
+
+// Static mengembalikan Verifier untuk test: token yang ada di map dianggap valid untuk audience apa pun
+func Static(tokens map[string]Identity) Verifier {
+	return VerifierFunc(func(_ context.Context, idToken, _ string) (Identity, error) {
+		id, ok := tokens[idToken]
+		if !ok {
+			return Identity{}, errors.New("ID token tidak dikenal")
+		}
+		return id, nil
+	})
+}
@@ -0,0 +1,44 @@
+package googleid
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFromClaims(t *testing.T) {
+	id, err := FromClaims("", map[string]interface{}{
+		"sub":            "1234",
+		"email":          " Pipo@Example.com ",
+		"email_verified": true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Subject != "1234" || id.Email != "pipo@example.com" || id.Name != "pipo" {
+		t.Errorf("identity = %+v", id)
+	}
+	if id, err := FromClaims("99", map[string]interface{}{"email": "a@b.c", "email_verified": "true", "name": "A"}); err != nil || id.Subject != "99" || id.Name != "A" {
+		t.Errorf("string email_verified = %+v, %v", id, err)
+	}
+
+	cases := map[error]map[string]interface{}{
+		ErrNoSubject:     {"email": "a@b.c", "email_verified": true},
+		ErrNoEmail:       {"sub": "1", "email_verified": true},
+		ErrEmailUnverify: {"sub": "1", "email": "a@b.c", "email_verified": false},
+	}
+	for want, claims := range cases {
+		if _, err := FromClaims("", claims); err != want {
+			t.Errorf("FromClaims(%v) err = %v, want %v", claims, err, want)
+		}
+	}
+}
+
+func TestStatic(t *testing.T) {
+	v := Static(map[string]Identity{"ok": {Subject: "1", Email: "a@b.c"}})
+	if id, err := v.Verify(context.Background(), "ok", "client"); err != nil || id.Subject != "1" {
+		t.Errorf("Verify(ok) = %+v, %v", id, err)
+	}
+	if _, err := v.Verify(context.Background(), "palsu", "client"); err == nil {
+		t.Error("token tidak dikenal harus ditolak")
+	}
+}
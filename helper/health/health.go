@@ -0,0 +1,135 @@
+// Package health menjalankan pemeriksaan dependensi untuk endpoint readiness. Setiap pemeriksaan
+// dijalankan paralel dengan batas waktunya sendiri dan dilaporkan beserta latensinya, sehingga load
+// balancer cukup membaca status HTTP dan operator bisa melihat dependensi mana yang bermasalah.
+package health
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gocroot/model"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// Status pemeriksaan
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Check adalah satu pemeriksaan dependensi. Run harus berhenti saat ctx selesai.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Run menjalankan semua pemeriksaan secara paralel, masing-masing dibatasi timeout. Urutan hasil
+// mengikuti urutan checks. Status laporan "fail" jika ada satu pemeriksaan yang gagal.
+func Run(ctx context.Context, timeout time.Duration, checks ...Check) model.HealthResponse {
+	results := make([]model.HealthCheck, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c Check) {
+			defer wg.Done()
+			results[i] = runOne(ctx, timeout, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := model.HealthResponse{Status: StatusOK, Checks: results, CheckedAt: time.Now()}
+	for _, r := range results {
+		if r.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+func runOne(ctx context.Context, timeout time.Duration, c Check) model.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				done <- fmt.Errorf("panic: %v", v)
+			}
+		}()
+		done <- c.Run(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timeout setelah %s", timeout)
+	}
+	result := model.HealthCheck{
+		Name:      c.Name,
+		Status:    StatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status, result.Error = StatusFail, err.Error()
+	}
+	return result
+}
+
+// TempDirWritable memeriksa bahwa dir (kosong berarti os.TempDir) bisa ditulisi, dengan membuat
+// lalu menghapus file sementara
+func TempDirWritable(dir string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		f, err := os.CreateTemp(dir, "pdfm-readyz-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(f.Name())
+		if _, err := f.Write([]byte("ok")); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+}
+
+// PDFCPU memeriksa bahwa pdfcpu bisa membaca dokumen PDF, termasuk memuat konfigurasinya
+func PDFCPU(ctx context.Context) error {
+	n, err := api.PageCount(bytes.NewReader(samplePDF), nil)
+	if err != nil {
+		return err
+	}
+	if n != 1 {
+		return errors.New("pdfcpu membaca jumlah halaman yang salah")
+	}
+	return nil
+}
+
+// samplePDF adalah PDF satu halaman kosong dengan tabel xref yang benar
+var samplePDF = func() []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 72 72] /Resources << >> >>",
+	}
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.Bytes()
+}()
@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunReport(t *testing.T) {
+	report := Run(context.Background(), 50*time.Millisecond,
+		Check{Name: "ok", Run: func(ctx context.Context) error { return nil }},
+		Check{Name: "gagal", Run: func(ctx context.Context) error { return errors.New("mati") }},
+		Check{Name: "lambat", Run: func(ctx context.Context) error { time.Sleep(time.Second); return nil }},
+		Check{Name: "panik", Run: func(ctx context.Context) error { panic("boom") }},
+	)
+	if report.Status != StatusFail || len(report.Checks) != 4 {
+		t.Fatalf("report = %+v", report)
+	}
+	want := []struct{ name, status, err string }{
+		{"ok", StatusOK, ""},
+		{"gagal", StatusFail, "mati"},
+		{"lambat", StatusFail, "timeout setelah 50ms"},
+		{"panik", StatusFail, "panic: boom"},
+	}
+	for i, w := range want {
+		c := report.Checks[i]
+		if c.Name != w.name || c.Status != w.status || c.Error != w.err {
+			t.Errorf("check %d = %+v", i, c)
+		}
+	}
+	if report.Checks[2].LatencyMS < 50 || report.Checks[2].LatencyMS > 500 {
+		t.Errorf("latency check lambat = %v ms", report.Checks[2].LatencyMS)
+	}
+
+	if ok := Run(context.Background(), time.Second, Check{Name: "ok", Run: func(ctx context.Context) error { return nil }}); ok.Status != StatusOK {
+		t.Errorf("status = %s", ok.Status)
+	}
+}
+
+func TestTempDirWritable(t *testing.T) {
+	if err := TempDirWritable(t.TempDir())(context.Background()); err != nil {
+		t.Error(err)
+	}
+	if err := TempDirWritable(filepath.Join(t.TempDir(), "tidak-ada"))(context.Background()); err == nil {
+		t.Error("direktori yang tidak ada seharusnya gagal")
+	}
+}
+
+func TestPDFCPU(t *testing.T) {
+	if err := PDFCPU(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
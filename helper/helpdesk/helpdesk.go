@@ -0,0 +1,160 @@
+package helpdesk
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// Status tiket feedback
+const (
+	StatusNew        = "new"
+	StatusInProgress = "in_progress"
+	StatusResolved   = "resolved"
+	StatusClosed     = "closed"
+)
+
+// Kategori feedback; CategoryOther dipakai untuk feedback lama atau yang tidak memilih kategori
+const (
+	CategoryBug     = "bug"
+	CategoryFeature = "feature"
+	CategoryBilling = "billing"
+	CategoryOther   = "other"
+)
+
+// Prioritas tiket, diurutkan dari yang paling rendah
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+// transitions berisi status tujuan yang boleh dari setiap status. Closed adalah status akhir,
+// sedangkan resolved masih bisa dibuka lagi jika masalah muncul kembali.
+var transitions = map[string][]string{
+	StatusNew:        {StatusInProgress, StatusResolved, StatusClosed},
+	StatusInProgress: {StatusResolved, StatusClosed},
+	StatusResolved:   {StatusInProgress, StatusClosed},
+	StatusClosed:     {},
+}
+
+var categories = []string{CategoryBug, CategoryFeature, CategoryBilling, CategoryOther}
+
+var priorities = []string{PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent}
+
+// Statuses mengembalikan semua status sesuai urutan lifecycle
+func Statuses() []string {
+	return []string{StatusNew, StatusInProgress, StatusResolved, StatusClosed}
+}
+
+// ValidStatus bernilai true jika s adalah status yang dikenal
+func ValidStatus(s string) bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// ValidCategory bernilai true jika c adalah kategori yang dikenal
+func ValidCategory(c string) bool {
+	return contains(categories, c)
+}
+
+// ValidPriority bernilai true jika p adalah prioritas yang dikenal
+func ValidPriority(p string) bool {
+	return contains(priorities, p)
+}
+
+// Rank mengembalikan urutan prioritas (0 = low) untuk pengurutan, -1 jika tidak dikenal
+func Rank(priority string) int {
+	for i, p := range priorities {
+		if p == priority {
+			return i
+		}
+	}
+	return -1
+}
+
+// Normalize mengisi kategori, prioritas dan status kosong dengan nilai default.
+// Feedback lama yang tersimpan sebelum ada lifecycle dibaca sebagai tiket baru.
+func Normalize(category, priority, status string) (string, string, string) {
+	if category == "" {
+		category = CategoryOther
+	}
+	if priority == "" {
+		priority = PriorityNormal
+	}
+	if status == "" {
+		status = StatusNew
+	}
+	return category, priority, status
+}
+
+// Transition memeriksa perpindahan status from -> to. Status yang sama dianggap valid (tanpa perubahan).
+func Transition(from, to string) error {
+	if from == "" {
+		from = StatusNew
+	}
+	if !ValidStatus(to) {
+		return fmt.Errorf("status %q tidak dikenal", to)
+	}
+	if from == to {
+		return nil
+	}
+	if !contains(transitions[from], to) {
+		return fmt.Errorf("status tidak bisa diubah dari %s ke %s", from, to)
+	}
+	return nil
+}
+
+// Open bernilai true jika tiket masih menunggu penanganan admin
+func Open(status string) bool {
+	return status == "" || status == StatusNew || status == StatusInProgress
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Tipe file yang boleh dilampirkan ke feedback, dideteksi dari isi file (bukan nama atau header client)
+var attachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+}
+
+// AttachmentType mendeteksi content type lampiran dan menolak selain PDF, PNG dan JPEG
+func AttachmentType(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("file kosong")
+	}
+	ct := http.DetectContentType(data)
+	if !attachmentTypes[ct] {
+		return "", fmt.Errorf("tipe file %s tidak diizinkan, hanya PDF, PNG atau JPEG", ct)
+	}
+	return ct, nil
+}
+
+// AttachmentName membersihkan nama file lampiran dari path dan karakter kontrol
+func AttachmentName(name string) string {
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "lampiran"
+	}
+	if r := []rune(name); len(r) > 120 {
+		name = string(r[len(r)-120:])
+	}
+	return name
+}
@@ -0,0 +1,83 @@
+package helpdesk
+
+import "testing"
+
+func TestTransition(t *testing.T) {
+	cases := []struct {
+		from, to string
+		ok       bool
+	}{
+		{"", StatusInProgress, true},
+		{StatusNew, StatusResolved, true},
+		{StatusInProgress, StatusNew, false},
+		{StatusResolved, StatusInProgress, true},
+		{StatusClosed, StatusInProgress, false},
+		{StatusClosed, StatusClosed, true},
+		{StatusNew, "pending", false},
+	}
+	for _, c := range cases {
+		err := Transition(c.from, c.to)
+		if (err == nil) != c.ok {
+			t.Errorf("Transition(%q, %q) = %v, want ok=%v", c.from, c.to, err, c.ok)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	c, p, s := Normalize("", "", "")
+	if c != CategoryOther || p != PriorityNormal || s != StatusNew {
+		t.Errorf("Normalize kosong = %s %s %s", c, p, s)
+	}
+	c, p, s = Normalize(CategoryBug, PriorityUrgent, StatusClosed)
+	if c != CategoryBug || p != PriorityUrgent || s != StatusClosed {
+		t.Errorf("Normalize terisi berubah: %s %s %s", c, p, s)
+	}
+}
+
+func TestValidation(t *testing.T) {
+	if !ValidCategory(CategoryBilling) || ValidCategory("complaint") {
+		t.Error("ValidCategory salah")
+	}
+	if !ValidPriority(PriorityHigh) || ValidPriority("critical") {
+		t.Error("ValidPriority salah")
+	}
+	if Rank(PriorityUrgent) <= Rank(PriorityLow) || Rank("x") != -1 {
+		t.Error("Rank salah")
+	}
+	if !Open("") || !Open(StatusInProgress) || Open(StatusResolved) {
+		t.Error("Open salah")
+	}
+}
+
+func TestAttachmentType(t *testing.T) {
+	cases := map[string]string{
+		"%PDF-1.7\n%\xe2\xe3\xcf\xd3":         "application/pdf",
+		"\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR": "image/png",
+		"\xff\xd8\xff\xe0\x00\x10JFIF\x00":    "image/jpeg",
+	}
+	for data, want := range cases {
+		got, err := AttachmentType([]byte(data))
+		if err != nil || got != want {
+			t.Errorf("AttachmentType(%q) = %s, %v; want %s", data[:4], got, err, want)
+		}
+	}
+	for _, data := range []string{"", "<html><script>alert(1)</script>", "GIF89a\x01\x00"} {
+		if _, err := AttachmentType([]byte(data)); err == nil {
+			t.Errorf("AttachmentType(%q) harus ditolak", data)
+		}
+	}
+}
+
+func TestAttachmentName(t *testing.T) {
+	cases := map[string]string{
+		"../../etc/passwd":          "passwd",
+		`C:\Users\budi\layar.png`:   "layar.png",
+		"  laporan\x00 rusak.pdf  ": "laporan rusak.pdf",
+		"":                          "lampiran",
+	}
+	for in, want := range cases {
+		if got := AttachmentName(in); got != want {
+			t.Errorf("AttachmentName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -32,7 +32,7 @@ func HubHandler(Profile itmodel.Profile, msg itmodel.IteungMessage, db *mongo.Da
 				IsGroup:  false,
 				Messages: msgstr,
 			}
-			go atapi.PostStructWithToken[itmodel.Response]("Token", Profile.Token, dt, Profile.URLAPIText)
+			go atapi.PostWhatsApp(Profile, dt, Profile.URLAPIText)
 			err = tiket.UpdateAdminMsgInTiket(msg.Phone_number, msg.Message, db)
 			if err != nil {
 				return "tiket tidak ditemukan atau sudah di tutup : " + err.Error()
@@ -53,7 +53,7 @@ func HubHandler(Profile itmodel.Profile, msg itmodel.IteungMessage, db *mongo.Da
 		IsGroup:  false,
 		Messages: msgstr,
 	}
-	go atapi.PostStructWithToken[itmodel.Response]("Token", Profile.Token, dt, Profile.URLAPIText)
+	go atapi.PostWhatsApp(Profile, dt, Profile.URLAPIText)
 	err = tiket.UpdateUserMsgInTiket(msg.Phone_number, msg.Message, db)
 	if err != nil {
 		return "tiket tidak ditemukan atau sudah di tutup : " + err.Error()
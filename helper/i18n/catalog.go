@@ -0,0 +1,339 @@
+package i18n
+
+// Kode pesan API. Kode dikirim di field "code" response agar frontend tidak bergantung pada teks.
+const (
+	MethodNotAllowed = "method_not_allowed"
+	InvalidBody      = "invalid_body"
+	SaveFailed       = "save_failed"
+	InternalError    = "internal_error"
+	Unauthorized     = "unauthorized"
+	ForbiddenAdmin   = "forbidden_admin"
+	InvalidQuery     = "invalid_query"
+	InvalidID        = "invalid_id"
+	InvalidCursor    = "invalid_cursor"
+	IDRequired       = "id_required"
+	FetchFailed      = "fetch_failed"
+	DeleteFailed     = "delete_failed"
+
+	TokenMissing       = "token_missing"
+	TokenInvalidFormat = "token_invalid_format"
+	TokenInvalid       = "token_invalid"
+	TokenSaveFailed    = "token_save_failed"
+	TokenRevoked       = "token_revoked"
+	TokenNoJti         = "token_no_jti"
+
+	RegisterRequired   = "register_required"
+	RegisterSuccess    = "register_success"
+	LoginInvalid       = "login_invalid"
+	LoginSuccess       = "login_success"
+	LogoutFailed       = "logout_failed"
+	LogoutSuccess      = "logout_success"
+	LanguageUnknown    = "language_unknown"
+	LanguageUpdated    = "language_updated"
+	UserNotFound       = "user_not_found"
+	UserInvalidID      = "user_invalid_id"
+	UserIDRequired     = "user_id_required"
+	UserEmailExists    = "user_email_exists"
+	UserNameEmailEmpty = "user_name_email_empty"
+	UserUpdated        = "user_updated"
+	UserUpdateFailed   = "user_update_failed"
+	UserDeleted        = "user_deleted"
+	UserRestored       = "user_restored"
+	UserSuspended      = "user_suspended"
+	UserUnsuspended    = "user_unsuspended"
+	UserSelfAction     = "user_self_action"
+	AccountSuspended   = "account_suspended"
+	PasswordMismatch   = "password_mismatch"
+	ExportFailed       = "export_failed"
+	ExportRateLimited  = "export_rate_limited"
+	DeletionScheduled  = "account_deletion_scheduled"
+	DeletionCancelled  = "account_deletion_cancelled"
+	DeletionNotFound   = "account_deletion_not_found"
+	AccountPurgeDone   = "account_purge_done"
+	AccountPurgeDryRun = "account_purge_dry_run"
+	PaymentMinimum     = "payment_minimum"
+	PaymentSuccess     = "payment_success"
+	InvoiceFetchFailed = "invoice_fetch_failed"
+	PhotoRequired      = "photo_required"
+	PhotoUpdated       = "photo_updated"
+	PhotoInvalid       = "photo_invalid"
+	PhotoTooLarge      = "photo_too_large"
+	PhotoNotFound      = "photo_not_found"
+	PhotoSizeInvalid   = "photo_size_invalid"
+
+	// Notifikasi, riwayat dan statistik
+	NotificationReadParam   = "notification_read_param"
+	NotificationRequired    = "notification_required"
+	NotificationCodeUnknown = "notification_code_unknown"
+	NotificationNotFound    = "notification_not_found"
+	PrefsChannelUnknown     = "prefs_channel_unknown"
+	PrefsWhatsAppRequired   = "prefs_whatsapp_required"
+	PrefsWhatsAppInvalid    = "prefs_whatsapp_invalid"
+	PrefsTimezoneInvalid    = "prefs_timezone_invalid"
+	PrefsQuietHoursInvalid  = "prefs_quiet_hours_invalid"
+	PrefsDigestHourInvalid  = "prefs_digest_hour_invalid"
+	DispatchQueueFailed     = "dispatch_queue_failed"
+	HistoryTypeInvalid      = "history_type_invalid"
+	HistorySortInvalid      = "history_sort_invalid"
+	HistoryNotFound         = "history_not_found"
+	HistoryDeleted          = "history_deleted"
+	FileInputInvalid        = "file_input_invalid"
+	ExportFormatInvalid     = "export_format_invalid"
+	RerunUnsupported        = "rerun_unsupported"
+	RerunInputsMissing      = "rerun_inputs_missing"
+	RerunInputsExpired      = "rerun_inputs_expired"
+	RerunInputReadFailed    = "rerun_input_read_failed"
+	RerunDetailsInvalid     = "rerun_details_invalid"
+	RerunFailed             = "rerun_failed"
+	StatsFailed             = "stats_failed"
+	StreamUnsupported       = "stream_unsupported"
+	FileRequired            = "file_required"
+	FileTooLarge            = "file_too_large"
+	FileReadFailed          = "file_read_failed"
+	FilePDFOnly             = "file_pdf_only"
+	FileUploaded            = "file_uploaded"
+	FileExpired             = "file_expired"
+	FileNotFound            = "file_not_found"
+
+	// Pengumuman dan retensi data
+	AnnouncementRequired        = "announcement_required"
+	AnnouncementAudienceInvalid = "announcement_audience_invalid"
+	AnnouncementSegmentEmpty    = "announcement_segment_empty"
+	AnnouncementLinkInvalid     = "announcement_link_invalid"
+	AnnouncementExpiryInvalid   = "announcement_expiry_invalid"
+	AnnouncementNotFound        = "announcement_not_found"
+	AnnouncementScheduled       = "announcement_scheduled"
+	AnnouncementPublished       = "announcement_published"
+	AnnouncementDeferred        = "announcement_deferred"
+	AnnouncementWithdrawn       = "announcement_withdrawn"
+	RetentionFailed             = "retention_failed"
+	RetentionPurged             = "retention_purged"
+	RetentionDryRun             = "retention_dry_run"
+
+	// Feedback
+	FeedbackMessageRequired   = "feedback_message_required"
+	FeedbackCategoryInvalid   = "feedback_category_invalid"
+	FeedbackStatusInvalid     = "feedback_status_invalid"
+	FeedbackPriorityInvalid   = "feedback_priority_invalid"
+	FeedbackAssigneeInvalid   = "feedback_assignee_invalid"
+	FeedbackAssigneeNotFound  = "feedback_assignee_not_found"
+	FeedbackNotFound          = "feedback_not_found"
+	FeedbackTransitionInvalid = "feedback_transition_invalid"
+	FeedbackReplyRequired     = "feedback_reply_required"
+
+	FeedbackAttachmentsTooLarge  = "feedback_attachments_too_large"
+	FeedbackAttachmentLimit      = "feedback_attachment_limit"
+	FeedbackAttachmentTooLarge   = "feedback_attachment_too_large"
+	FeedbackAttachmentReadFailed = "feedback_attachment_read_failed"
+	FeedbackAttachmentType       = "feedback_attachment_type"
+	FeedbackAttachmentSaveFailed = "feedback_attachment_save_failed"
+	FeedbackAttachmentNotFound   = "feedback_attachment_not_found"
+
+	FeedbackSentimentInvalid = "feedback_sentiment_invalid"
+	FeedbackLanguageInvalid  = "feedback_language_invalid"
+	FeedbackClusterInvalid   = "feedback_cluster_invalid"
+
+	// Form kontak tanpa login
+	FeedbackThanks           = "feedback_thanks"
+	FeedbackAnonymousInvalid = "feedback_anonymous_invalid"
+	ContactRateLimited       = "contact_rate_limited"
+	ContactRequired          = "contact_required"
+	ContactEmailInvalid      = "contact_email_invalid"
+	ContactMessageTooLong    = "contact_message_too_long"
+	ContactSpam              = "contact_spam"
+	CaptchaInvalid           = "captcha_invalid"
+	CaptchaUnavailable       = "captcha_unavailable"
+
+	// API key
+	APIKeyInvalid      = "apikey_invalid"
+	APIKeyEndpoint     = "apikey_endpoint"
+	APIKeyScope        = "apikey_scope"
+	APIKeyRateLimited  = "apikey_rate_limited"
+	APIKeyCreated      = "apikey_created"
+	APIKeyRevoked      = "apikey_revoked"
+	APIKeyNotFound     = "apikey_not_found"
+	APIKeyLimit        = "apikey_limit"
+	APIKeyNameRequired = "apikey_name_required"
+
+	// Login Google
+	GoogleUnavailable     = "google_unavailable"
+	GoogleTokenInvalid    = "google_token_invalid"
+	GoogleEmailUnverified = "google_email_unverified"
+	GoogleLinkRequired    = "google_link_required"
+	GoogleAccountConflict = "google_account_conflict"
+
+	// Template notifikasi
+	NotifMerge    = "notif_merge"
+	NotifCompress = "notif_compress"
+	NotifConvert  = "notif_convert"
+	NotifSummary  = "notif_summary"
+	NotifRerun    = "notif_rerun"
+
+	NotifFeedbackReply   = "notif_feedback_reply"
+	NotifAccountDeletion = "notif_account_deletion"
+)
+
+var catalog = map[string]map[string]string{
+	MethodNotAllowed: {ID: "Metode tidak diizinkan", EN: "Method not allowed"},
+	InvalidBody:      {ID: "Data tidak valid", EN: "Invalid request data"},
+	SaveFailed:       {ID: "Gagal menyimpan data", EN: "Failed to save data"},
+	InternalError:    {ID: "Terjadi kesalahan pada server", EN: "Internal server error"},
+	Unauthorized:     {ID: "Unauthorized", EN: "Unauthorized"},
+	ForbiddenAdmin:   {ID: "Akses khusus admin", EN: "Forbidden: Admin access required"},
+	InvalidQuery:     {ID: "Parameter tidak valid", EN: "Invalid query parameter"},
+	InvalidID:        {ID: "Format ID tidak valid", EN: "Invalid ID format"},
+	InvalidCursor:    {ID: "Cursor tidak valid", EN: "Invalid cursor"},
+	IDRequired:       {ID: "ID wajib diisi", EN: "ID is required"},
+	FetchFailed:      {ID: "Gagal mengambil data", EN: "Failed to fetch data"},
+	DeleteFailed:     {ID: "Gagal menghapus data", EN: "Failed to delete data"},
+
+	TokenMissing:       {ID: "Token tidak ditemukan", EN: "Missing token"},
+	TokenInvalidFormat: {ID: "Format token tidak valid", EN: "Invalid token format"},
+	TokenInvalid:       {ID: "Token tidak valid atau sudah kedaluwarsa", EN: "Invalid or expired token"},
+	TokenSaveFailed:    {ID: "Gagal menyimpan token", EN: "Failed to store token"},
+	TokenRevoked:       {ID: "Token berhasil dicabut", EN: "Token revoked successfully"},
+	TokenNoJti:         {ID: "Token tidak memiliki ID sehingga tidak bisa dicabut satu per satu, rotasi key untuk mencabutnya", EN: "Token has no ID and cannot be revoked individually, rotate the key instead"},
+
+	RegisterRequired:   {ID: "Name, Email, dan Password wajib diisi", EN: "Name, email and password are required"},
+	RegisterSuccess:    {ID: "Registrasi berhasil", EN: "Registration successful"},
+	LoginInvalid:       {ID: "Email atau password salah", EN: "Incorrect email or password"},
+	LoginSuccess:       {ID: "Login berhasil", EN: "Login successful"},
+	LogoutFailed:       {ID: "Gagal logout", EN: "Logout failed"},
+	LogoutSuccess:      {ID: "Logout berhasil", EN: "Logout successful"},
+	LanguageUnknown:    {ID: "Bahasa tidak didukung, pilih id atau en", EN: "Unsupported language, choose id or en"},
+	LanguageUpdated:    {ID: "Bahasa berhasil diubah", EN: "Language updated"},
+	UserNotFound:       {ID: "User tidak ditemukan", EN: "User not found"},
+	UserInvalidID:      {ID: "Format ID user tidak valid", EN: "Invalid user ID format"},
+	UserIDRequired:     {ID: "ID user wajib diisi", EN: "User ID is required"},
+	UserEmailExists:    {ID: "Email sudah terdaftar", EN: "Email already exists"},
+	UserNameEmailEmpty: {ID: "Nama dan email tidak boleh kosong", EN: "Name and email cannot be empty"},
+	UserUpdated:        {ID: "Data user berhasil diubah", EN: "User updated successfully"},
+	UserUpdateFailed:   {ID: "Gagal mengubah data user", EN: "Failed to update user"},
+	UserDeleted:        {ID: "User berhasil dihapus", EN: "User deleted successfully"},
+	UserRestored:       {ID: "User berhasil dipulihkan", EN: "User restored successfully"},
+	UserSuspended:      {ID: "User berhasil ditangguhkan", EN: "User suspended successfully"},
+	UserUnsuspended:    {ID: "Penangguhan user dicabut", EN: "User unsuspended successfully"},
+	UserSelfAction:     {ID: "Admin tidak bisa menghapus atau menangguhkan akunnya sendiri", EN: "Admins cannot delete or suspend their own account"},
+	AccountSuspended:   {ID: "Akun Anda ditangguhkan, hubungi admin", EN: "Your account is suspended, please contact an admin"},
+	PasswordMismatch:   {ID: "Password salah", EN: "Incorrect password"},
+	ExportFailed:       {ID: "Gagal menyiapkan ekspor data", EN: "Failed to prepare data export"},
+	ExportRateLimited:  {ID: "Terlalu sering mengekspor data, coba lagi nanti", EN: "Too many exports, please try again later"},
+	DeletionScheduled:  {ID: "Akun akan dihapus permanen pada {date}. Login dan batalkan sebelum tanggal itu jika berubah pikiran", EN: "Your account will be permanently deleted on {date}. Log in and cancel before then if you change your mind"},
+	DeletionCancelled:  {ID: "Penghapusan akun dibatalkan", EN: "Account deletion cancelled"},
+	DeletionNotFound:   {ID: "Tidak ada permintaan hapus akun", EN: "No pending account deletion"},
+	AccountPurgeDone:   {ID: "Penghapusan akun selesai", EN: "Account purge finished"},
+	AccountPurgeDryRun: {ID: "Laporan penghapusan akun (dry run)", EN: "Account purge report (dry run)"},
+	PaymentMinimum:     {ID: "Minimal donasi adalah Rp1", EN: "Minimum donation is Rp1"},
+	PaymentSuccess:     {ID: "Pembayaran telah dilakukan, terima kasih!", EN: "Payment received, thank you!"},
+	InvoiceFetchFailed: {ID: "Gagal mengambil invoice", EN: "Oops! We couldn't fetch the invoices."},
+	PhotoRequired:      {ID: "Foto profil wajib diisi", EN: "Profile photo is required"},
+	PhotoUpdated:       {ID: "Foto profil berhasil diubah", EN: "Profile photo updated successfully"},
+	PhotoInvalid:       {ID: "Foto profil tidak valid", EN: "Invalid profile photo"},
+	PhotoTooLarge:      {ID: "Ukuran foto profil terlalu besar", EN: "Profile photo is too large"},
+	PhotoNotFound:      {ID: "Foto profil belum diunggah", EN: "No profile photo uploaded"},
+	PhotoSizeInvalid:   {ID: "Ukuran avatar tidak tersedia", EN: "Avatar size not available"},
+
+	NotificationReadParam:   {ID: "Parameter read harus true atau false", EN: "The read parameter must be true or false"},
+	NotificationRequired:    {ID: "Tipe dan pesan notifikasi wajib diisi", EN: "Type and message are required"},
+	NotificationCodeUnknown: {ID: "Kode pesan tidak dikenal: {code}", EN: "Unknown message code: {code}"},
+	NotificationNotFound:    {ID: "Notifikasi tidak ditemukan", EN: "Notification not found"},
+	PrefsChannelUnknown:     {ID: "Channel tidak dikenal untuk tipe {type}: {channel}", EN: "Unknown channel for type {type}: {channel}"},
+	PrefsWhatsAppRequired:   {ID: "whatsapp_number wajib diisi untuk channel whatsapp", EN: "whatsapp_number is required for the whatsapp channel"},
+	PrefsWhatsAppInvalid:    {ID: "whatsapp_number hanya boleh berisi angka", EN: "whatsapp_number may only contain digits"},
+	PrefsTimezoneInvalid:    {ID: "Timezone tidak dikenal", EN: "Unknown timezone"},
+	PrefsQuietHoursInvalid:  {ID: "{field} harus berformat HH:MM", EN: "{field} must use the HH:MM format"},
+	PrefsDigestHourInvalid:  {ID: "digest.hour harus 0-23", EN: "digest.hour must be 0-23"},
+	DispatchQueueFailed:     {ID: "Gagal membaca antrean notifikasi", EN: "Failed to read the notification queue"},
+	HistoryTypeInvalid:      {ID: "Tipe riwayat tidak dikenal", EN: "Invalid history type"},
+	HistorySortInvalid:      {ID: "Parameter sort tidak dikenal", EN: "Unknown sort parameter"},
+	HistoryNotFound:         {ID: "Riwayat tidak ditemukan atau bukan milik Anda", EN: "History item not found or not authorized"},
+	HistoryDeleted:          {ID: "Riwayat berhasil dihapus", EN: "History deleted successfully"},
+	FileInputInvalid:        {ID: "File input tidak valid: {id}", EN: "Invalid input file: {id}"},
+	ExportFormatInvalid:     {ID: "Format harus csv, json atau pdf", EN: "Format must be csv, json or pdf"},
+	RerunUnsupported:        {ID: "Operasi {type} tidak bisa dijalankan ulang", EN: "{type} operations cannot be re-run"},
+	RerunInputsMissing:      {ID: "inputs expired: file input tidak disimpan, unggah ulang file untuk menjalankan operasi ini", EN: "inputs expired: the input files were not stored, upload them again to run this operation"},
+	RerunInputsExpired:      {ID: "inputs expired: file input sudah melewati masa retensi, unggah ulang file untuk menjalankan operasi ini", EN: "inputs expired: the input files are past their retention period, upload them again to run this operation"},
+	RerunInputReadFailed:    {ID: "Gagal membaca file input", EN: "Failed to read input files"},
+	RerunDetailsInvalid:     {ID: "Detail aktivitas tidak valid", EN: "Invalid activity details"},
+	RerunFailed:             {ID: "Gagal menjalankan ulang", EN: "Re-run failed"},
+	StatsFailed:             {ID: "Gagal menghitung statistik", EN: "Failed to compute statistics"},
+	StreamUnsupported:       {ID: "Streaming tidak didukung", EN: "Streaming is not supported"},
+	FileRequired:            {ID: "Field 'file' wajib diisi", EN: "The 'file' field is required"},
+	FileTooLarge:            {ID: "Ukuran file melebihi batas {max} MB", EN: "File exceeds the {max} MB limit"},
+	FileReadFailed:          {ID: "Gagal membaca file", EN: "Failed to read the file"},
+	FilePDFOnly:             {ID: "Hanya file PDF yang diizinkan", EN: "Only PDF files are allowed"},
+	FileUploaded:            {ID: "File berhasil diunggah", EN: "File uploaded successfully"},
+	FileExpired:             {ID: "File sudah melewati masa retensi", EN: "The file is past its retention period"},
+	FileNotFound:            {ID: "File tidak ditemukan", EN: "File not found"},
+
+	AnnouncementRequired:        {ID: "title dan body wajib diisi", EN: "title and body are required"},
+	AnnouncementAudienceInvalid: {ID: "audience harus all, supporters atau segment", EN: "audience must be all, supporters or segment"},
+	AnnouncementSegmentEmpty:    {ID: "segment minimal berisi satu kriteria", EN: "segment needs at least one criterion"},
+	AnnouncementLinkInvalid:     {ID: "link harus https:// atau path relatif", EN: "link must be https:// or a relative path"},
+	AnnouncementExpiryInvalid:   {ID: "expires_at harus setelah publish_at", EN: "expires_at must be after publish_at"},
+	AnnouncementNotFound:        {ID: "Pengumuman tidak ditemukan", EN: "Announcement not found"},
+	AnnouncementScheduled:       {ID: "Pengumuman dijadwalkan", EN: "Announcement scheduled"},
+	AnnouncementPublished:       {ID: "Pengumuman diterbitkan", EN: "Announcement published"},
+	AnnouncementDeferred:        {ID: "Pengumuman tersimpan, penyebaran dilanjutkan oleh job terjadwal", EN: "Announcement saved, delivery will continue in the scheduled job"},
+	AnnouncementWithdrawn:       {ID: "Pengumuman ditarik", EN: "Announcement withdrawn"},
+	RetentionFailed:             {ID: "Gagal menjalankan retensi", EN: "Failed to run retention"},
+	RetentionPurged:             {ID: "Purge retensi selesai", EN: "Retention purge finished"},
+	RetentionDryRun:             {ID: "Laporan retensi (dry run)", EN: "Retention report (dry run)"},
+
+	FeedbackMessageRequired:   {ID: "Pesan tidak boleh kosong", EN: "Message cannot be empty"},
+	FeedbackCategoryInvalid:   {ID: "Kategori harus bug, feature, billing atau other", EN: "Category must be bug, feature, billing or other"},
+	FeedbackStatusInvalid:     {ID: "Status harus new, in_progress, resolved atau closed", EN: "Status must be new, in_progress, resolved or closed"},
+	FeedbackPriorityInvalid:   {ID: "Prioritas harus low, normal, high atau urgent", EN: "Priority must be low, normal, high or urgent"},
+	FeedbackAssigneeInvalid:   {ID: "assigned_to harus ID admin, me atau none", EN: "assigned_to must be an admin ID, me or none"},
+	FeedbackAssigneeNotFound:  {ID: "Admin yang ditugaskan tidak ditemukan", EN: "Assigned admin not found"},
+	FeedbackNotFound:          {ID: "Feedback tidak ditemukan", EN: "Feedback not found"},
+	FeedbackTransitionInvalid: {ID: "Perubahan status tiket tidak diizinkan", EN: "Ticket status change not allowed"},
+	FeedbackReplyRequired:     {ID: "Pesan balasan tidak boleh kosong", EN: "Reply message cannot be empty"},
+
+	FeedbackAttachmentsTooLarge:  {ID: "Total ukuran lampiran terlalu besar", EN: "Attachments are too large in total"},
+	FeedbackAttachmentLimit:      {ID: "Maksimal {max} lampiran", EN: "At most {max} attachments"},
+	FeedbackAttachmentTooLarge:   {ID: "Lampiran {name} melebihi batas {max} MB", EN: "Attachment {name} exceeds the {max} MB limit"},
+	FeedbackAttachmentReadFailed: {ID: "Gagal membaca lampiran {name}", EN: "Failed to read attachment {name}"},
+	FeedbackAttachmentType:       {ID: "Lampiran {name} kosong atau bukan PDF, PNG atau JPEG", EN: "Attachment {name} is empty or not a PDF, PNG or JPEG"},
+	FeedbackAttachmentSaveFailed: {ID: "Gagal menyimpan lampiran {name}", EN: "Failed to store attachment {name}"},
+	FeedbackAttachmentNotFound:   {ID: "Lampiran tidak ditemukan", EN: "Attachment not found"},
+
+	FeedbackSentimentInvalid: {ID: "sentiment harus positive, neutral atau negative", EN: "sentiment must be positive, neutral or negative"},
+	FeedbackLanguageInvalid:  {ID: "language harus id atau en", EN: "language must be id or en"},
+	FeedbackClusterInvalid:   {ID: "cluster harus ID cluster", EN: "cluster must be a cluster ID"},
+
+	FeedbackThanks:           {ID: "Terima kasih atas masukan Anda!", EN: "Thank you for your feedback!"},
+	FeedbackAnonymousInvalid: {ID: "anonymous harus true atau false", EN: "anonymous must be true or false"},
+	ContactRateLimited:       {ID: "Terlalu banyak pesan, coba lagi nanti", EN: "Too many messages, please try again later"},
+	ContactRequired:          {ID: "Nama dan pesan tidak boleh kosong", EN: "Name and message cannot be empty"},
+	ContactEmailInvalid:      {ID: "Email tidak valid", EN: "Invalid email address"},
+	ContactMessageTooLong:    {ID: "Pesan terlalu panjang (maks. {max} karakter)", EN: "Message is too long (max {max} characters)"},
+	ContactSpam:              {ID: "Pesan terdeteksi sebagai spam. Kurangi link atau ubah isi pesan lalu coba lagi", EN: "Message was flagged as spam. Remove some links or reword it and try again"},
+	CaptchaInvalid:           {ID: "Captcha tidak valid", EN: "Invalid captcha"},
+	CaptchaUnavailable:       {ID: "Gagal memverifikasi captcha", EN: "Captcha verification failed, please try again"},
+
+	APIKeyInvalid:      {ID: "API key tidak valid, sudah dicabut atau kedaluwarsa", EN: "API key is invalid, revoked or expired"},
+	APIKeyEndpoint:     {ID: "Endpoint ini tidak bisa diakses dengan API key", EN: "This endpoint cannot be accessed with an API key"},
+	APIKeyScope:        {ID: "API key tidak memiliki scope {scope}", EN: "API key is missing the {scope} scope"},
+	APIKeyRateLimited:  {ID: "Batas request API key terlampaui, coba lagi nanti", EN: "API key rate limit exceeded, please try again later"},
+	APIKeyCreated:      {ID: "API key berhasil dibuat. Simpan sekarang, key tidak akan ditampilkan lagi", EN: "API key created. Store it now, it will not be shown again"},
+	APIKeyRevoked:      {ID: "API key berhasil dicabut", EN: "API key revoked successfully"},
+	APIKeyNotFound:     {ID: "API key tidak ditemukan", EN: "API key not found"},
+	APIKeyLimit:        {ID: "Maksimal {max} API key aktif per akun", EN: "You can have at most {max} active API keys"},
+	APIKeyNameRequired: {ID: "Nama API key wajib diisi (maks. 100 karakter)", EN: "API key name is required (max 100 characters)"},
+
+	GoogleUnavailable:     {ID: "Login Google belum tersedia", EN: "Google sign-in is not available"},
+	GoogleTokenInvalid:    {ID: "Token Google tidak valid atau sudah kedaluwarsa", EN: "Google token is invalid or expired"},
+	GoogleEmailUnverified: {ID: "Email akun Google belum terverifikasi", EN: "Your Google account email is not verified"},
+	GoogleLinkRequired:    {ID: "Email ini sudah terdaftar. Masukkan password akun untuk menautkannya ke Google", EN: "This email is already registered. Enter the account password to link it to Google"},
+	GoogleAccountConflict: {ID: "Email ini sudah tertaut ke akun Google lain", EN: "This email is already linked to another Google account"},
+
+	NotifMerge:    {ID: "{count} file PDF berhasil digabungkan menjadi {file}", EN: "{count} PDF files merged into {file}"},
+	NotifCompress: {ID: "{file} berhasil dikompres", EN: "{file} compressed successfully"},
+	NotifConvert:  {ID: "{file} berhasil dikonversi ke {format}", EN: "{file} converted to {format}"},
+	NotifSummary:  {ID: "Ringkasan {file} sudah siap", EN: "Summary of {file} is ready"},
+	NotifRerun:    {ID: "Operasi {type} untuk {file} berhasil dijalankan ulang", EN: "{type} of {file} was re-run successfully"},
+
+	NotifFeedbackReply:   {ID: "{admin} membalas feedback Anda: {excerpt}", EN: "{admin} replied to your feedback: {excerpt}"},
+	NotifAccountDeletion: {ID: "Akun Anda dijadwalkan untuk dihapus permanen pada {date}", EN: "Your account is scheduled for permanent deletion on {date}"},
+}
@@ -0,0 +1,108 @@
+package i18n
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale yang didukung; Indonesia adalah default karena mayoritas pengguna pdfm
+const (
+	ID      = "id"
+	EN      = "en"
+	Default = ID
+)
+
+// Supported bernilai true jika locale ada di katalog
+func Supported(locale string) bool {
+	return locale == ID || locale == EN
+}
+
+// Normalize mengubah tag bahasa seperti "en-US" atau "id_ID" menjadi locale katalog ("" jika tidak didukung)
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if tag == "in" { // kode lama untuk bahasa Indonesia
+		tag = ID
+	}
+	if Supported(tag) {
+		return tag
+	}
+	return ""
+}
+
+// Negotiate memilih locale terbaik dari header Accept-Language berdasarkan bobot q
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+	var list []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		locale := Normalize(fields[0])
+		if locale == "" {
+			continue
+		}
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				if v, err := strconv.ParseFloat(f[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			list = append(list, candidate{locale, q})
+		}
+	}
+	if len(list) == 0 {
+		return Default
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].q > list[j].q })
+	return list[0].locale
+}
+
+// FromRequest menentukan locale request: query ?lang, lalu preferensi user yang tersimpan,
+// lalu Accept-Language, dan terakhir Default
+func FromRequest(r *http.Request, preferred string) string {
+	if l := Normalize(r.URL.Query().Get("lang")); l != "" {
+		return l
+	}
+	if l := Normalize(preferred); l != "" {
+		return l
+	}
+	return Negotiate(r.Header.Get("Accept-Language"))
+}
+
+// T mengembalikan teks pesan code dalam locale dengan parameter {nama} diganti dari pasangan args
+// (nama, nilai, nama, nilai, ...). Jika locale tidak punya terjemahan, dipakai Default; jika code tidak
+// dikenal, code itu sendiri dikembalikan.
+func T(locale, code string, args ...string) string {
+	texts, ok := catalog[code]
+	if !ok {
+		return code
+	}
+	text, ok := texts[locale]
+	if !ok {
+		text = texts[Default]
+	}
+	if len(args) < 2 {
+		return text
+	}
+	pairs := make([]string, 0, len(args))
+	for i := 0; i+1 < len(args); i += 2 {
+		pairs = append(pairs, "{"+args[i]+"}", args[i+1])
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// Has bernilai true jika code terdaftar di katalog
+func Has(code string) bool {
+	_, ok := catalog[code]
+	return ok
+}
@@ -0,0 +1,63 @@
+package i18n
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]string{
+		"":                          ID,
+		"en-US,en;q=0.9":            EN,
+		"fr-FR, en;q=0.5, id;q=0.8": ID,
+		"id-ID":                     ID,
+		"in":                        ID,
+		"de, fr":                    ID,
+		"en;q=0, id;q=0.1":          ID,
+	}
+	for header, want := range cases {
+		if got := Negotiate(header); got != want {
+			t.Errorf("Negotiate(%q) = %s, want %s", header, got, want)
+		}
+	}
+}
+
+func TestFromRequestPriority(t *testing.T) {
+	r := httptest.NewRequest("GET", "/pdfm/stats", nil)
+	r.Header.Set("Accept-Language", "en-GB")
+	if got := FromRequest(r, ""); got != EN {
+		t.Errorf("Accept-Language saja = %s", got)
+	}
+	if got := FromRequest(r, "id"); got != ID {
+		t.Errorf("preferensi user harus menang atas header, got %s", got)
+	}
+	r = httptest.NewRequest("GET", "/pdfm/stats?lang=en", nil)
+	if got := FromRequest(r, "id"); got != EN {
+		t.Errorf("query lang harus menang, got %s", got)
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T(ID, RegisterSuccess); got != "Registrasi berhasil" {
+		t.Errorf("T id = %q", got)
+	}
+	if got := T(EN, NotifMerge, "count", "3", "file", "laporan.pdf"); got != "3 PDF files merged into laporan.pdf" {
+		t.Errorf("T en template = %q", got)
+	}
+	if got := T("fr", LoginSuccess); got != "Login berhasil" {
+		t.Errorf("locale asing harus jatuh ke default, got %q", got)
+	}
+	if got := T(EN, "tidak_ada"); got != "tidak_ada" {
+		t.Errorf("code tidak dikenal = %q", got)
+	}
+}
+
+func TestCatalogComplete(t *testing.T) {
+	for code, texts := range catalog {
+		for _, l := range []string{ID, EN} {
+			if texts[l] == "" {
+				t.Errorf("%s tidak punya teks %s", code, l)
+			}
+		}
+	}
+}
@@ -76,3 +76,8 @@ func jaroWinkler(s1, s2 string) float64 {
 
 	return jaroDist + 0.1*float64(prefix)*(1-jaroDist)
 }
+
+// JaroWinkler mengembalikan kemiripan dua string (0 sampai 1) yang juga dipakai untuk mencocokkan pertanyaan FAQ
+func JaroWinkler(s1, s2 string) float64 {
+	return jaroWinkler(s1, s2)
+}
@@ -0,0 +1,113 @@
+// Package logging menyiapkan log JSON terstruktur dengan log/slog. Field mengikuti format yang
+// dikenali Cloud Logging (severity, message, time) dan request_id dari context ikut ditulis
+// otomatis jika log dibuat dengan slog.InfoContext dan sejenisnya.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// RequestIDHeader adalah header yang membawa ID request dari klien atau load balancer
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID menyimpan ID request di context
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID mengambil ID request dari context, kosong jika tidak ada
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID membuat ID request acak 32 karakter heksadesimal
+func NewRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ValidRequestID menerima ID dari header hanya jika pendek dan berisi karakter aman, agar header
+// dari klien tidak bisa menyisipkan isi log
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("-_.:/", c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseLevel mengubah nilai LOG_LEVEL (debug, info, warn, error) menjadi slog.Level, default info
+func ParseLevel(s string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// New membuat logger JSON ke w
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	h := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level, ReplaceAttr: cloudLoggingAttr})
+	return slog.New(contextHandler{h})
+}
+
+// Setup memasang logger JSON ke stdout sebagai logger default. Pemanggilan log.Printf yang lama
+// ikut diteruskan ke logger ini dengan level INFO.
+func Setup(level string) *slog.Logger {
+	logger := New(os.Stdout, ParseLevel(level))
+	slog.SetDefault(logger)
+	return logger
+}
+
+func cloudLoggingAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.MessageKey:
+		a.Key = "message"
+	case slog.LevelKey:
+		a.Key = "severity"
+		if level, ok := a.Value.Any().(slog.Level); ok && level >= slog.LevelWarn && level < slog.LevelError {
+			a.Value = slog.StringValue("WARNING")
+		}
+	}
+	return a
+}
+
+// contextHandler menambahkan request_id dari context ke setiap record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
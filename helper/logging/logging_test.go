@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestLoggerJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+	ctx := WithRequestID(context.Background(), "abc-123")
+	logger.WarnContext(ctx, "request lambat", "status", 200)
+	logger.Debug("tidak ditulis")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log bukan satu baris JSON: %q", buf.String())
+	}
+	if entry["message"] != "request lambat" || entry["severity"] != "WARNING" || entry["request_id"] != "abc-123" || entry["status"] != float64(200) {
+		t.Errorf("entry = %v", entry)
+	}
+}
+
+func TestValidRequestID(t *testing.T) {
+	for id, want := range map[string]bool{
+		NewRequestID():             true,
+		"projects/x/traces/1;o=1":  false,
+		"a\nb":                     false,
+		"":                         false,
+		"0f8c-4a1b_trace.1:span/2": true,
+	} {
+		if got := ValidRequestID(id); got != want {
+			t.Errorf("ValidRequestID(%q) = %v", id, got)
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	if ParseLevel("debug") != slog.LevelDebug || ParseLevel("WARN") != slog.LevelWarn || ParseLevel("") != slog.LevelInfo {
+		t.Error("ParseLevel tidak sesuai")
+	}
+}
@@ -2,7 +2,7 @@ package menu
 
 import (
 	"context"
-	"fmt"
+	"log/slog"
 	"strconv"
 	"time"
 
@@ -22,53 +22,40 @@ func MenuSessionHandler(msg *itmodel.IteungMessage, db *mongo.Database) string {
 	} else {
 		startmenu = "adminmenu"
 	}
-	fmt.Println("Startmenu determined:", startmenu) // Debugging startmenu
 
 	// check apakah ada session, klo ga ada insert session baru
 	Sesdoc, ses, err := CheckSession(msg.Phone_number, db)
 	if err != nil {
-		fmt.Println("Error checking session:", err) // Debug error check session
+		slog.Error("gagal memeriksa session menu", "phone", msg.Phone_number, "error", err)
 		return err.Error()
 	}
-	fmt.Println("Session exists:", ses, "Session document:", Sesdoc) // Debug session state and document
-	fmt.Println("Session Menu List:", Sesdoc.Menulist)
+	slog.Debug("session menu", "phone", msg.Phone_number, "startmenu", startmenu, "exists", ses, "menus", len(Sesdoc.Menulist))
 
 	if !ses { // jika tidak ada session atau session=false maka return menu utama user dan update session isi list nomor menunya
 		reply, err := GetMenuFromKeywordAndSetSession(startmenu, Sesdoc, db)
 		if err != nil {
-			fmt.Println("Error getting menu from keyword:", err) // Debug error in menu retrieval
 			return err.Error()
 		}
-		fmt.Println("Reply on new session:", reply) // Debug reply from the new session setup
 		return reply
 	}
 
 	// jika ada session maka cek menu
-	fmt.Println("Existing session found, checking menu") // Debug existing session found
-
 	// check apakah pesan integer
 	menuno, err := strconv.Atoi(msg.Message)
 	if err == nil { // kalo pesan adalah nomor
-		fmt.Println("Message is a valid number:", menuno) // Debug if message is a valid number
-		for _, menu := range Sesdoc.Menulist {            // looping di menu list dari session
-			fmt.Println("Checking menu number:", menu.No, "with keyword:", menu.Keyword) // Debug each menu item in session
-
+		for _, menu := range Sesdoc.Menulist { // looping di menu list dari session
 			if menuno == menu.No { // jika nomor menu sama dengan nomor yang ada di pesan
-				fmt.Println("Menu number matches:", menuno) // Debug when menu number matches
 				reply, err := GetMenuFromKeywordAndSetSession(menu.Keyword, Sesdoc, db)
 				if err != nil {
-					fmt.Println("Error getting menu for keyword:", menu.Keyword) // Debug error for keyword menu retrieval
+					slog.Debug("nomor menu diteruskan sebagai keyword", "keyword", menu.Keyword)
 					msg.Message = menu.Keyword
 					return ""
 				}
-				fmt.Println("Reply for matching menu:", reply) // Debug reply after matching menu
 				return reply
 			}
 		}
-		fmt.Println("Menu number not found:", menuno) // Debug if menu number not found
 		return "Mohon maaf nomor menu yang anda masukkan tidak ada di daftar menu"
 	}
-	fmt.Println("Message is not a valid number:", msg.Message) // Debug if message is not a number
 	// kalo pesan bukan nomor return kosong
 	return ""
 }
@@ -102,18 +89,16 @@ func GetMenuFromKeywordAndSetSession(keyword string, session Session, db *mongo.
 	// Ambil dokumen menu berdasarkan keyword
 	dt, err := atdb.GetOneDoc[Menu](db, "menu", bson.M{"keyword": keyword})
 	if err != nil {
-		fmt.Println("Error fetching menu from DB with keyword:", keyword, "Error:", err)
+		slog.Error("gagal mengambil menu", "keyword", keyword, "error", err)
 		return "", err
 	}
-	fmt.Println("Menu data fetched:", dt) // Debug untuk melihat data menu yang diambil
 
 	// Update session dengan list menu dari data yang diambil
-	result, err := atdb.UpdateOneDoc(db, "session", bson.M{"phonenumber": session.PhoneNumber}, bson.M{"list": dt.List})
+	_, err = atdb.UpdateOneDoc(db, "session", bson.M{"phonenumber": session.PhoneNumber}, bson.M{"list": dt.List})
 	if err != nil {
-		fmt.Println("Error updating session with menu list:", dt.List, "Error:", err)
+		slog.Error("gagal memperbarui menu di session", "phone", session.PhoneNumber, "error", err)
 		return "", err
 	}
-	fmt.Println("Session updated with menu list:", dt.List, "Result:", result) // Debug untuk memastikan session diupdate
 
 	// Bangun pesan yang akan dikirim ke pengguna
 	msg = dt.Header + "\n"
@@ -121,16 +106,13 @@ func GetMenuFromKeywordAndSetSession(keyword string, session Session, db *mongo.
 		msg += strconv.Itoa(item.No) + ". " + item.Konten + "\n"
 	}
 	msg += dt.Footer
-	fmt.Println("Generated message:", msg) // Debug pesan yang dihasilkan
 	return
 }
 
 func InjectSessionMenu(menulist []MenuList, phonenumber string, db *mongo.Database) error {
-	result, err := atdb.UpdateOneDoc(db, "session", bson.M{"phonenumber": phonenumber}, bson.M{"list": menulist})
+	_, err := atdb.UpdateOneDoc(db, "session", bson.M{"phonenumber": phonenumber}, bson.M{"list": menulist})
 	if err != nil {
-		fmt.Println("Error injecting session menu list:", menulist, "Error:", err)
-		return err
+		slog.Error("gagal menyisipkan menu ke session", "phone", phonenumber, "error", err)
 	}
-	fmt.Println("Session menu list injected successfully for phone number:", phonenumber, "Result:", result)
-	return nil
+	return err
 }
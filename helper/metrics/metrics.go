@@ -0,0 +1,62 @@
+// Package metrics berisi metrik Prometheus aplikasi pdfm (lihat pdfm.go) yang didaftarkan ke Registry
+// milik paket ini, ditambah metrik runtime Go dan proses. Handler mengekspos Registry untuk di-scrape.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry menyimpan semua metrik pdfm. Registry terpisah dari prometheus.DefaultRegisterer agar
+// library lain tidak ikut menambah metrik ke endpoint /metrics.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+}
+
+// Handler mengembalikan handler HTTP untuk endpoint scrape
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// Sample adalah satu nilai gauge beserta nilai labelnya
+type Sample struct {
+	Labels []string
+	Value  float64
+}
+
+// gaugeFunc adalah gauge berlabel yang nilainya dihitung saat scrape
+type gaugeFunc struct {
+	desc    *prometheus.Desc
+	collect func() []Sample
+}
+
+func (g *gaugeFunc) Describe(ch chan<- *prometheus.Desc) {
+	ch <- g.desc
+}
+
+func (g *gaugeFunc) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range g.collect() {
+		// Sample dengan jumlah label yang salah dilewati agar scrape tidak gagal seluruhnya
+		m, err := prometheus.NewConstMetric(g.desc, prometheus.GaugeValue, s.Value, s.Labels...)
+		if err != nil {
+			continue
+		}
+		ch <- m
+	}
+}
+
+// NewGaugeFunc mendaftarkan gauge berlabel, mis. panjang antrean di database. collect dipanggil
+// setiap kali endpoint metrik dibaca.
+func NewGaugeFunc(name, help string, labels []string, collect func() []Sample) prometheus.Collector {
+	g := &gaugeFunc{desc: prometheus.NewDesc(name, help, labels, nil), collect: collect}
+	Registry.MustRegister(g)
+	return g
+}
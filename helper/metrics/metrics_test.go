@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestGaugeFuncAndHandler(t *testing.T) {
+	NewGaugeFunc("test_queue_length", "Panjang antrean uji", []string{"queue"}, func() []Sample {
+		return []Sample{
+			{Labels: []string{"email"}, Value: 4},
+			{Labels: []string{"terlalu", "banyak"}, Value: 9},
+		}
+	})
+	HTTPRequests.WithLabelValues("GET", "/handler-test", "200").Inc()
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`test_queue_length{queue="email"} 4`,
+		`pdfm_http_requests_total{method="GET",route="/handler-test",status="200"} 1`,
+		"go_goroutines",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("output tidak memuat %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "terlalu") {
+		t.Error("sample dengan jumlah label salah harus dilewati")
+	}
+}
+
+func TestCommandCollection(t *testing.T) {
+	cases := []struct {
+		name string
+		cmd  bson.D
+		want string
+	}{
+		{"find", bson.D{{Key: "find", Value: "users"}, {Key: "filter", Value: bson.D{}}}, "users"},
+		{"getMore", bson.D{{Key: "getMore", Value: int64(7)}, {Key: "collection", Value: "history"}}, "history"},
+		{"ping", bson.D{{Key: "ping", Value: 1}}, "none"},
+	}
+	for _, c := range cases {
+		raw, _ := bson.Marshal(c.cmd)
+		if got := commandCollection(c.name, raw); got != c.want {
+			t.Errorf("commandCollection(%s) = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestMongoMonitor(t *testing.T) {
+	m := MongoMonitor()
+	ctx := context.Background()
+	find, _ := bson.Marshal(bson.D{{Key: "find", Value: "monitor_test"}})
+	update, _ := bson.Marshal(bson.D{{Key: "update", Value: "monitor_test"}})
+
+	m.Started(ctx, &event.CommandStartedEvent{Command: find, CommandName: "find", RequestID: 1})
+	m.Started(ctx, &event.CommandStartedEvent{Command: update, CommandName: "update", RequestID: 2})
+	m.Succeeded(ctx, &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", RequestID: 1, Duration: time.Millisecond},
+	})
+	m.Failed(ctx, &event.CommandFailedEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "update", RequestID: 2, Duration: time.Millisecond},
+	})
+
+	if got := testutil.ToFloat64(MongoErrors.WithLabelValues("update", "monitor_test")); got != 1 {
+		t.Errorf("MongoErrors update = %v", got)
+	}
+	if got := testutil.ToFloat64(MongoErrors.WithLabelValues("find", "monitor_test")); got != 0 {
+		t.Errorf("MongoErrors find = %v", got)
+	}
+	if n := testutil.CollectAndCount(MongoDuration, "pdfm_mongo_operation_duration_seconds"); n != 2 {
+		t.Errorf("seri MongoDuration = %d", n)
+	}
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// MongoMonitor mengembalikan monitor perintah untuk client MongoDB. Setiap perintah yang dikirim
+// driver (termasuk getMore dan perintah dari kode yang tidak lewat helper atdb) dicatat ke
+// MongoDuration, dan perintah yang gagal ke MongoErrors. Dokumen tidak ditemukan bukan kegagalan.
+func MongoMonitor() *event.CommandMonitor {
+	// Event selesai tidak membawa nama koleksi, jadi disimpan dari event mulai per request ID
+	var pending sync.Map
+	collection := func(requestID int64) string {
+		if v, ok := pending.LoadAndDelete(requestID); ok {
+			return v.(string)
+		}
+		return "none"
+	}
+	return &event.CommandMonitor{
+		Started: func(_ context.Context, e *event.CommandStartedEvent) {
+			pending.Store(e.RequestID, commandCollection(e.CommandName, e.Command))
+		},
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			MongoDuration.WithLabelValues(e.CommandName, collection(e.RequestID)).Observe(e.Duration.Seconds())
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			coll := collection(e.RequestID)
+			MongoDuration.WithLabelValues(e.CommandName, coll).Observe(e.Duration.Seconds())
+			MongoErrors.WithLabelValues(e.CommandName, coll).Inc()
+		},
+	}
+}
+
+// commandCollection mengambil nama koleksi dari perintah: nilai elemen pertama (mis. {find: "users"})
+// atau field collection untuk getMore. Perintah tanpa koleksi diberi label "none".
+func commandCollection(name string, cmd bson.Raw) string {
+	if name == "getMore" {
+		if v, ok := cmd.Lookup("collection").StringValueOK(); ok {
+			return v
+		}
+		return "none"
+	}
+	elems, err := cmd.Elements()
+	if err != nil || len(elems) == 0 {
+		return "none"
+	}
+	if v, ok := elems[0].Value().StringValueOK(); ok {
+		return v
+	}
+	return "none"
+}
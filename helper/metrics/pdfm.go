@@ -0,0 +1,57 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrik aplikasi pdfm. Didefinisikan di sini agar paket lain (config, atapi, activity, router) bisa
+// mencatat tanpa saling import. Label route memakai pola route (mis. /pdfm/history/:id), bukan path
+// mentah, agar jumlah seri tetap kecil.
+var (
+	HTTPRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "pdfm_http_requests_total",
+		Help: "Jumlah request HTTP per method, pola route dan status",
+	}, []string{"method", "route", "status"})
+	HTTPDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pdfm_http_request_duration_seconds",
+		Help:    "Latensi request HTTP per method dan pola route",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	PDFOperations = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "pdfm_pdf_operations_total",
+		Help: "Jumlah operasi PDF di server per tool dan hasil (success/failure)",
+	}, []string{"tool", "result"})
+	PDFDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pdfm_pdf_operation_duration_seconds",
+		Help:    "Durasi operasi PDF per tool",
+		Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"tool"})
+	PDFBytesIn = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "pdfm_pdf_bytes_in_total",
+		Help: "Total byte file input operasi PDF per tool",
+	}, []string{"tool"})
+	PDFBytesOut = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "pdfm_pdf_bytes_out_total",
+		Help: "Total byte file hasil operasi PDF per tool",
+	}, []string{"tool"})
+
+	WhatsAppMessages = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "pdfm_whatsapp_messages_total",
+		Help: "Pesan WhatsApp keluar per nomor profile dan hasil (success/failure)",
+	}, []string{"profile", "result"})
+
+	MongoDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pdfm_mongo_operation_duration_seconds",
+		Help:    "Latensi perintah MongoDB di level driver per perintah dan koleksi",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "collection"})
+	MongoErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "pdfm_mongo_operation_errors_total",
+		Help: "Perintah MongoDB yang gagal di level driver per perintah dan koleksi",
+	}, []string{"operation", "collection"})
+)
+
+// Hasil operasi untuk label result
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
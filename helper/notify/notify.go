@@ -0,0 +1,206 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gocroot/model"
+)
+
+// Nama channel pengiriman notifikasi
+const (
+	ChannelInApp    = "in_app"
+	ChannelEmail    = "email"
+	ChannelWhatsApp = "whatsapp"
+)
+
+// Status pengiriman per channel
+const (
+	StatusSent     = "sent"
+	StatusQueued   = "queued"   // menunggu dikirim (segera, atau setelah quiet hours)
+	StatusDigest   = "digest"   // menunggu ringkasan harian
+	StatusRetrying = "retrying" // gagal, akan dicoba lagi pada NextAttempt
+	StatusSending  = "sending"  // sedang dikirim satu proses; boleh diklaim ulang setelah NextAttempt (SendingLease)
+	StatusFailed   = "failed"   // gagal setelah MaxAttempts
+	StatusSkipped  = "skipped"  // channel tidak bisa dipakai (mis. nomor WhatsApp kosong)
+)
+
+// SendingLease adalah lama delivery berstatus sending dianggap masih dikirim. Setelah itu delivery dianggap
+// macet (mis. proses berhenti di tengah pengiriman) dan dicoba lagi oleh dispatch berikutnya.
+const SendingLease = 5 * time.Minute
+
+// DefaultTimezone dipakai jika user belum mengatur zona waktu
+const DefaultTimezone = "Asia/Jakarta"
+
+var ErrNoAddress = errors.New("alamat tujuan untuk channel ini kosong")
+
+// Recipient adalah tujuan pengiriman notifikasi
+type Recipient struct {
+	Name  string
+	Email string
+	Phone string
+}
+
+// Message adalah isi notifikasi yang dikirim ke channel eksternal
+type Message struct {
+	Type    string
+	Subject string
+	Body    string
+}
+
+// Channel mengirim Message ke Recipient lewat satu media (email, WhatsApp, ...)
+type Channel interface {
+	Send(ctx context.Context, to Recipient, msg Message) error
+}
+
+// ChannelFunc mengubah fungsi biasa menjadi Channel
+type ChannelFunc func(ctx context.Context, to Recipient, msg Message) error
+
+func (f ChannelFunc) Send(ctx context.Context, to Recipient, msg Message) error {
+	return f(ctx, to, msg)
+}
+
+// Dispatcher mengirim notifikasi ke channel eksternal dengan retry exponential backoff
+type Dispatcher struct {
+	Channels    map[string]Channel
+	MaxAttempts int
+	Backoff     time.Duration // jeda sebelum percobaan kedua; berlipat dua tiap kegagalan
+}
+
+// Attempt mencoba satu kali pengiriman d dan memperbarui status, jumlah percobaan dan jadwal retry-nya
+func (disp *Dispatcher) Attempt(ctx context.Context, d *model.NotificationDelivery, to Recipient, msg Message, now time.Time) {
+	ch, ok := disp.Channels[d.Channel]
+	if !ok {
+		d.Status, d.LastError = StatusSkipped, "channel tidak terdaftar"
+		return
+	}
+	d.Attempts++
+	err := ch.Send(ctx, to, msg)
+	if err == nil {
+		d.Status, d.LastError, d.SentAt, d.NextAttempt = StatusSent, "", now, time.Time{}
+		return
+	}
+	d.LastError = err.Error()
+	if errors.Is(err, ErrNoAddress) {
+		d.Status, d.NextAttempt = StatusSkipped, time.Time{}
+		return
+	}
+	if d.Attempts >= disp.MaxAttempts {
+		d.Status, d.NextAttempt = StatusFailed, time.Time{}
+		return
+	}
+	d.Status = StatusRetrying
+	d.NextAttempt = now.Add(disp.Backoff << (d.Attempts - 1))
+}
+
+// Due bernilai true jika d perlu dicoba kirim pada waktu now
+func Due(d model.NotificationDelivery, now time.Time) bool {
+	return (d.Status == StatusQueued || d.Status == StatusRetrying || d.Status == StatusSending) && !now.Before(d.NextAttempt)
+}
+
+// Enabled bernilai true jika channel aktif untuk tipe notifikasi menurut preferensi user.
+// Tanpa preferensi, hanya in_app yang aktif.
+func Enabled(prefs model.NotificationPreferences, notifType, channel string) bool {
+	channels, ok := prefs.Channels[notifType]
+	if !ok {
+		channels, ok = prefs.Channels["*"]
+	}
+	if !ok {
+		return channel == ChannelInApp
+	}
+	for _, c := range channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// Plan menyusun status pengiriman awal sebuah notifikasi baru untuk setiap channel
+func Plan(prefs model.NotificationPreferences, notifType string, now time.Time) []model.NotificationDelivery {
+	inApp := model.NotificationDelivery{Channel: ChannelInApp, Status: StatusSkipped}
+	if Enabled(prefs, notifType, ChannelInApp) {
+		inApp.Status, inApp.SentAt = StatusSent, now
+	}
+	plan := []model.NotificationDelivery{inApp}
+
+	for _, ch := range []string{ChannelEmail, ChannelWhatsApp} {
+		if !Enabled(prefs, notifType, ch) {
+			continue
+		}
+		d := model.NotificationDelivery{Channel: ch, Status: StatusQueued, NextAttempt: now}
+		if prefs.Digest.Enabled {
+			d.Status, d.NextAttempt = StatusDigest, time.Time{}
+		} else if quiet, end := InQuietHours(prefs, now); quiet {
+			d.NextAttempt = end
+		}
+		plan = append(plan, d)
+	}
+	return plan
+}
+
+// Location mengembalikan zona waktu user (default Asia/Jakarta)
+func Location(prefs model.NotificationPreferences) *time.Location {
+	name := prefs.Timezone
+	if name == "" {
+		name = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.FixedZone("WIB", 7*3600)
+	}
+	return loc
+}
+
+// ParseClock membaca jam "HH:MM" menjadi menit sejak tengah malam
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// InQuietHours bernilai true jika now berada di quiet hours user, beserta waktu quiet hours berakhir
+func InQuietHours(prefs model.NotificationPreferences, now time.Time) (bool, time.Time) {
+	q := prefs.QuietHours
+	if !q.Enabled {
+		return false, time.Time{}
+	}
+	start, err1 := ParseClock(q.Start)
+	end, err2 := ParseClock(q.End)
+	if err1 != nil || err2 != nil || start == end {
+		return false, time.Time{}
+	}
+
+	local := now.In(Location(prefs))
+	minute := local.Hour()*60 + local.Minute()
+	var quiet bool
+	if start < end {
+		quiet = minute >= start && minute < end
+	} else { // melewati tengah malam, mis. 22:00-07:00
+		quiet = minute >= start || minute < end
+	}
+	if !quiet {
+		return false, time.Time{}
+	}
+
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	until := midnight.Add(time.Duration(end) * time.Minute)
+	if !until.After(local) {
+		until = until.AddDate(0, 0, 1)
+	}
+	return true, until
+}
+
+// DigestDue bernilai true jika ringkasan harian user perlu dikirim pada waktu now:
+// sudah lewat jam digest hari ini dan belum dikirim sejak jam tersebut
+func DigestDue(prefs model.NotificationPreferences, now time.Time) bool {
+	if !prefs.Digest.Enabled {
+		return false
+	}
+	local := now.In(Location(prefs))
+	slot := time.Date(local.Year(), local.Month(), local.Day(), prefs.Digest.Hour, 0, 0, 0, local.Location())
+	return !local.Before(slot) && prefs.Digest.LastSentAt.Before(slot)
+}
@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gocroot/model"
+)
+
+var wib = time.FixedZone("WIB", 7*3600)
+
+func TestEnabledDefaults(t *testing.T) {
+	var none model.NotificationPreferences
+	if !Enabled(none, "merge", ChannelInApp) || Enabled(none, "merge", ChannelEmail) {
+		t.Error("tanpa preferensi hanya in_app yang aktif")
+	}
+	prefs := model.NotificationPreferences{Channels: map[string][]string{
+		"*":       {ChannelInApp, ChannelEmail},
+		"summary": {ChannelWhatsApp},
+	}}
+	if !Enabled(prefs, "merge", ChannelEmail) {
+		t.Error("tipe tanpa entri harus memakai *")
+	}
+	if Enabled(prefs, "summary", ChannelInApp) || !Enabled(prefs, "summary", ChannelWhatsApp) {
+		t.Error("entri tipe harus menimpa *")
+	}
+}
+
+func TestQuietHoursOvernight(t *testing.T) {
+	prefs := model.NotificationPreferences{
+		Timezone:   "Asia/Jakarta",
+		QuietHours: model.QuietHours{Enabled: true, Start: "22:00", End: "07:00"},
+	}
+	night := time.Date(2024, 5, 1, 23, 30, 0, 0, wib)
+	quiet, until := InQuietHours(prefs, night)
+	if !quiet || !until.Equal(time.Date(2024, 5, 2, 7, 0, 0, 0, wib)) {
+		t.Errorf("23:30 quiet=%v until=%v", quiet, until)
+	}
+	early := time.Date(2024, 5, 2, 6, 0, 0, 0, wib)
+	quiet, until = InQuietHours(prefs, early)
+	if !quiet || !until.Equal(time.Date(2024, 5, 2, 7, 0, 0, 0, wib)) {
+		t.Errorf("06:00 quiet=%v until=%v", quiet, until)
+	}
+	if quiet, _ := InQuietHours(prefs, time.Date(2024, 5, 2, 12, 0, 0, 0, wib)); quiet {
+		t.Error("12:00 bukan quiet hours")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	now := time.Date(2024, 5, 1, 23, 0, 0, 0, wib)
+	prefs := model.NotificationPreferences{
+		Channels:   map[string][]string{"*": {ChannelInApp, ChannelEmail, ChannelWhatsApp}},
+		QuietHours: model.QuietHours{Enabled: true, Start: "22:00", End: "07:00"},
+	}
+	plan := Plan(prefs, "merge", now)
+	if len(plan) != 3 || plan[0].Status != StatusSent {
+		t.Fatalf("plan = %+v", plan)
+	}
+	for _, d := range plan[1:] {
+		if d.Status != StatusQueued || Due(d, now) || !Due(d, now.Add(8*time.Hour)) {
+			t.Errorf("delivery %s harus ditahan sampai quiet hours selesai: %+v", d.Channel, d)
+		}
+	}
+
+	prefs.Digest.Enabled = true
+	for _, d := range Plan(prefs, "merge", now)[1:] {
+		if d.Status != StatusDigest || Due(d, now) {
+			t.Errorf("digest aktif, status = %s", d.Status)
+		}
+	}
+}
+
+func TestAttemptRetry(t *testing.T) {
+	fail := errors.New("smtp down")
+	calls := 0
+	disp := &Dispatcher{
+		Channels: map[string]Channel{ChannelEmail: ChannelFunc(func(ctx context.Context, to Recipient, msg Message) error {
+			calls++
+			if calls < 3 {
+				return fail
+			}
+			return nil
+		})},
+		MaxAttempts: 3,
+		Backoff:     time.Minute,
+	}
+	now := time.Now()
+	d := model.NotificationDelivery{Channel: ChannelEmail, Status: StatusQueued}
+
+	disp.Attempt(context.Background(), &d, Recipient{}, Message{}, now)
+	if d.Status != StatusRetrying || !d.NextAttempt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("percobaan 1: %+v", d)
+	}
+	disp.Attempt(context.Background(), &d, Recipient{}, Message{}, now)
+	if d.Status != StatusRetrying || !d.NextAttempt.Equal(now.Add(2*time.Minute)) {
+		t.Fatalf("percobaan 2: %+v", d)
+	}
+	disp.Attempt(context.Background(), &d, Recipient{}, Message{}, now)
+	if d.Status != StatusSent || d.Attempts != 3 || d.LastError != "" {
+		t.Fatalf("percobaan 3: %+v", d)
+	}
+
+	calls = -10
+	d = model.NotificationDelivery{Channel: ChannelEmail}
+	for i := 0; i < 3; i++ {
+		disp.Attempt(context.Background(), &d, Recipient{}, Message{}, now)
+	}
+	if d.Status != StatusFailed {
+		t.Errorf("setelah MaxAttempts status = %s", d.Status)
+	}
+}
+
+func TestDigestDue(t *testing.T) {
+	prefs := model.NotificationPreferences{Digest: model.DigestSetting{Enabled: true, Hour: 8}}
+	morning := time.Date(2024, 5, 1, 8, 5, 0, 0, wib)
+	if DigestDue(prefs, morning.Add(-time.Hour)) {
+		t.Error("belum jam digest")
+	}
+	if !DigestDue(prefs, morning) {
+		t.Error("digest seharusnya jatuh tempo")
+	}
+	prefs.Digest.LastSentAt = morning
+	if DigestDue(prefs, morning.Add(time.Hour)) {
+		t.Error("digest hari ini sudah terkirim")
+	}
+	if !DigestDue(prefs, morning.AddDate(0, 0, 1)) {
+		t.Error("digest besok seharusnya jatuh tempo")
+	}
+}
+
+func TestDueSendingLease(t *testing.T) {
+	now := time.Now()
+	d := model.NotificationDelivery{Channel: ChannelEmail, Status: StatusSending, NextAttempt: now.Add(SendingLease)}
+	if Due(d, now) {
+		t.Error("delivery yang sedang dikirim tidak boleh dicoba lagi sebelum lease habis")
+	}
+	if !Due(d, now.Add(SendingLease)) {
+		t.Error("delivery sending yang macet harus dicoba lagi setelah lease habis")
+	}
+}
@@ -0,0 +1,66 @@
+package paging
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Cursor menyimpan posisi item terakhir yang sudah dikirim ke client.
+// Time dipakai untuk urutan berdasarkan waktu, Text untuk urutan berdasarkan nama,
+// dan ID sebagai pemecah seri ketika nilai urutan sama.
+type Cursor struct {
+	Time time.Time `json:"t,omitempty"`
+	Text string    `json:"s,omitempty"`
+	ID   string    `json:"id"`
+}
+
+var ErrInvalidCursor = errors.New("cursor tidak valid")
+
+// Encode mengubah cursor menjadi string opaque yang aman dipakai di query string
+func Encode(c Cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode membaca kembali cursor dari string hasil Encode
+func Decode(s string) (c Cursor, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err = json.Unmarshal(raw, &c); err != nil || c.ID == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// Limit membaca parameter limit, memakai def jika kosong/tidak valid dan membatasi ke max
+func Limit(raw string, def, max int) int {
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		return def
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
+
+// ParseDate menerima format tanggal (2006-01-02) atau RFC3339.
+// Untuk format tanggal, endOfDay=true mengembalikan akhir hari tersebut pada zona loc.
+func ParseDate(raw string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", raw, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
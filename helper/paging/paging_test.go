@@ -0,0 +1,49 @@
+package paging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	c := Cursor{Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), ID: "65b000000000000000000001"}
+	got, err := Decode(Encode(c))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !got.Time.Equal(c.Time) || got.ID != c.ID {
+		t.Errorf("cursor berubah: %+v", got)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, s := range []string{"", "!!!", Encode(Cursor{})} {
+		if _, err := Decode(s); err != ErrInvalidCursor {
+			t.Errorf("Decode(%q) err = %v", s, err)
+		}
+	}
+}
+
+func TestLimit(t *testing.T) {
+	cases := map[string]int{"": 20, "abc": 20, "-1": 20, "5": 5, "500": 100}
+	for raw, want := range cases {
+		if got := Limit(raw, 20, 100); got != want {
+			t.Errorf("Limit(%q) = %d, want %d", raw, got, want)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	start, err := ParseDate("2025-03-01", loc, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	end, _ := ParseDate("2025-03-01", loc, true)
+	if end.Sub(start) != 24*time.Hour-time.Nanosecond {
+		t.Errorf("rentang hari salah: %v - %v", start, end)
+	}
+	if _, err := ParseDate("01/03/2025", loc, false); err == nil {
+		t.Error("format tidak dikenal seharusnya error")
+	}
+}
@@ -0,0 +1,47 @@
+package privacy
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Archive menulis ekspor data personal sebagai zip berisi file JSON
+type Archive struct {
+	zw  *zip.Writer
+	now time.Time
+}
+
+func NewArchive(w io.Writer, now time.Time) *Archive {
+	return &Archive{zw: zip.NewWriter(w), now: now}
+}
+
+// AddJSON menulis v sebagai JSON terindentasi ke file name di dalam zip
+func (a *Archive) AddJSON(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return a.Add(name, data)
+}
+
+// Add menulis data apa adanya ke file name di dalam zip
+func (a *Archive) Add(name string, data []byte) error {
+	f, err := a.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: a.now})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
+
+// Close menutup zip; wajib dipanggil agar central directory ikut tertulis
+func (a *Archive) Close() error {
+	return a.zw.Close()
+}
+
+// ExportFileName adalah nama file zip ekspor yang dikirim ke user
+func ExportFileName(now time.Time) string {
+	return "pdfm-data-" + now.Format("20060102-150405") + ".zip"
+}
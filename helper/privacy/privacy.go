@@ -0,0 +1,104 @@
+package privacy
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Action menentukan perlakuan data personal di satu koleksi saat akun dihapus
+type Action string
+
+const (
+	// Delete menghapus dokumen milik user
+	Delete Action = "delete"
+	// Anonymize mempertahankan dokumen (mis. invoice untuk pembukuan) tetapi mengganti data identitasnya
+	Anonymize Action = "anonymize"
+)
+
+// Key adalah cara koleksi mereferensikan user
+type Key string
+
+const (
+	ByID    Key = "id"    // primitive.ObjectID user
+	ByHexID Key = "hex"   // ID user dalam bentuk string hex
+	ByEmail Key = "email" // email user
+)
+
+// PseudonymPlaceholder di nilai Target.Set diganti dengan alamat samaran dari Pseudonym
+const PseudonymPlaceholder = "{pseudonym}"
+
+// DeletedName adalah nama pengganti untuk dokumen yang dianonimkan
+const DeletedName = "Pengguna terhapus"
+
+// ArrayElement adalah identifier array filter untuk Target dengan Array terisi, dipakai di path Set/Unset
+// seperti "replies.$[el].author_name" agar hanya elemen milik user yang diubah
+const ArrayElement = "el"
+
+// Target adalah satu koleksi yang menyimpan data personal user
+type Target struct {
+	Collection string
+	Field      string
+	Key        Key
+	Action     Action
+	// Set dan Unset hanya dipakai untuk Anonymize
+	Set   map[string]string
+	Unset []string
+	// Array diisi jika referensi user ada di elemen array (mis. "replies"). Field lalu relatif terhadap
+	// elemen, hanya Anonymize yang didukung, dan Set/Unset memakai "$[el]" (lihat ArrayElement).
+	Array string
+}
+
+// key mengembalikan nilai referensi user sesuai Key
+func (t Target) key(id primitive.ObjectID, email string) interface{} {
+	switch t.Key {
+	case ByHexID:
+		return id.Hex()
+	case ByEmail:
+		return email
+	default:
+		return id
+	}
+}
+
+// Filter mengembalikan filter dokumen milik user di koleksi target
+func (t Target) Filter(id primitive.ObjectID, email string) bson.M {
+	if t.Array != "" {
+		return bson.M{t.Array + "." + t.Field: t.key(id, email)}
+	}
+	return bson.M{t.Field: t.key(id, email)}
+}
+
+// ArrayFilters mengembalikan array filter untuk update Target dengan Array terisi, atau nil
+func (t Target) ArrayFilters(id primitive.ObjectID, email string) []interface{} {
+	if t.Array == "" {
+		return nil
+	}
+	return []interface{}{bson.M{ArrayElement + "." + t.Field: t.key(id, email)}}
+}
+
+// Update mengembalikan update anonimisasi; pseudonym menggantikan PseudonymPlaceholder di nilai Set
+func (t Target) Update(pseudonym string) bson.M {
+	update := bson.M{}
+	if len(t.Set) > 0 {
+		set := bson.M{}
+		for field, value := range t.Set {
+			set[field] = strings.ReplaceAll(value, PseudonymPlaceholder, pseudonym)
+		}
+		update["$set"] = set
+	}
+	if len(t.Unset) > 0 {
+		unset := bson.M{}
+		for _, field := range t.Unset {
+			unset[field] = ""
+		}
+		update["$unset"] = unset
+	}
+	return update
+}
+
+// Pseudonym adalah alamat email samaran yang stabil untuk satu user; domain .invalid tidak pernah bisa dikirimi email
+func Pseudonym(id primitive.ObjectID) string {
+	return "deleted-" + id.Hex() + "@deleted.invalid"
+}
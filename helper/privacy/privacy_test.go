@@ -0,0 +1,103 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTargetFilter(t *testing.T) {
+	id := primitive.NewObjectID()
+	cases := []struct {
+		target Target
+		want   interface{}
+	}{
+		{Target{Field: "user_id", Key: ByID}, id},
+		{Target{Field: "user_id", Key: ByHexID}, id.Hex()},
+		{Target{Field: "email", Key: ByEmail}, "a@b.id"},
+	}
+	for _, c := range cases {
+		got := c.target.Filter(id, "a@b.id")[c.target.Field]
+		if got != c.want {
+			t.Errorf("Filter(%s) = %v, want %v", c.target.Key, got, c.want)
+		}
+	}
+}
+
+func TestTargetArray(t *testing.T) {
+	id := primitive.NewObjectID()
+	target := Target{
+		Array: "replies", Field: "author_id", Key: ByID, Action: Anonymize,
+		Set: map[string]string{"replies.$[el].author_name": DeletedName},
+	}
+	if got := target.Filter(id, "")["replies.author_id"]; got != id {
+		t.Errorf("Filter = %v", target.Filter(id, ""))
+	}
+	filters := target.ArrayFilters(id, "")
+	if len(filters) != 1 || filters[0].(bson.M)["el.author_id"] != id {
+		t.Errorf("ArrayFilters = %v", filters)
+	}
+	if set := target.Update("x")["$set"].(bson.M); set["replies.$[el].author_name"] != DeletedName {
+		t.Errorf("$set = %v", set)
+	}
+	if (Target{Field: "user_id"}).ArrayFilters(id, "") != nil {
+		t.Error("target tanpa Array tidak butuh array filter")
+	}
+}
+
+func TestTargetUpdate(t *testing.T) {
+	id := primitive.NewObjectID()
+	target := Target{
+		Set:   map[string]string{"email": PseudonymPlaceholder, "name": DeletedName},
+		Unset: []string{"user_id"},
+	}
+	update := target.Update(Pseudonym(id))
+	set := update["$set"].(bson.M)
+	if set["email"] != "deleted-"+id.Hex()+"@deleted.invalid" || set["name"] != DeletedName {
+		t.Errorf("$set = %v", set)
+	}
+	if _, ok := update["$unset"].(bson.M)["user_id"]; !ok {
+		t.Errorf("$unset = %v", update["$unset"])
+	}
+	if len((Target{}).Update("x")) != 0 {
+		t.Error("target tanpa Set/Unset harus menghasilkan update kosong")
+	}
+}
+
+func TestArchive(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	a := NewArchive(&buf, now)
+	if err := a.AddJSON("profile.json", map[string]string{"name": "Budi"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Add("README.txt", []byte("halo")); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zr.File) != 2 || zr.File[0].Name != "profile.json" {
+		t.Fatalf("isi zip = %v", zr.File)
+	}
+	f, _ := zr.File[0].Open()
+	data, _ := io.ReadAll(f)
+	var profile map[string]string
+	if err := json.Unmarshal(data, &profile); err != nil || profile["name"] != "Budi" {
+		t.Errorf("profile.json = %s (%v)", data, err)
+	}
+	if got := ExportFileName(now); got != "pdfm-data-20260102-030405.zip" {
+		t.Errorf("ExportFileName = %q", got)
+	}
+}
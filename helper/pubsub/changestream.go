@@ -0,0 +1,71 @@
+package pubsub
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DecodeFunc mengubah dokumen yang baru di-insert menjadi topic dan Message.
+// ok bernilai false jika dokumen tidak perlu dikirim.
+type DecodeFunc func(doc bson.Raw) (topic string, msg Message, ok bool)
+
+// ChangeStream adalah Broker untuk banyak instance: setiap instance menonton insert pada
+// satu koleksi Mongo (butuh replica set / Atlas) dan meneruskannya ke subscriber lokal.
+// Publish tidak mengirim apa pun karena dokumen yang di-insert sudah menjadi event-nya.
+type ChangeStream struct {
+	local  *Memory
+	coll   *mongo.Collection
+	decode DecodeFunc
+	once   sync.Once
+}
+
+func NewChangeStream(coll *mongo.Collection, decode DecodeFunc) *ChangeStream {
+	return &ChangeStream{local: NewMemory(), coll: coll, decode: decode}
+}
+
+func (c *ChangeStream) Publish(topic string, msg Message) {}
+
+// Subscribe menjalankan watcher pada pemanggilan pertama
+func (c *ChangeStream) Subscribe(topic string) (<-chan Message, func()) {
+	c.once.Do(func() { go c.watch(context.Background()) })
+	return c.local.Subscribe(topic)
+}
+
+// watch membaca change stream terus-menerus dan menyambung ulang dari resume token jika terputus
+func (c *ChangeStream) watch(ctx context.Context) {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"operationType": "insert"}}}}
+	var resume bson.Raw
+	for ctx.Err() == nil {
+		opts := options.ChangeStream()
+		if resume != nil {
+			opts.SetResumeAfter(resume)
+		}
+		stream, err := c.coll.Watch(ctx, pipeline, opts)
+		if err != nil {
+			slog.ErrorContext(ctx, "pubsub: gagal membuka change stream", "error", err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
+		for stream.Next(ctx) {
+			resume = stream.ResumeToken()
+			doc, err := stream.Current.LookupErr("fullDocument")
+			if err != nil {
+				continue
+			}
+			if topic, msg, ok := c.decode(doc.Document()); ok {
+				c.local.Publish(topic, msg)
+			}
+		}
+		if err := stream.Err(); err != nil {
+			slog.WarnContext(ctx, "pubsub: change stream terputus", "error", err)
+		}
+		stream.Close(ctx)
+		time.Sleep(time.Second)
+	}
+}
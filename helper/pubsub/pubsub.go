@@ -0,0 +1,73 @@
+package pubsub
+
+import "sync"
+
+// Message adalah satu event yang dikirim ke subscriber sebuah topic
+type Message struct {
+	ID   string // ID event, dipakai sebagai SSE id / Last-Event-ID
+	Data []byte
+}
+
+// Broker mengirim Message ke semua subscriber topic yang sama.
+// Memory cukup untuk satu instance; ChangeStream dipakai jika aplikasi berjalan di beberapa instance.
+type Broker interface {
+	// Publish mengirim msg ke semua subscriber topic; tidak pernah memblok pengirim
+	Publish(topic string, msg Message)
+	// Subscribe mengembalikan channel event dan fungsi untuk berhenti berlangganan
+	Subscribe(topic string) (<-chan Message, func())
+}
+
+// SubscriberBuffer adalah kapasitas channel tiap subscriber.
+// Event untuk subscriber yang buffer-nya penuh dibuang; klien SSE mengejarnya lewat Last-Event-ID.
+const SubscriberBuffer = 16
+
+// Memory adalah Broker in-process
+type Memory struct {
+	mu   sync.RWMutex
+	subs map[string]map[chan Message]struct{}
+}
+
+func NewMemory() *Memory {
+	return &Memory{subs: map[string]map[chan Message]struct{}{}}
+}
+
+func (m *Memory) Publish(topic string, msg Message) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for ch := range m.subs[topic] {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+func (m *Memory) Subscribe(topic string) (<-chan Message, func()) {
+	ch := make(chan Message, SubscriberBuffer)
+	m.mu.Lock()
+	if m.subs[topic] == nil {
+		m.subs[topic] = map[chan Message]struct{}{}
+	}
+	m.subs[topic][ch] = struct{}{}
+	m.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			m.mu.Lock()
+			delete(m.subs[topic], ch)
+			if len(m.subs[topic]) == 0 {
+				delete(m.subs, topic)
+			}
+			m.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Subscribers mengembalikan jumlah subscriber aktif pada topic
+func (m *Memory) Subscribers(topic string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.subs[topic])
+}
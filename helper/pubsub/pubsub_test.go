@@ -0,0 +1,48 @@
+package pubsub
+
+import "testing"
+
+func TestMemoryPublishSubscribe(t *testing.T) {
+	m := NewMemory()
+	a, cancelA := m.Subscribe("user-1")
+	b, cancelB := m.Subscribe("user-1")
+	other, cancelOther := m.Subscribe("user-2")
+	defer cancelOther()
+
+	m.Publish("user-1", Message{ID: "1", Data: []byte("halo")})
+	for _, ch := range []<-chan Message{a, b} {
+		if msg := <-ch; msg.ID != "1" || string(msg.Data) != "halo" {
+			t.Errorf("pesan salah: %+v", msg)
+		}
+	}
+	select {
+	case msg := <-other:
+		t.Errorf("topic lain ikut menerima %+v", msg)
+	default:
+	}
+
+	cancelA()
+	cancelA() // aman dipanggil dua kali
+	if _, open := <-a; open {
+		t.Error("channel harus ditutup setelah cancel")
+	}
+	if n := m.Subscribers("user-1"); n != 1 {
+		t.Errorf("Subscribers = %d", n)
+	}
+	cancelB()
+	if n := m.Subscribers("user-1"); n != 0 {
+		t.Errorf("Subscribers setelah semua cancel = %d", n)
+	}
+}
+
+func TestMemorySlowSubscriberDoesNotBlock(t *testing.T) {
+	m := NewMemory()
+	ch, cancel := m.Subscribe("t")
+	defer cancel()
+	for i := 0; i < SubscriberBuffer*2; i++ {
+		m.Publish("t", Message{ID: "x"})
+	}
+	if len(ch) != SubscriberBuffer {
+		t.Errorf("buffer = %d, want %d", len(ch), SubscriberBuffer)
+	}
+}
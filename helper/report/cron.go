@@ -49,7 +49,7 @@ func RekapMeetingKemarin(db *mongo.Database) (err error) {
 				Filename:  project.Name + ".pdf",
 				Caption:   "Berikut ini rekap rapat kemaren ya kak untuk project " + project.Name,
 			}
-			_, _, err = atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken, dt, config.WAAPIDocMessage)
+			_, _, err = atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken(), dt, config.WAAPIDocMessage)
 			if err != nil {
 				continue
 			}
@@ -112,7 +112,7 @@ func RekapPagiHari(db *mongo.Database) (err error) {
 			Messages: msg,
 		}
 		var resp model.Response
-		_, resp, err = atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken, dt, config.WAAPIMessage)
+		_, resp, err = atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken(), dt, config.WAAPIMessage)
 		if err != nil {
 			lastErr = errors.New("Tidak berhak: " + err.Error() + ", " + resp.Info)
 			continue
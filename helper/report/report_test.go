@@ -7,6 +7,7 @@ import (
 
 	"github.com/gocroot/config"
 	"github.com/gocroot/helper/atdb"
+	"github.com/whatsauth/itmodel"
 )
 
 var mongoinfo = atdb.DBInfo{
@@ -17,7 +18,7 @@ var mongoinfo = atdb.DBInfo{
 var Mongoconn, ErrorMongoconn = atdb.MongoConnect(mongoinfo)
 
 func TestGenerateReport(t *testing.T) {
-	config.WAAPIToken = "v4.public."
+	config.SetProfile(itmodel.Profile{Token: "v4.public."})
 	fmt.Println(mongoinfo.DBString)
 	err := RekapMeetingKemarin(Mongoconn)
 	fmt.Println(err)
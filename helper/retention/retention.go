@@ -0,0 +1,58 @@
+package retention
+
+import "time"
+
+// Plan langganan user pdfm yang menentukan lama data disimpan
+const (
+	PlanFree      = "free"
+	PlanSupporter = "supporter"
+)
+
+// Policy adalah aturan retensi satu koleksi.
+// Jika UserField kosong, semua dokumen memakai masa retensi Free tanpa melihat plan user.
+type Policy struct {
+	Collection string
+	TimeField  string
+	UserField  string
+	Free       time.Duration
+	Supporter  time.Duration
+}
+
+// PerPlan bernilai true jika masa retensi koleksi ini berbeda antar plan
+func (p Policy) PerPlan() bool {
+	return p.UserField != "" && p.Free != p.Supporter
+}
+
+// Plans mengembalikan plan yang perlu diproses terpisah untuk policy ini
+func (p Policy) Plans() []string {
+	if p.PerPlan() {
+		return []string{PlanFree, PlanSupporter}
+	}
+	return []string{PlanFree}
+}
+
+// Cutoff adalah batas waktu: dokumen dengan TimeField sebelum nilai ini boleh dihapus.
+// Durasi nol atau negatif berarti koleksi tidak pernah dipurge (zero time dikembalikan).
+func (p Policy) Cutoff(now time.Time, plan string) time.Time {
+	d := p.Free
+	if plan == PlanSupporter {
+		d = p.Supporter
+	}
+	if d <= 0 {
+		return time.Time{}
+	}
+	return now.Add(-d)
+}
+
+// PlanOf mengembalikan plan user berdasarkan flag isSupport
+func PlanOf(isSupport bool) string {
+	if isSupport {
+		return PlanSupporter
+	}
+	return PlanFree
+}
+
+// Days mengubah jumlah hari menjadi durasi
+func Days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
@@ -0,0 +1,40 @@
+package retention
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCutoffPerPlan(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	p := Policy{Collection: "activity_log", TimeField: "created_at", UserField: "user_id", Free: Days(30), Supporter: Days(365)}
+
+	if !p.PerPlan() || len(p.Plans()) != 2 {
+		t.Fatalf("policy seharusnya per plan: %v", p.Plans())
+	}
+	if got := p.Cutoff(now, PlanFree); !got.Equal(now.AddDate(0, 0, -30)) {
+		t.Errorf("cutoff free = %v", got)
+	}
+	if got := p.Cutoff(now, PlanSupporter); !got.Equal(now.AddDate(0, 0, -365)) {
+		t.Errorf("cutoff supporter = %v", got)
+	}
+}
+
+func TestFlatPolicy(t *testing.T) {
+	now := time.Now()
+	p := Policy{Collection: "login_logs", TimeField: "login_at", UserField: "user_id", Free: Days(90), Supporter: Days(90)}
+	if p.PerPlan() {
+		t.Error("durasi sama tidak perlu dipisah per plan")
+	}
+	if plans := p.Plans(); len(plans) != 1 || plans[0] != PlanFree {
+		t.Errorf("Plans = %v", plans)
+	}
+
+	keep := Policy{Collection: "x", TimeField: "t"}
+	if !keep.Cutoff(now, PlanFree).IsZero() {
+		t.Error("durasi nol berarti tidak pernah dipurge")
+	}
+	if PlanOf(true) != PlanSupporter || PlanOf(false) != PlanFree {
+		t.Error("PlanOf salah")
+	}
+}
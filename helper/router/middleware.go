@@ -0,0 +1,175 @@
+package router
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/gocroot/helper/logging"
+	"github.com/gocroot/helper/metrics"
+)
+
+// Recover menangkap panic di handler, mencatat stack trace, lalu membalas 500 jika respons belum
+// sempat ditulis. onPanic boleh nil.
+func Recover(onPanic http.Handler) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := NewRecorder(w)
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				slog.ErrorContext(r.Context(), "panic", "method", r.Method, "path", r.URL.Path,
+					"error", v, "stack", string(debug.Stack()))
+				if rec.Written() {
+					return
+				}
+				if onPanic != nil {
+					onPanic.ServeHTTP(w, r)
+					return
+				}
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+// MaxBodySize membatasi ukuran body request. Handler yang membaca melewati batas mendapat error
+// *http.MaxBytesError. Batas yang lebih kecil di route atau handler tetap berlaku.
+func MaxBodySize(n int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = http.MaxBytesReader(w, r.Body, n)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequestID memakai X-Request-ID dari request atau membuat ID baru, menyimpannya di context untuk
+// log, dan mengembalikannya di header respons
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(logging.RequestIDHeader)
+		if !logging.ValidRequestID(id) {
+			id = logging.NewRequestID()
+		}
+		w.Header().Set(logging.RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+// Logger mencatat setiap request sebagai log terstruktur berisi method, path, status, durasi dan
+// ukuran respons. Status 5xx dicatat sebagai ERROR. Pasang setelah RequestID.
+func Logger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := NewRecorder(w)
+		next.ServeHTTP(rec, r)
+		level := slog.LevelInfo
+		if rec.Status() >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.Log(r.Context(), level, "request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.Status(),
+			"latency_ms", float64(time.Since(start).Microseconds())/1000,
+			"bytes", rec.Bytes(),
+			"user_agent", r.UserAgent(),
+		)
+	})
+}
+
+// Metrics mencatat jumlah request dan latensi per method, pola route dan status ke metrics.
+// Request yang tidak cocok dengan route mana pun dicatat dengan route "unmatched".
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := NewRecorder(w)
+		next.ServeHTTP(rec, r)
+		route := rec.Route()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.HTTPRequests.WithLabelValues(r.Method, route, strconv.Itoa(rec.Status())).Inc()
+		metrics.HTTPDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// Recorder mencatat status dan jumlah byte respons. Flush diteruskan agar stream SSE tetap jalan.
+type Recorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+	route  string
+}
+
+// NewRecorder membungkus w. Jika w sudah Recorder, w dipakai ulang.
+func NewRecorder(w http.ResponseWriter) *Recorder {
+	if rec, ok := w.(*Recorder); ok {
+		return rec
+	}
+	return &Recorder{ResponseWriter: w}
+}
+
+func (rec *Recorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *Recorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += int64(n)
+	return n, err
+}
+
+func (rec *Recorder) Flush() {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap dipakai http.ResponseController
+func (rec *Recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// Status adalah status respons, 200 jika handler tidak menulis apa pun
+func (rec *Recorder) Status() int {
+	if rec.status == 0 {
+		return http.StatusOK
+	}
+	return rec.status
+}
+
+// Bytes adalah jumlah byte body yang sudah ditulis
+func (rec *Recorder) Bytes() int64 {
+	return rec.bytes
+}
+
+// Route adalah pola route yang cocok, kosong jika tidak ada (404/405)
+func (rec *Recorder) Route() string {
+	return rec.route
+}
+
+// Written bernilai true jika header respons sudah dikirim
+func (rec *Recorder) Written() bool {
+	return rec.status != 0
+}
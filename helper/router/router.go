@@ -0,0 +1,278 @@
+// Package router adalah router HTTP kecil untuk route.URL: registrasi route deklaratif dengan
+// parameter path (":id") dan wildcard ("*"), respons 405 beserta header Allow, serta middleware yang
+// bisa disusun per router, per grup atau per route. Router adalah http.Handler biasa sehingga tetap
+// bisa dipakai entrypoint Cloud Functions WebHook.
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Middleware membungkus handler, mis. untuk CORS, autentikasi atau logging
+type Middleware func(http.Handler) http.Handler
+
+// Chain membungkus h dengan middleware. Middleware pertama menjadi lapisan terluar.
+func Chain(h http.Handler, mw ...Middleware) http.Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
+
+type route struct {
+	method  string
+	pattern string
+	segs    []string
+	handler http.Handler
+}
+
+// Router mencocokkan request dengan route terdaftar. Jika beberapa pola cocok, segmen statis lebih
+// diutamakan daripada parameter, dan parameter daripada wildcard, dihitung dari kiri; urutan
+// registrasi tidak berpengaruh.
+type Router struct {
+	routes     []route
+	middleware []Middleware
+	// NotFound dipanggil jika tidak ada pola yang cocok dengan path
+	NotFound http.Handler
+	// MethodNotAllowed dipanggil jika path cocok tetapi method tidak. Header Allow sudah diisi.
+	MethodNotAllowed http.Handler
+}
+
+// New membuat router dengan middleware global. Middleware global juga membungkus respons 404 dan 405.
+func New(mw ...Middleware) *Router {
+	return &Router{middleware: mw}
+}
+
+// Use menambahkan middleware global
+func (rt *Router) Use(mw ...Middleware) {
+	rt.middleware = append(rt.middleware, mw...)
+}
+
+// Handle mendaftarkan handler untuk method dan pola path. Pola yang sama dengan method yang sama
+// tidak boleh didaftarkan dua kali.
+func (rt *Router) Handle(method, pattern string, h http.Handler, mw ...Middleware) {
+	segs := splitPath(pattern)
+	for i, seg := range segs {
+		if seg == "*" && i != len(segs)-1 {
+			panic(fmt.Sprintf("router: wildcard hanya boleh di segmen terakhir: %s", pattern))
+		}
+	}
+	for _, existing := range rt.routes {
+		if existing.method == method && samePattern(existing.segs, segs) {
+			panic(fmt.Sprintf("router: route ganda %s %s (sudah ada %s)", method, pattern, existing.pattern))
+		}
+	}
+	rt.routes = append(rt.routes, route{method: method, pattern: pattern, segs: segs, handler: Chain(h, mw...)})
+}
+
+// HandleFunc seperti Handle untuk http.HandlerFunc
+func (rt *Router) HandleFunc(method, pattern string, h http.HandlerFunc, mw ...Middleware) {
+	rt.Handle(method, pattern, h, mw...)
+}
+
+func (rt *Router) GET(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	rt.Handle(http.MethodGet, pattern, h, mw...)
+}
+
+func (rt *Router) POST(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	rt.Handle(http.MethodPost, pattern, h, mw...)
+}
+
+func (rt *Router) PUT(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	rt.Handle(http.MethodPut, pattern, h, mw...)
+}
+
+func (rt *Router) DELETE(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	rt.Handle(http.MethodDelete, pattern, h, mw...)
+}
+
+// Group membuat kelompok route dengan prefix dan middleware bersama
+func (rt *Router) Group(prefix string, mw ...Middleware) *Group {
+	return &Group{router: rt, prefix: strings.TrimRight(prefix, "/"), middleware: mw}
+}
+
+// ServeHTTP menjalankan middleware global lalu handler route yang cocok
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	Chain(http.HandlerFunc(rt.dispatch), rt.middleware...).ServeHTTP(w, r)
+}
+
+func (rt *Router) dispatch(w http.ResponseWriter, r *http.Request) {
+	path := splitPath(r.URL.Path)
+	var best *route
+	var bestParams map[string]string
+	allowed := map[string]bool{}
+	for i := range rt.routes {
+		rr := &rt.routes[i]
+		params, ok := match(rr.segs, path)
+		if !ok {
+			continue
+		}
+		allowed[rr.method] = true
+		if rr.method == r.Method && (best == nil || moreSpecific(rr.segs, best.segs)) {
+			best, bestParams = rr, params
+		}
+	}
+
+	if best != nil {
+		r.Pattern = best.pattern
+		if rec := findRecorder(w); rec != nil {
+			rec.route = best.pattern
+		}
+		for name, value := range bestParams {
+			r.SetPathValue(name, value)
+		}
+		best.handler.ServeHTTP(w, r)
+		return
+	}
+	if len(allowed) == 0 {
+		if rt.NotFound != nil {
+			rt.NotFound.ServeHTTP(w, r)
+		} else {
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	allowed[http.MethodOptions] = true
+	methods := make([]string, 0, len(allowed))
+	for m := range allowed {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	switch {
+	case r.Method == http.MethodOptions:
+		w.WriteHeader(http.StatusNoContent)
+	case rt.MethodNotAllowed != nil:
+		rt.MethodNotAllowed.ServeHTTP(w, r)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// Param mengambil parameter path dari route yang cocok, mis. Param(r, "id") untuk pola "/x/:id".
+// Sisa path dari wildcard tersedia sebagai Param(r, "*").
+func Param(r *http.Request, name string) string {
+	return r.PathValue(name)
+}
+
+// findRecorder mencari Recorder di rantai ResponseWriter agar middleware luar (Logger, Metrics)
+// bisa membaca pola route yang cocok
+func findRecorder(w http.ResponseWriter) *Recorder {
+	for {
+		switch v := w.(type) {
+		case *Recorder:
+			return v
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = v.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+// Group adalah kumpulan route dengan prefix dan middleware yang sama
+type Group struct {
+	router     *Router
+	prefix     string
+	middleware []Middleware
+}
+
+// Handle mendaftarkan route di bawah prefix grup. Middleware grup dijalankan sebelum middleware route.
+func (g *Group) Handle(method, pattern string, h http.Handler, mw ...Middleware) {
+	all := append(append([]Middleware{}, g.middleware...), mw...)
+	g.router.Handle(method, g.prefix+pattern, h, all...)
+}
+
+func (g *Group) GET(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	g.Handle(http.MethodGet, pattern, h, mw...)
+}
+
+func (g *Group) POST(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	g.Handle(http.MethodPost, pattern, h, mw...)
+}
+
+func (g *Group) PUT(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	g.Handle(http.MethodPut, pattern, h, mw...)
+}
+
+func (g *Group) DELETE(pattern string, h http.HandlerFunc, mw ...Middleware) {
+	g.Handle(http.MethodDelete, pattern, h, mw...)
+}
+
+// Group membuat sub-grup yang mewarisi prefix dan middleware grup ini
+func (g *Group) Group(prefix string, mw ...Middleware) *Group {
+	all := append(append([]Middleware{}, g.middleware...), mw...)
+	return &Group{router: g.router, prefix: g.prefix + strings.TrimRight(prefix, "/"), middleware: all}
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// match mencocokkan segmen pola dengan segmen path. Parameter tidak boleh kosong.
+func match(pattern, path []string) (map[string]string, bool) {
+	var params map[string]string
+	for i, seg := range pattern {
+		if seg == "*" {
+			if params == nil {
+				params = map[string]string{}
+			}
+			params["*"] = strings.Join(path[i:], "/")
+			return params, true
+		}
+		if i >= len(path) {
+			return nil, false
+		}
+		if strings.HasPrefix(seg, ":") {
+			if path[i] == "" {
+				return nil, false
+			}
+			if params == nil {
+				params = map[string]string{}
+			}
+			params[seg[1:]] = path[i]
+			continue
+		}
+		if seg != path[i] {
+			return nil, false
+		}
+	}
+	return params, len(pattern) == len(path)
+}
+
+// segmentRank: statis > parameter > wildcard
+func segmentRank(seg string) int {
+	switch {
+	case seg == "*":
+		return 0
+	case strings.HasPrefix(seg, ":"):
+		return 1
+	}
+	return 2
+}
+
+func moreSpecific(a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if ra, rb := segmentRank(a[i]), segmentRank(b[i]); ra != rb {
+			return ra > rb
+		}
+	}
+	return len(a) > len(b)
+}
+
+func samePattern(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		ra, rb := segmentRank(a[i]), segmentRank(b[i])
+		if ra != rb || (ra == 2 && a[i] != b[i]) {
+			return false
+		}
+	}
+	return true
+}
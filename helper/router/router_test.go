@@ -0,0 +1,164 @@
+package router
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gocroot/helper/logging"
+	"github.com/gocroot/helper/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func serve(rt http.Handler, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func reply(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body + Param(r, "id") + Param(r, "*")))
+	}
+}
+
+func TestRouterMatch(t *testing.T) {
+	rt := New()
+	rt.GET("/", reply("home"))
+	rt.GET("/pdfm/feedback/:id", reply("thread:"))
+	rt.PUT("/pdfm/feedback/:id", reply("update:"))
+	rt.GET("/pdfm/feedback/mine", reply("mine"))
+	rt.POST("/pdfm/history/:id/rerun", reply("rerun:"))
+	rt.GET("/swagger/*", reply("swagger:"))
+
+	cases := []struct{ method, path, want string }{
+		{"GET", "/", "home"},
+		{"GET", "/pdfm/feedback/mine", "mine"},
+		{"GET", "/pdfm/feedback/65b1", "thread:65b1"},
+		{"PUT", "/pdfm/feedback/mine", "update:mine"},
+		{"POST", "/pdfm/history/65b1/rerun/", "rerun:65b1"},
+		{"GET", "/swagger/index.html", "swagger:index.html"},
+	}
+	for _, c := range cases {
+		if got := serve(rt, c.method, c.path).Body.String(); got != c.want {
+			t.Errorf("%s %s = %q, ingin %q", c.method, c.path, got, c.want)
+		}
+	}
+	for _, path := range []string{"/pdfm/feedback", "/pdfm/history//rerun", "/pdfm/feedback/a/b"} {
+		if code := serve(rt, "GET", path).Code; code != http.StatusNotFound {
+			t.Errorf("GET %s = %d, ingin 404", path, code)
+		}
+	}
+}
+
+func TestRouterMethodNotAllowed(t *testing.T) {
+	rt := New()
+	rt.GET("/pdfm/notifications", reply("list"))
+	rt.DELETE("/pdfm/notifications", reply("clear"))
+
+	w := serve(rt, "PATCH", "/pdfm/notifications")
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "DELETE, GET, OPTIONS" {
+		t.Errorf("PATCH = %d, Allow %q", w.Code, w.Header().Get("Allow"))
+	}
+	w = serve(rt, "OPTIONS", "/pdfm/notifications")
+	if w.Code != http.StatusNoContent || w.Header().Get("Allow") == "" {
+		t.Errorf("OPTIONS = %d, Allow %q", w.Code, w.Header().Get("Allow"))
+	}
+}
+
+func TestRouterDuplicate(t *testing.T) {
+	rt := New()
+	rt.POST("/webhook/nomor/:nomorwa", reply(""))
+	defer func() {
+		if recover() == nil {
+			t.Error("route ganda harus panic")
+		}
+	}()
+	rt.POST("/webhook/nomor/:nomor", reply(""))
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var order []string
+	mark := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	rt := New(mark("global"))
+	api := rt.Group("/pdfm", mark("group"))
+	api.GET("/stats", func(w http.ResponseWriter, r *http.Request) { order = append(order, "handler") }, mark("route"))
+
+	serve(rt, "GET", "/pdfm/stats")
+	if got := strings.Join(order, ","); got != "global,group,route,handler" {
+		t.Errorf("urutan = %s", got)
+	}
+	order = nil
+	serve(rt, "GET", "/tidak-ada")
+	if got := strings.Join(order, ","); got != "global" {
+		t.Errorf("404 harus tetap melewati middleware global, urutan = %s", got)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	rt := New(Recover(nil))
+	rt.GET("/panic", func(w http.ResponseWriter, r *http.Request) { panic("boom") })
+	if code := serve(rt, "GET", "/panic").Code; code != http.StatusInternalServerError {
+		t.Errorf("panic = %d", code)
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	rt := New(MaxBodySize(4))
+	rt.POST("/upload", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		}
+	})
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest("POST", "/upload", strings.NewReader("lebih dari empat byte")))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("body besar = %d", w.Code)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	rt := New(RequestID)
+	rt.GET("/", func(w http.ResponseWriter, r *http.Request) { seen = logging.RequestID(r.Context()) })
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(logging.RequestIDHeader, "trace-1")
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, r)
+	if seen != "trace-1" || w.Header().Get(logging.RequestIDHeader) != "trace-1" {
+		t.Errorf("ID dari header = %q, respons %q", seen, w.Header().Get(logging.RequestIDHeader))
+	}
+
+	r.Header.Set(logging.RequestIDHeader, "bukan\nid")
+	w = httptest.NewRecorder()
+	rt.ServeHTTP(w, r)
+	if seen == "" || seen == "bukan\nid" || w.Header().Get(logging.RequestIDHeader) != seen {
+		t.Errorf("ID tidak valid harus diganti, dapat %q", seen)
+	}
+}
+
+func TestMetricsRoutePattern(t *testing.T) {
+	rt := New(RequestID, Metrics)
+	rt.GET("/metrics-test/:id", reply("ok"))
+	matched := metrics.HTTPRequests.WithLabelValues("GET", "/metrics-test/:id", "200")
+	before := testutil.ToFloat64(matched)
+	serve(rt, "GET", "/metrics-test/1")
+	serve(rt, "GET", "/metrics-test/2")
+	serve(rt, "GET", "/metrics-test")
+	if got := testutil.ToFloat64(matched) - before; got != 2 {
+		t.Errorf("request tercatat %v, seharusnya 2 untuk satu pola route", got)
+	}
+	if testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues("GET", "unmatched", "404")) < 1 {
+		t.Error("request 404 seharusnya tercatat sebagai unmatched")
+	}
+}
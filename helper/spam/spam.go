@@ -0,0 +1,129 @@
+package spam
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// DefaultThreshold adalah skor minimal agar pesan dianggap spam
+const DefaultThreshold = 3.0
+
+// DefaultBlocklist berisi frasa yang hampir selalu muncul di spam kotak saran
+var DefaultBlocklist = []string{
+	"casino", "slot gacor", "judi online", "togel", "viagra", "cialis", "bitcoin doubler",
+	"crypto investment", "pinjol", "pinjaman online cepat", "seo service", "backlink",
+	"bokep", "porn", "xxx", "escort", "forex signal", "click here", "klik di sini",
+}
+
+var (
+	rxURL    = regexp.MustCompile(`(?i)\b(https?://|www\.)[^\s"'<>\]]+`)
+	rxMarkup = regexp.MustCompile(`(?i)\[url=|<a\s+href`)
+	rxDomain = regexp.MustCompile(`(?i)\b[a-z0-9-]+\.(com|net|org|xyz|top|ru|cn|io|info|biz|site|online|link|click)\b`)
+)
+
+// Scorer memberi skor spam berdasarkan jumlah link, blocklist dan pola teks.
+// MaxLinks adalah jumlah link yang masih wajar (mis. link ke file contoh).
+type Scorer struct {
+	MaxLinks  int
+	Blocklist []string
+	Threshold float64
+}
+
+// Result adalah skor pesan beserta alasannya agar admin bisa meninjau
+type Result struct {
+	Score   float64
+	Spam    bool
+	Reasons []string
+}
+
+// NewScorer membuat Scorer dengan blocklist default ditambah extra
+func NewScorer(extra ...string) Scorer {
+	list := append([]string{}, DefaultBlocklist...)
+	for _, w := range extra {
+		if w = strings.TrimSpace(strings.ToLower(w)); w != "" {
+			list = append(list, w)
+		}
+	}
+	return Scorer{MaxLinks: 1, Blocklist: list, Threshold: DefaultThreshold}
+}
+
+// Links menghitung link dan nama domain di teks
+func Links(text string) int {
+	n := len(rxURL.FindAllStringIndex(text, -1)) + len(rxMarkup.FindAllStringIndex(text, -1))
+	// Domain tanpa skema ("murah.xyz") juga dihitung, kecuali yang sudah bagian dari URL di atas
+	stripped := rxURL.ReplaceAllString(text, " ")
+	for _, m := range rxDomain.FindAllStringIndex(stripped, -1) {
+		if m[0] == 0 || stripped[m[0]-1] != '@' {
+			n++
+		}
+	}
+	return n
+}
+
+// Score menilai pesan dari form kontak; name dan email ikut diperiksa karena bot sering mengisi link di sana
+func (s Scorer) Score(name, email, message string) Result {
+	var res Result
+	add := func(score float64, reason string) {
+		res.Score += score
+		res.Reasons = append(res.Reasons, reason)
+	}
+
+	links := Links(message)
+	if links > s.MaxLinks {
+		add(float64(links-s.MaxLinks), "terlalu banyak link")
+	}
+	if Links(name) > 0 {
+		add(3, "link di nama")
+	}
+
+	lower := strings.ToLower(name + " " + email + " " + message)
+	for _, w := range s.Blocklist {
+		if w != "" && strings.Contains(lower, w) {
+			add(2, "blocklist: "+w)
+		}
+	}
+
+	letters, upper := 0, 0
+	for _, r := range message {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	if letters >= 20 && float64(upper)/float64(letters) > 0.7 {
+		add(1, "huruf kapital berlebihan")
+	}
+	if letters < 5 {
+		add(1, "pesan terlalu pendek")
+	}
+	if repeated(message, 8) {
+		add(1, "karakter berulang")
+	}
+
+	threshold := s.Threshold
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	res.Spam = res.Score >= threshold
+	return res
+}
+
+// repeated bernilai true jika ada karakter yang sama berturut-turut minimal n kali ("!!!!!!!!")
+func repeated(text string, n int) bool {
+	var last rune
+	count := 0
+	for _, r := range text {
+		if r == last {
+			count++
+			if count >= n {
+				return true
+			}
+			continue
+		}
+		last, count = r, 1
+	}
+	return false
+}
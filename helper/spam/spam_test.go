@@ -0,0 +1,42 @@
+package spam
+
+import "testing"
+
+func TestLinks(t *testing.T) {
+	cases := map[string]int{
+		"Fitur gabung PDF error":                         0,
+		"contoh file: https://drive.google.com/abc":      1,
+		"kunjungi www.murah.xyz dan http://a.b/c":        2,
+		"beli di murah.xyz atau obat.ru":                 2,
+		"email saya budi@gmail.com":                      0,
+		`<a href="http://x">x</a> [url=http://y]y[/url]`: 4,
+	}
+	for text, want := range cases {
+		if got := Links(text); got != want {
+			t.Errorf("Links(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	s := NewScorer("promo spesial")
+	ham := []struct{ name, email, msg string }{
+		{"Budi", "budi@gmail.com", "Fitur kompres PDF gagal untuk file di atas 20 MB, mohon dicek."},
+		{"Sari", "sari@kampus.ac.id", "Contoh file yang error: https://drive.google.com/file/d/abc"},
+	}
+	for _, h := range ham {
+		if r := s.Score(h.name, h.email, h.msg); r.Spam {
+			t.Errorf("pesan wajar dianggap spam: %q %+v", h.msg, r)
+		}
+	}
+	spam := []struct{ name, email, msg string }{
+		{"Slot", "x@y.com", "SLOT GACOR hari ini http://a.xyz http://b.xyz http://c.xyz"},
+		{"http://seo.top", "seo@seo.top", "We offer cheap SEO service and backlink packages"},
+		{"Admin", "a@b.com", "Promo spesial!!!!!!!!!! klik di sini www.promo.site"},
+	}
+	for _, m := range spam {
+		if r := s.Score(m.name, m.email, m.msg); !r.Spam {
+			t.Errorf("spam lolos: %q %+v", m.msg, r)
+		}
+	}
+}
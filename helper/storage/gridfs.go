@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GridFS menyimpan file di bucket GridFS pada database Mongo yang sama dengan aplikasi.
+// Metadata (owner, purpose, content_type, expires_at) disimpan di field metadata dokumen <bucket>.files.
+type GridFS struct {
+	DB     *mongo.Database
+	Bucket string
+}
+
+func NewGridFS(db *mongo.Database, bucket string) *GridFS {
+	return &GridFS{DB: db, Bucket: bucket}
+}
+
+type gridfsMetadata struct {
+	Owner       string    `bson:"owner,omitempty"`
+	Purpose     string    `bson:"purpose,omitempty"`
+	ContentType string    `bson:"content_type"`
+	ExpiresAt   time.Time `bson:"expires_at,omitempty"`
+}
+
+type gridfsFile struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	Length     int64              `bson:"length"`
+	UploadDate time.Time          `bson:"uploadDate"`
+	Filename   string             `bson:"filename"`
+	Metadata   gridfsMetadata     `bson:"metadata"`
+}
+
+func (g *GridFS) bucket() (*gridfs.Bucket, error) {
+	if g.DB == nil {
+		return nil, errors.New("storage: koneksi database belum tersedia")
+	}
+	return gridfs.NewBucket(g.DB, options.GridFSBucket().SetName(g.Bucket))
+}
+
+// FilesCollection adalah koleksi metadata GridFS, dipakai untuk query retensi
+func (g *GridFS) FilesCollection() *mongo.Collection {
+	return g.DB.Collection(g.Bucket + ".files")
+}
+
+func (g *GridFS) Put(ctx context.Context, obj Object, data []byte) (Object, error) {
+	b, err := g.bucket()
+	if err != nil {
+		return Object{}, err
+	}
+	meta := gridfsMetadata{Owner: obj.Owner, Purpose: obj.Purpose, ContentType: obj.ContentType, ExpiresAt: obj.ExpiresAt}
+	id, err := b.UploadFromStream(obj.Name, bytes.NewReader(data), options.GridFSUpload().SetMetadata(meta))
+	if err != nil {
+		return Object{}, err
+	}
+	obj.ID = id.Hex()
+	obj.Size = int64(len(data))
+	obj.CreatedAt = time.Now()
+	return obj, nil
+}
+
+func (g *GridFS) stat(ctx context.Context, id string) (primitive.ObjectID, Object, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return oid, Object{}, ErrNotFound
+	}
+	var f gridfsFile
+	err = g.FilesCollection().FindOne(ctx, bson.M{"_id": oid}).Decode(&f)
+	if err == mongo.ErrNoDocuments {
+		return oid, Object{}, ErrNotFound
+	}
+	if err != nil {
+		return oid, Object{}, err
+	}
+	return oid, f.object(), nil
+}
+
+func (f gridfsFile) object() Object {
+	return Object{
+		ID:          f.ID.Hex(),
+		Name:        f.Filename,
+		ContentType: f.Metadata.ContentType,
+		Size:        f.Length,
+		Owner:       f.Metadata.Owner,
+		Purpose:     f.Metadata.Purpose,
+		ExpiresAt:   f.Metadata.ExpiresAt,
+		CreatedAt:   f.UploadDate,
+	}
+}
+
+func (g *GridFS) Stat(ctx context.Context, id string) (Object, error) {
+	if g.DB == nil {
+		return Object{}, errors.New("storage: koneksi database belum tersedia")
+	}
+	_, obj, err := g.stat(ctx, id)
+	return obj, err
+}
+
+func (g *GridFS) Get(ctx context.Context, id string) (Object, []byte, error) {
+	if g.DB == nil {
+		return Object{}, nil, errors.New("storage: koneksi database belum tersedia")
+	}
+	oid, obj, err := g.stat(ctx, id)
+	if err != nil {
+		return Object{}, nil, err
+	}
+	if obj.Expired(time.Now()) {
+		return obj, nil, ErrExpired
+	}
+	b, err := g.bucket()
+	if err != nil {
+		return Object{}, nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := b.DownloadToStream(oid, &buf); err != nil {
+		return Object{}, nil, err
+	}
+	return obj, buf.Bytes(), nil
+}
+
+func (g *GridFS) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrNotFound
+	}
+	b, err := g.bucket()
+	if err != nil {
+		return err
+	}
+	err = b.DeleteContext(ctx, oid)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (g *GridFS) ListExpired(ctx context.Context, now time.Time) ([]Object, error) {
+	// expires_at zero tidak ikut karena omitempty membuat field-nya tidak tersimpan
+	return g.list(ctx, bson.M{"metadata.expires_at": bson.M{"$lte": now}})
+}
+
+func (g *GridFS) ListOwned(ctx context.Context, owner string) ([]Object, error) {
+	if owner == "" {
+		return nil, nil
+	}
+	return g.list(ctx, bson.M{"metadata.owner": owner})
+}
+
+func (g *GridFS) list(ctx context.Context, filter bson.M) ([]Object, error) {
+	if g.DB == nil {
+		return nil, errors.New("storage: koneksi database belum tersedia")
+	}
+	cur, err := g.FilesCollection().Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var files []gridfsFile
+	if err := cur.All(ctx, &files); err != nil {
+		return nil, err
+	}
+	out := make([]Object, 0, len(files))
+	for _, f := range files {
+		out = append(out, f.object())
+	}
+	return out, nil
+}
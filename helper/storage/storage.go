@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotFound = errors.New("file tidak ditemukan")
+	ErrExpired  = errors.New("file sudah melewati masa retensi")
+)
+
+// Backend adalah tempat penyimpanan file biner (input/output tool, lampiran, dsb).
+// Implementasi bawaan: GridFS (produksi) dan Memory (test).
+type Backend interface {
+	// Put menyimpan data dan mengembalikan metadata lengkap (ID, Size, CreatedAt terisi)
+	Put(ctx context.Context, obj Object, data []byte) (Object, error)
+	// Stat mengambil metadata tanpa isi file (tetap dikembalikan walau sudah kedaluwarsa)
+	Stat(ctx context.Context, id string) (Object, error)
+	// Get mengambil file; ErrExpired jika masa retensinya sudah lewat
+	Get(ctx context.Context, id string) (Object, []byte, error)
+	// Delete menghapus file; ErrNotFound jika tidak ada
+	Delete(ctx context.Context, id string) error
+	// ListExpired mengembalikan metadata semua file yang masa retensinya sudah lewat pada waktu now
+	ListExpired(ctx context.Context, now time.Time) ([]Object, error)
+	// ListOwned mengembalikan metadata semua file milik owner (hex ID user), termasuk yang kedaluwarsa
+	ListOwned(ctx context.Context, owner string) ([]Object, error)
+}
+
+// Memory adalah Backend in-process untuk test dan pengembangan lokal
+type Memory struct {
+	mu    sync.Mutex
+	seq   int
+	objs  map[string]Object
+	blobs map[string][]byte
+}
+
+func NewMemory() *Memory {
+	return &Memory{objs: map[string]Object{}, blobs: map[string][]byte{}}
+}
+
+func (m *Memory) Put(ctx context.Context, obj Object, data []byte) (Object, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	obj.ID = strconv.Itoa(m.seq)
+	obj.Size = int64(len(data))
+	obj.CreatedAt = time.Now()
+	m.objs[obj.ID] = obj
+	m.blobs[obj.ID] = append([]byte(nil), data...)
+	return obj, nil
+}
+
+func (m *Memory) Stat(ctx context.Context, id string) (Object, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.objs[id]
+	if !ok {
+		return Object{}, ErrNotFound
+	}
+	return obj, nil
+}
+
+func (m *Memory) Get(ctx context.Context, id string) (Object, []byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.objs[id]
+	if !ok {
+		return Object{}, nil, ErrNotFound
+	}
+	if obj.Expired(time.Now()) {
+		return obj, nil, ErrExpired
+	}
+	return obj, m.blobs[id], nil
+}
+
+func (m *Memory) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.objs[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.objs, id)
+	delete(m.blobs, id)
+	return nil
+}
+
+func (m *Memory) ListExpired(ctx context.Context, now time.Time) ([]Object, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []Object
+	for _, obj := range m.objs {
+		if obj.Expired(now) {
+			out = append(out, obj)
+		}
+	}
+	return out, nil
+}
+
+func (m *Memory) ListOwned(ctx context.Context, owner string) ([]Object, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []Object
+	for _, obj := range m.objs {
+		if owner != "" && obj.Owner == owner {
+			out = append(out, obj)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+
+	obj, err := m.Put(ctx, Object{Name: "a.pdf", ContentType: "application/pdf", Owner: "u1"}, []byte("%PDF-1.4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.ID == "" || obj.Size != 8 {
+		t.Fatalf("metadata tidak terisi: %+v", obj)
+	}
+
+	got, data, err := m.Get(ctx, obj.ID)
+	if err != nil || string(data) != "%PDF-1.4" || got.Owner != "u1" {
+		t.Fatalf("Get = %+v, %q, %v", got, data, err)
+	}
+
+	if err := m.Delete(ctx, obj.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := m.Get(ctx, obj.ID); err != ErrNotFound {
+		t.Errorf("Get setelah Delete err = %v", err)
+	}
+	if err := m.Delete(ctx, obj.ID); err != ErrNotFound {
+		t.Errorf("Delete dua kali err = %v", err)
+	}
+}
+
+func TestMemoryExpired(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	obj, _ := m.Put(ctx, Object{Name: "old.pdf", ExpiresAt: time.Now().Add(-time.Minute)}, []byte("x"))
+	if _, _, err := m.Get(ctx, obj.ID); err != ErrExpired {
+		t.Errorf("Get file kedaluwarsa err = %v", err)
+	}
+	if (Object{}).Expired(time.Now()) {
+		t.Error("ExpiresAt kosong tidak boleh dianggap kedaluwarsa")
+	}
+
+	m.Put(ctx, Object{Name: "fresh.pdf", ExpiresAt: time.Now().Add(time.Hour)}, []byte("y"))
+	m.Put(ctx, Object{Name: "forever.pdf"}, []byte("z"))
+	expired, err := m.ListExpired(ctx, time.Now())
+	if err != nil || len(expired) != 1 || expired[0].ID != obj.ID {
+		t.Errorf("ListExpired = %+v, %v", expired, err)
+	}
+}
+
+func TestMemoryOwned(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	mine, _ := m.Put(ctx, Object{Name: "a.pdf", Owner: "u1", ExpiresAt: time.Now().Add(-time.Minute)}, []byte("x"))
+	m.Put(ctx, Object{Name: "b.pdf", Owner: "u2"}, []byte("y"))
+	m.Put(ctx, Object{Name: "c.pdf"}, []byte("z"))
+	owned, err := m.ListOwned(ctx, "u1")
+	if err != nil || len(owned) != 1 || owned[0].ID != mine.ID {
+		t.Errorf("ListOwned = %+v, %v", owned, err)
+	}
+	if owned, _ := m.ListOwned(ctx, ""); len(owned) != 0 {
+		t.Errorf("ListOwned owner kosong = %+v", owned)
+	}
+}
@@ -0,0 +1,20 @@
+package storage
+
+import "time"
+
+// Object adalah metadata satu file yang disimpan di storage backend
+type Object struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Owner       string    `json:"owner,omitempty"`      // hex ID user pemilik file
+	Purpose     string    `json:"purpose,omitempty"`    // mis. tool-input, tool-output
+	ExpiresAt   time.Time `json:"expires_at,omitempty"` // zero berarti tidak kedaluwarsa
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Expired bernilai true jika masa retensi objek sudah lewat pada waktu now
+func (o Object) Expired(now time.Time) bool {
+	return !o.ExpiresAt.IsZero() && !now.Before(o.ExpiresAt)
+}
@@ -0,0 +1,81 @@
+package triage
+
+// Leksikon sentimen berisi kata dasar; kata Indonesia dicocokkan setelah di-stem dengan Sastrawi.
+// Nilai +1 berarti positif dan -1 negatif.
+var lexiconID = map[string]int{
+	"bagus": 1, "baik": 1, "mantap": 1, "mantul": 1, "keren": 1, "suka": 1, "senang": 1, "puas": 1,
+	"mudah": 1, "cepat": 1, "bantu": 1, "hebat": 1, "sempurna": 1, "lancar": 1, "makasih": 1,
+	"terimakasih": 1, "praktis": 1, "guna": 1, "manfaat": 1, "rapi": 1, "nyaman": 1, "aman": 1,
+	"sukses": 1, "rekomendasi": 1, "top": 1, "recommended": 1, "oke": 1, "jelas": 1, "ringan": 1,
+
+	"buruk": -1, "jelek": -1, "rusak": -1, "gagal": -1, "error": -1, "eror": -1, "lambat": -1,
+	"lemot": -1, "lelet": -1, "kecewa": -1, "susah": -1, "sulit": -1, "ribet": -1, "bug": -1,
+	"crash": -1, "hilang": -1, "salah": -1, "mahal": -1, "parah": -1, "payah": -1, "bingung": -1,
+	"macet": -1, "hang": -1, "masalah": -1, "benci": -1, "kesal": -1, "ganggu": -1, "tipu": -1,
+	"berat": -1, "korup": -1, "blank": -1, "kacau": -1, "aneh": -1,
+}
+
+var lexiconEN = map[string]int{
+	"good": 1, "great": 1, "awesome": 1, "excellent": 1, "love": 1, "like": 1, "nice": 1,
+	"helpful": 1, "easy": 1, "fast": 1, "perfect": 1, "amazing": 1, "useful": 1, "thanks": 1,
+	"thank": 1, "smooth": 1, "best": 1, "happy": 1, "recommend": 1, "cool": 1, "clean": 1,
+	"simple": 1, "works": 1,
+
+	"bad": -1, "terrible": -1, "awful": -1, "broken": -1, "fail": -1, "failed": -1, "fails": -1,
+	"error": -1, "errors": -1, "slow": -1, "crash": -1, "crashes": -1, "crashed": -1, "bug": -1,
+	"bugs": -1, "hate": -1, "annoying": -1, "difficult": -1, "hard": -1, "wrong": -1,
+	"missing": -1, "lost": -1, "expensive": -1, "disappointed": -1, "disappointing": -1,
+	"useless": -1, "worst": -1, "problem": -1, "issue": -1, "stuck": -1, "poor": -1,
+	"corrupt": -1, "corrupted": -1, "confusing": -1, "blank": -1,
+}
+
+// functionWords bernilai netral, tetapi negatif jika didahului negasi ("tidak bisa", "doesn't work")
+var functionWords = map[string]bool{
+	"bisa": true, "jalan": true, "fungsi": true, "buka": true, "muncul": true, "masuk": true,
+	"unduh": true, "simpan": true, "proses": true, "jadi": true,
+	"work": true, "working": true, "open": true, "load": true, "download": true, "save": true,
+	"upload": true, "show": true,
+}
+
+// negators membalik sentimen kata dalam jendela beberapa token setelahnya
+var negators = map[string]bool{
+	"tidak": true, "tak": true, "gak": true, "ga": true, "nggak": true, "enggak": true,
+	"bukan": true, "belum": true, "kurang": true, "tdk": true, "gk": true,
+	"not": true, "no": true, "never": true, "dont": true, "doesnt": true, "didnt": true,
+	"isnt": true, "wasnt": true, "cant": true, "cannot": true, "wont": true, "couldnt": true,
+}
+
+// Kata fungsi yang menandai bahasa; hanya kata yang khas untuk salah satu bahasa
+var markersID = map[string]bool{
+	"yang": true, "dan": true, "di": true, "ke": true, "dari": true, "tidak": true, "ini": true,
+	"itu": true, "saya": true, "aku": true, "untuk": true, "dengan": true, "bisa": true,
+	"ada": true, "sudah": true, "belum": true, "tolong": true, "mohon": true, "kok": true,
+	"gak": true, "nggak": true, "juga": true, "karena": true, "kalau": true, "apakah": true,
+	"mau": true, "sangat": true, "banget": true, "aplikasi": true, "tapi": true, "atau": true,
+}
+
+var markersEN = map[string]bool{
+	"the": true, "and": true, "is": true, "are": true, "to": true, "of": true, "it": true,
+	"this": true, "that": true, "my": true, "when": true, "with": true, "for": true,
+	"not": true, "can": true, "cant": true, "dont": true, "doesnt": true, "please": true,
+	"would": true, "have": true, "was": true, "very": true, "but": true, "app": true,
+	"you": true, "your": true, "what": true, "why": true, "after": true,
+}
+
+// stopwordsExtra melengkapi stopword Indonesia bawaan Sastrawi dengan stopword Inggris dan kata obrolan
+var stopwordsExtra = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "but": true, "is": true,
+	"are": true, "was": true, "were": true, "be": true, "been": true, "to": true, "of": true,
+	"in": true, "on": true, "at": true, "for": true, "with": true, "from": true, "by": true,
+	"it": true, "its": true, "this": true, "that": true, "these": true, "those": true,
+	"i": true, "me": true, "my": true, "we": true, "our": true, "you": true, "your": true,
+	"he": true, "she": true, "they": true, "them": true, "can": true, "could": true,
+	"would": true, "should": true, "will": true, "have": true, "has": true, "had": true,
+	"do": true, "does": true, "did": true, "when": true, "what": true, "why": true,
+	"how": true, "please": true, "very": true, "so": true, "just": true, "also": true,
+	"after": true, "before": true, "there": true, "here": true, "if": true, "then": true,
+	"than": true, "too": true, "into": true, "about": true, "some": true, "any": true,
+	"all": true, "get": true, "got": true, "app": true, "use": true, "using": true,
+	"tolong": true, "mohon": true, "kok": true, "banget": true, "aja": true, "sih": true,
+	"dong": true, "deh": true, "nih": true, "min": true, "kak": true, "gan": true,
+}
@@ -0,0 +1,117 @@
+package triage
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// DuplicateThreshold adalah skor minimal agar dua feedback dianggap near-duplicate
+const DuplicateThreshold = 0.7
+
+// Document adalah feedback lama yang sudah dianalisis, dipakai sebagai pembanding near-duplicate
+type Document struct {
+	ID      string
+	Cluster string // kosong berarti dokumen ini belum punya cluster selain dirinya sendiri
+	Terms   []string
+}
+
+// Match adalah dokumen yang paling mirip beserta skornya
+type Match struct {
+	Document
+	Score float64
+}
+
+// ClusterOf mengembalikan ID cluster dokumen: Cluster jika ada, selain itu ID-nya sendiri
+func (d Document) ClusterOf() string {
+	if d.Cluster != "" {
+		return d.Cluster
+	}
+	return d.ID
+}
+
+// Nearest mencari dokumen di corpus yang paling mirip dengan terms. Skor adalah rata-rata
+// kemiripan karakter (Analyzer.Similarity atas term unik yang diurutkan) dan cosine TF-IDF atas corpus.
+// ok bernilai false jika tidak ada dokumen yang mencapai threshold.
+func (a *Analyzer) Nearest(terms []string, corpus []Document, threshold float64) (best Match, ok bool) {
+	if len(terms) == 0 || len(corpus) == 0 {
+		return Match{}, false
+	}
+	idf := inverseDocumentFrequency(terms, corpus)
+	query := tfidf(terms, idf)
+	joined := canonical(terms)
+	for _, doc := range corpus {
+		if len(doc.Terms) == 0 {
+			continue
+		}
+		score := (a.Similarity(joined, canonical(doc.Terms)) + cosine(query, tfidf(doc.Terms, idf))) / 2
+		if score > best.Score {
+			best = Match{Document: doc, Score: score}
+		}
+	}
+	return best, best.Score >= threshold
+}
+
+// canonical menggabungkan term unik yang diurutkan agar perbedaan urutan kata tidak menurunkan skor Jaro-Winkler
+func canonical(terms []string) string {
+	uniq := map[string]bool{}
+	list := make([]string, 0, len(terms))
+	for _, t := range terms {
+		if !uniq[t] {
+			uniq[t] = true
+			list = append(list, t)
+		}
+	}
+	sort.Strings(list)
+	return strings.Join(list, " ")
+}
+
+// inverseDocumentFrequency menghitung idf ter-smoothing dari corpus ditambah dokumen query
+func inverseDocumentFrequency(query []string, corpus []Document) map[string]float64 {
+	df := map[string]int{}
+	count := func(terms []string) {
+		seen := map[string]bool{}
+		for _, t := range terms {
+			if !seen[t] {
+				seen[t] = true
+				df[t]++
+			}
+		}
+	}
+	count(query)
+	for _, d := range corpus {
+		count(d.Terms)
+	}
+	n := float64(len(corpus) + 1)
+	idf := make(map[string]float64, len(df))
+	for t, c := range df {
+		idf[t] = math.Log((1+n)/(1+float64(c))) + 1
+	}
+	return idf
+}
+
+func tfidf(terms []string, idf map[string]float64) map[string]float64 {
+	vec := map[string]float64{}
+	for _, t := range terms {
+		vec[t]++
+	}
+	for t, tf := range vec {
+		vec[t] = tf / float64(len(terms)) * idf[t]
+	}
+	return vec
+}
+
+func cosine(a, b map[string]float64) float64 {
+	var dot, na, nb float64
+	for t, v := range a {
+		dot += v * b[t]
+		na += v * v
+	}
+	for _, v := range b {
+		nb += v * v
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,166 @@
+package triage
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/RadhiFadlillah/go-sastrawi"
+	"github.com/gocroot/helper/i18n"
+	"github.com/gocroot/helper/kimseok"
+)
+
+// Label sentimen hasil analisis
+const (
+	Positive = "positive"
+	Neutral  = "neutral"
+	Negative = "negative"
+)
+
+// MaxKeywords adalah jumlah keyword yang diambil dari satu feedback
+const MaxKeywords = 5
+
+// negationWindow adalah jumlah token setelah kata negasi yang sentimennya dibalik
+const negationWindow = 3
+
+// Analysis adalah hasil analisis offline satu pesan feedback
+type Analysis struct {
+	Language  string   // i18n.ID atau i18n.EN
+	Sentiment string   // positive, neutral, negative
+	Score     float64  // -1 (sangat negatif) sampai 1 (sangat positif)
+	Keywords  []string // kata dasar paling sering, tanpa stopword
+	Terms     []string // token ternormalisasi untuk perbandingan near-duplicate
+}
+
+// Analyzer menganalisis teks feedback. Similarity dipakai untuk membandingkan teks secara
+// karakter; default-nya Jaro-Winkler yang juga dipakai kimseok untuk FAQ.
+type Analyzer struct {
+	Similarity func(a, b string) float64
+	stemmer    sastrawi.Stemmer
+	stopwords  sastrawi.Dictionary
+}
+
+// NewAnalyzer memuat kamus Sastrawi; cukup dibuat sekali karena kamusnya besar
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{
+		Similarity: kimseok.JaroWinkler,
+		stemmer:    sastrawi.NewStemmer(sastrawi.DefaultDictionary()),
+		stopwords:  sastrawi.DefaultStopword(),
+	}
+}
+
+// Tokenize memecah teks menjadi kata huruf kecil; apostrof dibuang agar "don't" menjadi "dont"
+func Tokenize(text string) []string {
+	text = strings.NewReplacer("'", "", "’", "").Replace(text)
+	return sastrawi.Tokenize(text)
+}
+
+// DetectLanguage menebak bahasa teks dari kata penanda; teks yang tidak jelas dianggap Indonesia
+func DetectLanguage(tokens []string) string {
+	id, en := 0, 0
+	for _, t := range tokens {
+		if markersID[t] {
+			id++
+		}
+		if markersEN[t] {
+			en++
+		}
+	}
+	if en > id {
+		return i18n.EN
+	}
+	return i18n.Default
+}
+
+// Analyze menjalankan deteksi bahasa, sentimen berbasis leksikon, ekstraksi keyword dan normalisasi term
+func (a *Analyzer) Analyze(text string) Analysis {
+	tokens := Tokenize(text)
+	res := Analysis{Language: DetectLanguage(tokens), Sentiment: Neutral}
+
+	roots := make([]string, len(tokens))
+	for i, t := range tokens {
+		roots[i] = a.root(t, res.Language)
+	}
+
+	res.Score = sentiment(tokens, roots)
+	switch {
+	case res.Score > 0.2:
+		res.Sentiment = Positive
+	case res.Score < -0.2:
+		res.Sentiment = Negative
+	}
+
+	counts := map[string]int{}
+	for i, t := range tokens {
+		if len(t) < 3 || a.stopwords.Contains(t) || stopwordsExtra[t] || negators[t] {
+			continue
+		}
+		res.Terms = append(res.Terms, roots[i])
+		counts[roots[i]]++
+	}
+	res.Keywords = topKeywords(counts, MaxKeywords)
+	return res
+}
+
+// root mengembalikan bentuk dasar token: stem Sastrawi untuk teks Indonesia, token apa adanya untuk Inggris
+// dan untuk kata yang sudah ada di leksikon (mis. "lemot" yang salah di-stem menjadi "lot")
+func (a *Analyzer) root(token, lang string) string {
+	if lang != i18n.ID || lexiconID[token] != 0 || functionWords[token] {
+		return token
+	}
+	return a.stemmer.Stem(token)
+}
+
+// sentiment menghitung (positif - negatif) / (positif + negatif) dengan memperhitungkan negasi
+func sentiment(tokens, roots []string) float64 {
+	pos, neg := 0, 0
+	negateUntil := -1
+	for i, t := range tokens {
+		if negators[t] {
+			negateUntil = i + negationWindow
+			continue
+		}
+		polarity, known := lexiconEN[t]
+		if !known {
+			polarity, known = lexiconID[roots[i]]
+		}
+		if !known && !functionWords[t] && !functionWords[roots[i]] {
+			continue
+		}
+		if i <= negateUntil {
+			// "tidak bagus" menjadi negatif, "tidak bisa" (kata fungsi) juga negatif
+			polarity = -polarity
+			if !known {
+				polarity = -1
+			}
+			negateUntil = -1
+		}
+		switch {
+		case polarity > 0:
+			pos++
+		case polarity < 0:
+			neg++
+		}
+	}
+	if pos+neg == 0 {
+		return 0
+	}
+	return float64(pos-neg) / float64(pos+neg)
+}
+
+// topKeywords mengambil n kata dengan frekuensi tertinggi, seri diurutkan alfabetis agar stabil
+func topKeywords(counts map[string]int, n int) []string {
+	words := make([]string, 0, len(counts))
+	for w := range counts {
+		words = append(words, w)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if counts[words[i]] != counts[words[j]] {
+			return counts[words[i]] > counts[words[j]]
+		}
+		return words[i] < words[j]
+	})
+	if len(words) > n {
+		words = words[:n]
+	}
+	return words
+}
@@ -0,0 +1,67 @@
+package triage
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gocroot/helper/i18n"
+)
+
+var analyzer = NewAnalyzer()
+
+func TestAnalyzeSentimentAndLanguage(t *testing.T) {
+	cases := []struct {
+		text, lang, sentiment string
+	}{
+		{"Aplikasinya bagus banget, sangat membantu tugas kuliah saya", i18n.ID, Positive},
+		{"Fitur gabung PDF tidak bisa dipakai, selalu error", i18n.ID, Negative},
+		{"Hasilnya kurang bagus dan lemot", i18n.ID, Negative},
+		{"Tolong tambahkan fitur tanda tangan digital", i18n.ID, Neutral},
+		{"Great app, very helpful and fast!", i18n.EN, Positive},
+		{"The compress tool doesn't work, my file is corrupted", i18n.EN, Negative},
+		{"It is not bad at all", i18n.EN, Positive},
+	}
+	for _, c := range cases {
+		got := analyzer.Analyze(c.text)
+		if got.Language != c.lang || got.Sentiment != c.sentiment {
+			t.Errorf("Analyze(%q) = %s/%s (%.2f), want %s/%s", c.text, got.Language, got.Sentiment, got.Score, c.lang, c.sentiment)
+		}
+	}
+}
+
+func TestKeywordsUseSastrawiStem(t *testing.T) {
+	got := analyzer.Analyze("Pembayaran donasi gagal, pembayaran kedua juga gagal").Keywords
+	want := []string{"bayar", "gagal", "donasi"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Keywords = %v, want %v", got, want)
+	}
+}
+
+func TestNearest(t *testing.T) {
+	corpus := []Document{
+		{ID: "a", Terms: analyzer.Analyze("Fitur gabung PDF tidak bisa dipakai, selalu error saat upload").Terms},
+		{ID: "b", Cluster: "a", Terms: analyzer.Analyze("Tolong tambahkan fitur tanda tangan digital").Terms},
+		{ID: "c", Terms: analyzer.Analyze("Aplikasi ini sangat mantap!").Terms},
+	}
+	dup := analyzer.Analyze("gabung pdf error terus waktu upload file, tidak bisa dipakai")
+	best, ok := analyzer.Nearest(dup.Terms, corpus, DuplicateThreshold)
+	if !ok || best.ID != "a" || best.ClusterOf() != "a" {
+		t.Errorf("near-duplicate tidak ditemukan: %+v ok=%v", best, ok)
+	}
+	other := analyzer.Analyze("Pembayaran donasi saya gagal padahal saldo terpotong")
+	if best, ok := analyzer.Nearest(other.Terms, corpus, DuplicateThreshold); ok {
+		t.Errorf("feedback berbeda dianggap duplikat dari %s (%.2f)", best.ID, best.Score)
+	}
+	if (Document{ID: "b", Cluster: "a"}).ClusterOf() != "a" {
+		t.Error("ClusterOf harus mengikuti cluster")
+	}
+}
+
+func TestSimilarityInjectable(t *testing.T) {
+	a := NewAnalyzer()
+	a.Similarity = func(x, y string) float64 { return 1 }
+	best, ok := a.Nearest([]string{"x"}, []Document{{ID: "z", Terms: []string{"y"}}}, 0.5)
+	if !ok || best.Score != 0.5 {
+		t.Errorf("Similarity kustom tidak dipakai: %+v", best)
+	}
+}
@@ -0,0 +1,337 @@
+package watoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+)
+
+// Status key di keyring
+const (
+	KeyActive   = "active"   // dipakai menandatangani token baru; tepat satu per keyring
+	KeyRetiring = "retiring" // hanya memverifikasi token lama sampai RetireAt
+)
+
+var (
+	ErrNoActiveKey = errors.New("keyring tidak memiliki key aktif")
+	ErrUnknownKey  = errors.New("kid token tidak dikenal")
+	ErrKeyRetired  = errors.New("key token sudah pensiun")
+	ErrAudience    = errors.New("audience token tidak sesuai")
+	ErrScope       = errors.New("token tidak memiliki scope yang dibutuhkan")
+	ErrRevoked     = errors.New("token sudah dicabut")
+)
+
+// Key adalah satu pasang kunci PASETO v4 public. PrivateKey boleh kosong untuk key yang hanya
+// dipakai verifikasi (mis. kunci publik server WhatsAuth).
+type Key struct {
+	ID         string    `json:"kid"`
+	PublicKey  string    `json:"public_key"`
+	PrivateKey string    `json:"private_key,omitempty"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	RetireAt   time.Time `json:"retire_at,omitempty"` // kosong berarti tidak pernah pensiun
+}
+
+// Keyring menyimpan key aktif dan key yang sedang pensiun. Formatnya JSON sehingga bisa disimpan
+// di environment (WATOKEN_KEYRING) atau secret manager.
+type Keyring struct {
+	Keys []Key `json:"keys"`
+}
+
+// Claims adalah isi token yang ditandatangani Keyring.Sign
+type Claims struct {
+	ID       string // nomor telepon atau ID user, sama seperti Payload.Id
+	Alias    string
+	Audience string   // aplikasi tujuan token, mis. "pdfm" atau "domyikado"
+	Scopes   []string // izin tambahan; kosong berarti tanpa batasan scope
+	TokenID  string   // jti untuk pencabutan; dibuat otomatis jika kosong
+	Duration time.Duration
+	Data     interface{}
+}
+
+// VerifyOptions mengatur pemeriksaan tambahan di Keyring.Verify
+type VerifyOptions struct {
+	Audience        string                // jika diisi, aud token harus sama
+	AllowNoAudience bool                  // tetap menerima token lama tanpa aud walaupun Audience diisi
+	Scope           string                // jika diisi, token harus memiliki scope ini
+	Revoked         func(jti string) bool // mengembalikan true jika token sudah dicabut
+	IgnoreExpiry    bool                  // hanya memeriksa tanda tangan, mis. saat mencatat pencabutan
+	Now             time.Time
+}
+
+type footer struct {
+	Kid string `json:"kid"`
+}
+
+// NewKey membuat key aktif baru. kid berisi tanggal pembuatan dan sidik jari kunci publik.
+func NewKey(now time.Time) Key {
+	secret := paseto.NewV4AsymmetricSecretKey()
+	public := secret.Public().ExportHex()
+	sum := sha256.Sum256([]byte(public))
+	return Key{
+		ID:         "k" + now.UTC().Format("20060102") + "-" + hex.EncodeToString(sum[:4]),
+		PublicKey:  public,
+		PrivateKey: secret.ExportHex(),
+		Status:     KeyActive,
+		CreatedAt:  now,
+	}
+}
+
+// KeyFromPrivate membuat key dari private key hex yang sudah ada, mis. PRKEY sebelum keyring dipakai
+func KeyFromPrivate(id, privateKey string, now time.Time) (Key, error) {
+	secret, err := paseto.NewV4AsymmetricSecretKeyFromHex(privateKey)
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{ID: id, PublicKey: secret.Public().ExportHex(), PrivateKey: privateKey, Status: KeyActive, CreatedAt: now}, nil
+}
+
+// ParseKeyring membaca keyring JSON dan memvalidasi setiap key
+func ParseKeyring(data []byte) (*Keyring, error) {
+	var ring Keyring
+	if err := json.Unmarshal(data, &ring); err != nil {
+		return nil, err
+	}
+	return &ring, ring.Validate()
+}
+
+// Validate memastikan kid unik, setiap kunci valid dan ada tepat satu key aktif yang punya private key
+func (k *Keyring) Validate() error {
+	seen := map[string]bool{}
+	active := 0
+	for _, key := range k.Keys {
+		if key.ID == "" || seen[key.ID] {
+			return errors.New("kid kosong atau duplikat: " + key.ID)
+		}
+		seen[key.ID] = true
+		if _, err := paseto.NewV4AsymmetricPublicKeyFromHex(key.PublicKey); err != nil {
+			return errors.New("public key " + key.ID + " tidak valid")
+		}
+		switch key.Status {
+		case KeyActive:
+			if _, err := paseto.NewV4AsymmetricSecretKeyFromHex(key.PrivateKey); err != nil {
+				return errors.New("private key " + key.ID + " tidak valid")
+			}
+			active++
+		case KeyRetiring:
+		default:
+			return errors.New("status key " + key.ID + " tidak dikenal: " + key.Status)
+		}
+	}
+	if active != 1 {
+		return ErrNoActiveKey
+	}
+	return nil
+}
+
+// Active mengembalikan key untuk menandatangani token baru
+func (k *Keyring) Active() (Key, error) {
+	for _, key := range k.Keys {
+		if key.Status == KeyActive {
+			return key, nil
+		}
+	}
+	return Key{}, ErrNoActiveKey
+}
+
+// WithVerifyKey mengembalikan salinan keyring dengan tambahan key yang hanya untuk verifikasi
+func (k *Keyring) WithVerifyKey(id, publicKey string) *Keyring {
+	ring := &Keyring{Keys: append([]Key{}, k.Keys...)}
+	if publicKey != "" {
+		ring.Keys = append(ring.Keys, Key{ID: id, PublicKey: publicKey, Status: KeyRetiring})
+	}
+	return ring
+}
+
+// Rotate membuat key aktif baru. Key aktif lama menjadi retiring dan masih memverifikasi token
+// sampai retireAfter berlalu (isi dengan masa berlaku token terpanjang). Key retiring yang sudah
+// lewat RetireAt dibuang.
+func (k *Keyring) Rotate(now time.Time, retireAfter time.Duration) Key {
+	next := NewKey(now)
+	keys := []Key{next}
+	for _, key := range k.Keys {
+		if key.Status == KeyActive {
+			key.Status = KeyRetiring
+			key.RetireAt = now.Add(retireAfter)
+		}
+		if !key.RetireAt.IsZero() && !now.Before(key.RetireAt) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	k.Keys = keys
+	return next
+}
+
+func (k *Keyring) find(kid string) (Key, bool) {
+	for _, key := range k.Keys {
+		if key.ID == kid {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// Sign menandatangani token dengan key aktif. kid ditaruh di footer sehingga verifikasi tahu key mana
+// yang dipakai; footer ikut ditandatangani.
+func (k *Keyring) Sign(c Claims, now time.Time) (token string, jti string, err error) {
+	key, err := k.Active()
+	if err != nil {
+		return "", "", err
+	}
+	secret, err := paseto.NewV4AsymmetricSecretKeyFromHex(key.PrivateKey)
+	if err != nil {
+		return "", "", err
+	}
+	if c.Duration <= 0 {
+		c.Duration = 2 * time.Hour
+	}
+	if c.TokenID == "" {
+		raw := make([]byte, 16)
+		if _, err := rand.Read(raw); err != nil {
+			return "", "", err
+		}
+		c.TokenID = hex.EncodeToString(raw)
+	}
+
+	t := paseto.NewToken()
+	t.SetIssuedAt(now)
+	t.SetNotBefore(now)
+	t.SetExpiration(now.Add(c.Duration))
+	t.SetJti(c.TokenID)
+	t.SetString("id", c.ID)
+	if c.Alias != "" {
+		t.SetString("alias", c.Alias)
+	}
+	if c.Audience != "" {
+		t.SetAudience(c.Audience)
+	}
+	if len(c.Scopes) > 0 {
+		scopes := append([]string{}, c.Scopes...)
+		sort.Strings(scopes)
+		if err := t.Set("scope", scopes); err != nil {
+			return "", "", err
+		}
+	}
+	if c.Data != nil {
+		if err := t.Set("data", c.Data); err != nil {
+			return "", "", err
+		}
+	}
+	f, _ := json.Marshal(footer{Kid: key.ID})
+	t.SetFooter(f)
+	return t.V4Sign(secret, nil), c.TokenID, nil
+}
+
+// Verify memeriksa token dengan key sesuai kid di footer. Token lama tanpa kid dicoba dengan semua key
+// di keyring.
+func (k *Keyring) Verify(tokenstring string, opt VerifyOptions) (payload Payload[any], err error) {
+	if opt.Now.IsZero() {
+		opt.Now = time.Now()
+	}
+	parser := paseto.NewParserWithoutExpiryCheck()
+	if !opt.IgnoreExpiry {
+		parser.AddRule(paseto.ValidAt(opt.Now))
+	}
+
+	candidates := k.Keys
+	var f footer
+	if raw, ferr := parser.UnsafeParseFooter(paseto.V4Public, tokenstring); ferr == nil && len(raw) > 0 {
+		if json.Unmarshal(raw, &f) != nil || f.Kid == "" {
+			return payload, ErrUnknownKey
+		}
+		key, ok := k.find(f.Kid)
+		if !ok {
+			return payload, ErrUnknownKey
+		}
+		candidates = []Key{key}
+	}
+
+	var token *paseto.Token
+	err = ErrUnknownKey
+	for _, key := range candidates {
+		if !key.RetireAt.IsZero() && !opt.Now.Before(key.RetireAt) {
+			err = ErrKeyRetired
+			continue
+		}
+		pub, perr := paseto.NewV4AsymmetricPublicKeyFromHex(key.PublicKey)
+		if perr != nil {
+			continue
+		}
+		if token, err = parser.ParseV4Public(pub, tokenstring, nil); err == nil {
+			payload.Kid = key.ID
+			break
+		}
+	}
+	if err != nil {
+		return payload, err
+	}
+
+	kid := payload.Kid
+	if err = json.Unmarshal(token.ClaimsJSON(), &payload); err != nil {
+		return payload, err
+	}
+	payload.Kid = kid
+	if opt.Audience != "" && payload.Aud != opt.Audience && !(opt.AllowNoAudience && payload.Aud == "") {
+		return payload, ErrAudience
+	}
+	if opt.Scope != "" && !hasScope(payload.Scope, opt.Scope) {
+		return payload, ErrScope
+	}
+	if opt.Revoked != nil && payload.Jti != "" && opt.Revoked(payload.Jti) {
+		return payload, ErrRevoked
+	}
+	return payload, nil
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// RevocationList menyimpan jti token yang dicabut di memori sampai token tersebut kedaluwarsa
+type RevocationList struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+}
+
+func NewRevocationList() *RevocationList {
+	return &RevocationList{revoked: map[string]time.Time{}}
+}
+
+// Revoke mencabut token jti. until adalah waktu kedaluwarsa token; setelah itu entri boleh dibuang.
+func (l *RevocationList) Revoke(jti string, until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.revoked[jti] = until
+}
+
+// Revoked bernilai true jika jti ada di daftar dan belum lewat masa berlakunya
+func (l *RevocationList) Revoked(jti string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	until, ok := l.revoked[jti]
+	return ok && (until.IsZero() || time.Now().Before(until))
+}
+
+// Prune membuang entri yang token-nya sudah kedaluwarsa
+func (l *RevocationList) Prune(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for jti, until := range l.revoked {
+		if !until.IsZero() && !now.Before(until) {
+			delete(l.revoked, jti)
+		}
+	}
+}
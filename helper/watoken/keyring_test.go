@@ -0,0 +1,104 @@
+package watoken
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestKeyringSignVerify(t *testing.T) {
+	now := time.Now()
+	ring := &Keyring{Keys: []Key{NewKey(now)}}
+	if err := ring.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	token, jti, err := ring.Sign(Claims{ID: "6281234", Alias: "Pipo", Audience: "pdfm", Scopes: []string{"merge"}}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := ring.Verify(token, VerifyOptions{Audience: "pdfm", Scope: "merge"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload.Id != "6281234" || payload.Alias != "Pipo" || payload.Jti != jti || payload.Kid != ring.Keys[0].ID {
+		t.Errorf("payload = %+v", payload)
+	}
+
+	if _, err := ring.Verify(token, VerifyOptions{Audience: "domyikado"}); err != ErrAudience {
+		t.Errorf("audience lain err = %v", err)
+	}
+	if _, err := ring.Verify(token, VerifyOptions{Scope: "compress"}); err != ErrScope {
+		t.Errorf("scope lain err = %v", err)
+	}
+	revoked := NewRevocationList()
+	revoked.Revoke(jti, now.Add(time.Hour))
+	if _, err := ring.Verify(token, VerifyOptions{Revoked: revoked.Revoked}); err != ErrRevoked {
+		t.Errorf("token dicabut err = %v", err)
+	}
+	revoked.Prune(now.Add(2 * time.Hour))
+	if revoked.Revoked(jti) {
+		t.Error("entri kedaluwarsa harus dibuang Prune")
+	}
+	if _, err := ring.Verify(token, VerifyOptions{Now: now.Add(3 * time.Hour)}); err == nil {
+		t.Error("token kedaluwarsa harus ditolak")
+	}
+	if _, err := ring.Verify(token, VerifyOptions{Now: now.Add(3 * time.Hour), IgnoreExpiry: true}); err != nil {
+		t.Errorf("IgnoreExpiry: %v", err)
+	}
+
+	other := &Keyring{Keys: []Key{NewKey(now)}}
+	other.Keys[0].ID = ring.Keys[0].ID
+	if _, err := other.Verify(token, VerifyOptions{}); err == nil {
+		t.Error("token dengan key lain harus ditolak walaupun kid sama")
+	}
+}
+
+func TestKeyringRotate(t *testing.T) {
+	now := time.Now()
+	ring := &Keyring{Keys: []Key{NewKey(now.Add(-time.Hour))}}
+	oldToken, _, _ := ring.Sign(Claims{ID: "1", Duration: 48 * time.Hour}, now)
+
+	next := ring.Rotate(now, 24*time.Hour)
+	if err := ring.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if active, _ := ring.Active(); active.ID != next.ID || len(ring.Keys) != 2 {
+		t.Fatalf("setelah rotasi: %+v", ring.Keys)
+	}
+	if _, err := ring.Verify(oldToken, VerifyOptions{}); err != nil {
+		t.Errorf("token key lama harus tetap valid selama retiring: %v", err)
+	}
+	if _, err := ring.Verify(oldToken, VerifyOptions{Now: now.Add(25 * time.Hour)}); err != ErrKeyRetired {
+		t.Errorf("token key pensiun err = %v", err)
+	}
+	newToken, _, _ := ring.Sign(Claims{ID: "2"}, now)
+	if payload, err := ring.Verify(newToken, VerifyOptions{}); err != nil || payload.Kid != next.ID {
+		t.Errorf("token baru = %+v, %v", payload, err)
+	}
+
+	ring.Rotate(now.Add(25*time.Hour), 24*time.Hour)
+	if len(ring.Keys) != 2 {
+		t.Errorf("key yang sudah pensiun harus dibuang, sisa %d", len(ring.Keys))
+	}
+
+	data, _ := json.Marshal(ring)
+	if _, err := ParseKeyring(data); err != nil {
+		t.Errorf("ParseKeyring: %v", err)
+	}
+}
+
+func TestKeyringLegacyToken(t *testing.T) {
+	privateKey, publicKey := GenerateKey()
+	legacy, _ := EncodeforHours("6281234", "Pipo", privateKey, 1)
+	ring := (&Keyring{Keys: []Key{NewKey(time.Now())}}).WithVerifyKey("whatsauth", publicKey)
+	payload, err := ring.Verify(legacy, VerifyOptions{})
+	if err != nil || payload.Id != "6281234" || payload.Kid != "whatsauth" {
+		t.Errorf("token lama = %+v, %v", payload, err)
+	}
+	if _, err := ring.Verify(legacy, VerifyOptions{Audience: "pdfm"}); err != ErrAudience {
+		t.Errorf("token lama tanpa aud err = %v", err)
+	}
+	if _, err := ring.Verify(legacy, VerifyOptions{Audience: "pdfm", AllowNoAudience: true}); err != nil {
+		t.Errorf("AllowNoAudience: %v", err)
+	}
+}
@@ -14,6 +14,11 @@ type Payload[T any] struct {
 	Iat   time.Time `json:"iat"`
 	Nbf   time.Time `json:"nbf"`
 	Data  T         `json:"data"`
+	// Klaim token dari Keyring.Sign; kosong untuk token lama
+	Jti   string   `json:"jti,omitempty"`
+	Aud   string   `json:"aud,omitempty"`
+	Scope []string `json:"scope,omitempty"`
+	Kid   string   `json:"-"` // key yang memverifikasi token
 }
 
 func GenerateKey() (privateKey, publicKey string) {
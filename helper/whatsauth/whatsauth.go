@@ -123,7 +123,7 @@ func HandlerIncomingMessage(msg itmodel.IteungMessage, profile itmodel.Profile,
 		IsGroup:  isgrup,
 		Messages: msgstr,
 	}
-	_, resp, err = atapi.PostStructWithToken[itmodel.Response]("Token", profile.Token, dt, profile.URLAPIText)
+	_, resp, err = atapi.PostWhatsApp(profile, dt, profile.URLAPIText)
 	if err != nil {
 		return
 	}
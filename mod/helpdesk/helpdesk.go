@@ -74,7 +74,7 @@ func HelpdeskPDLMS(Profile itmodel.Profile, Pesan itmodel.IteungMessage, db *mon
 		IsGroup:  false,
 		Messages: msgstr,
 	}
-	go atapi.PostStructWithToken[itmodel.Response]("Token", Profile.Token, dt, Profile.URLAPIText)
+	go atapi.PostWhatsApp(Profile, dt, Profile.URLAPIText)
 	//pesan ke user
 	reply = GetPrefillMessage("userbantuanadmin", db) //pesan ke user
 	reply = fmt.Sprintf(reply, helpdeskname)
@@ -138,7 +138,7 @@ func HelpdeskPusat(Profile itmodel.Profile, Pesan itmodel.IteungMessage, db *mon
 		IsGroup:  false,
 		Messages: msgstr,
 	}
-	go atapi.PostStructWithToken[itmodel.Response]("Token", Profile.Token, dt, Profile.URLAPIText)
+	go atapi.PostWhatsApp(Profile, dt, Profile.URLAPIText)
 	//pesan untuk user
 	reply = GetPrefillMessage("userbantuanadmin", db)
 	reply = fmt.Sprintf(reply, op.Name)
@@ -185,7 +185,7 @@ func AdminNotFoundWithProvinsi(Profile itmodel.Profile, Pesan itmodel.IteungMess
 		IsGroup:  false,
 		Messages: msgstr,
 	}
-	go atapi.PostStructWithToken[itmodel.Response]("Token", Profile.Token, dt, Profile.URLAPIText)
+	go atapi.PostWhatsApp(Profile, dt, Profile.URLAPIText)
 	reply = GetPrefillMessage("userbantuanadmin", db) //pesan untuk user
 	reply = fmt.Sprintf(reply, op.Name)
 	//insert ke database dan set hub session
@@ -265,7 +265,7 @@ func EndHelpdesk(Profile itmodel.Profile, Pesan itmodel.IteungMessage, db *mongo
 		IsGroup:  false,
 		Messages: sendmsg,
 	}
-	go atapi.PostStructWithToken[itmodel.Response]("Token", Profile.Token, dt, Profile.URLAPIText)
+	go atapi.PostWhatsApp(Profile, dt, Profile.URLAPIText)
 
 	return
 }
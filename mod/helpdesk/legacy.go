@@ -32,7 +32,7 @@ func StartHelpdesk(Profile itmodel.Profile, Pesan itmodel.IteungMessage, db *mon
 			IsGroup:  false,
 			Messages: msgstr,
 		}
-		go atapi.PostStructWithToken[itmodel.Response]("Token", Profile.Token, dt, Profile.URLAPIText)
+		go atapi.PostWhatsApp(Profile, dt, Profile.URLAPIText)
 		reply = "Segera, Bapak/Ibu akan dihubungkan dengan salah satu Admin kami, *" + user.User.Name + "*.\n\n Mohon tunggu sebentar, kami akan menghubungi Anda melalui WhatsApp di nomor wa.me/" + user.User.PhoneNumber + "\nTerima kasih atas kesabaran Bapak/Ibu"
 		//reply = "Kakak kami hubungkan dengan operator kami yang bernama *" + user.User.Name + "* di nomor wa.me/" + user.User.PhoneNumber + "\nMohon tunggu sebentar kami akan kontak kakak melalui nomor tersebut.\n_Terima kasih_"
 		return
@@ -122,7 +122,7 @@ func FeedbackHelpdesk(Profile itmodel.Profile, Pesan itmodel.IteungMessage, db *
 		IsGroup:  false,
 		Messages: msgstr,
 	}
-	go atapi.PostStructWithToken[itmodel.Response]("Token", Profile.Token, dt, Profile.URLAPIText)
+	go atapi.PostWhatsApp(Profile, dt, Profile.URLAPIText)
 
 	return
 }
@@ -175,7 +175,7 @@ func PenugasanOperator(Profile itmodel.Profile, Pesan itmodel.IteungMessage, db
 			IsGroup:  false,
 			Messages: msgstr,
 		}
-		go atapi.PostStructWithToken[itmodel.Response]("Token", Profile.Token, dt, Profile.URLAPIText)
+		go atapi.PostWhatsApp(Profile, dt, Profile.URLAPIText)
 
 		reply = "Segera, Bapak/Ibu akan dihubungkan dengan salah satu Admin kami, *" + user.User.Name + "*.\n\n Mohon tunggu sebentar, kami akan menghubungi Anda melalui WhatsApp di nomor wa.me/" + user.User.PhoneNumber + "\nTerima kasih atas kesabaran Bapak/Ibu"
 
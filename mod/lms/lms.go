@@ -20,7 +20,7 @@ func ReplyRekapUsers(Profile itmodel.Profile, Pesan itmodel.IteungMessage, db *m
 		IsGroup:  Pesan.Is_group,
 		Messages: msgstr,
 	}
-	go atapi.PostStructWithToken[itmodel.Response]("Token", Profile.Token, dt, Profile.URLAPIText)
+	go atapi.PostWhatsApp(Profile, dt, Profile.URLAPIText)
 
 	//lanjutkan rekap
 	rkp, err := GetRekapPendaftaranUsers(db)
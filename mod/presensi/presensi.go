@@ -44,7 +44,7 @@ func CekSelfiePulang(Profile itmodel.Profile, Pesan itmodel.IteungMessage, db *m
 				Caption:     faceinfo.Error,
 				IsGroup:     Pesan.Is_group,
 			}
-			statuscode, httpresp, err := atapi.PostStructWithToken[itmodel.Response]("Token", Profile.Token, dt, Profile.URLAPIImage)
+			statuscode, httpresp, err := atapi.PostWhatsApp(Profile, dt, Profile.URLAPIImage)
 			if err != nil {
 				strconv.Itoa(statuscode)
 				return "Akses ke endpoint whatsaut gagal: " + err.Error() + strconv.Itoa(statuscode) + httpresp.Info + httpresp.Response
@@ -137,7 +137,7 @@ func CekSelfieMasuk(Profile itmodel.Profile, Pesan itmodel.IteungMessage, db *mo
 				Caption:     faceinfo.Error,
 				IsGroup:     Pesan.Is_group,
 			}
-			statuscode, httpresp, err := atapi.PostStructWithToken[itmodel.Response]("Token", Profile.Token, dt, Profile.URLAPIImage)
+			statuscode, httpresp, err := atapi.PostWhatsApp(Profile, dt, Profile.URLAPIImage)
 			if err != nil {
 				strconv.Itoa(statuscode)
 				return "Akses ke endpoint whatsaut gagal: " + err.Error() + strconv.Itoa(statuscode) + httpresp.Info + httpresp.Response
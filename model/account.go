@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// AccountDeletionInput mengonfirmasi permintaan hapus akun dengan password saat ini
+type AccountDeletionInput struct {
+	Password string `json:"password" example:"rahasia123"`
+}
+
+type AccountDeletionResponse struct {
+	Status      int       `json:"status" example:"202"`
+	Code        string    `json:"code,omitempty" example:"account_deletion_scheduled"`
+	Message     string    `json:"message" example:"Akun akan dihapus permanen pada 2026-01-15"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+// AccountPurgeResult adalah hasil satu kali jalan penghapusan akun yang masa tenggangnya sudah lewat
+type AccountPurgeResult struct {
+	Status   int             `json:"status" example:"200"`
+	Message  string          `json:"message" example:"Penghapusan akun selesai"`
+	DryRun   bool            `json:"dry_run"`
+	Accounts int             `json:"accounts" example:"2"`
+	Failed   []string        `json:"failed,omitempty"` // ID user yang gagal dihapus, dicoba lagi di jalan berikutnya
+	Items    []RetentionItem `json:"items"`
+}
+
+// ExportManifest adalah isi manifest.json di zip ekspor data personal
+type ExportManifest struct {
+	UserID      string           `json:"user_id"`
+	Email       string           `json:"email"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	Files       map[string]int64 `json:"files"` // nama file di zip -> jumlah entri
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// AdminUser adalah data user untuk panel admin (tanpa password) beserta waktu login terakhir
+type AdminUser struct {
+	PdfmUsers   `bson:",inline"`
+	LastLoginAt time.Time `bson:"lastLoginAt,omitempty" json:"lastLoginAt,omitempty"`
+}
+
+// UserSummary adalah ringkasan aktivitas satu user untuk admin
+type UserSummary struct {
+	Invoices      int64            `json:"invoices" example:"3"`
+	InvoiceTotal  int64            `json:"invoice_total" example:"150000"` // total amount invoice berstatus Paid
+	LastInvoiceAt time.Time        `json:"last_invoice_at,omitempty"`
+	History       map[string]int64 `json:"history"` // jumlah activity_log per tipe
+	HistoryTotal  int64            `json:"history_total" example:"42"`
+	Feedback      int64            `json:"feedback" example:"1"`
+	Logins30d     int64            `json:"logins_30d" example:"12"`
+	LastLogin     *LoginLog        `json:"last_login,omitempty"`
+}
+
+type AdminUserListResponse struct {
+	Status     int         `json:"status" example:"200"`
+	Message    string      `json:"message" example:"Users retrieved successfully"`
+	Users      []AdminUser `json:"users"`
+	Total      int64       `json:"total" example:"120"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	HasMore    bool        `json:"has_more"`
+}
+
+type AdminUserDetailResponse struct {
+	Status  int         `json:"status" example:"200"`
+	Message string      `json:"message" example:"User retrieved successfully"`
+	User    AdminUser   `json:"user"`
+	Summary UserSummary `json:"summary"`
+}
+
+// SuspendUserInput berisi alasan penangguhan yang disimpan untuk catatan admin
+type SuspendUserInput struct {
+	Reason string `json:"reason" example:"Penyalahgunaan layanan"`
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Announcement adalah pengumuman admin yang disebar ke notifikasi in-app para user
+type Announcement struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Title     string              `bson:"title" json:"title" example:"Fitur baru: Kompres PDF"`
+	Body      string              `bson:"body" json:"body" example:"Sekarang file PDF bisa dikompres hingga 70%."`
+	Link      string              `bson:"link,omitempty" json:"link,omitempty" example:"https://pdfmulbi.github.io/compress"`
+	Icon      string              `bson:"icon,omitempty" json:"icon,omitempty" example:"megaphone"`
+	Audience  string              `bson:"audience" json:"audience" example:"all"` // all, supporters, segment
+	Segment   AnnouncementSegment `bson:"segment,omitempty" json:"segment,omitempty"`
+	PublishAt time.Time           `bson:"publish_at" json:"publish_at"`
+	ExpiresAt time.Time           `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	// Status: scheduled, publishing, published, expired, withdrawn
+	Status      string             `bson:"status" json:"status" example:"scheduled"`
+	Recipients  int64              `bson:"recipients" json:"recipients" example:"0"`
+	ReadCount   int64              `bson:"-" json:"read_count" example:"0"`
+	CreatedBy   primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	PublishedAt time.Time          `bson:"published_at,omitempty" json:"published_at,omitempty"`
+}
+
+// AnnouncementSegment menyaring user penerima jika Audience = segment; semua kriteria yang diisi harus terpenuhi
+type AnnouncementSegment struct {
+	UserIDs          []primitive.ObjectID `bson:"user_ids,omitempty" json:"user_ids,omitempty"`
+	Emails           []string             `bson:"emails,omitempty" json:"emails,omitempty"`
+	Supporter        *bool                `bson:"supporter,omitempty" json:"supporter,omitempty"`
+	RegisteredAfter  time.Time            `bson:"registered_after,omitempty" json:"registered_after,omitempty"`
+	RegisteredBefore time.Time            `bson:"registered_before,omitempty" json:"registered_before,omitempty"`
+}
+
+type AnnouncementResponse struct {
+	Status       int          `json:"status" example:"200"`
+	Message      string       `json:"message" example:"Pengumuman dijadwalkan"`
+	Announcement Announcement `json:"announcement"`
+}
+
+type AnnouncementListResponse struct {
+	Status        int            `json:"status" example:"200"`
+	Message       string         `json:"message" example:"Announcements retrieved successfully"`
+	Announcements []Announcement `json:"announcements"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKey adalah personal API key untuk memanggil tool pdfm tanpa login browser.
+// Key asli tidak pernah disimpan; yang disimpan hanya hash sha256 dan awalannya.
+type APIKey struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID `bson:"user_id" json:"-"`
+	Name       string             `bson:"name" json:"name" example:"Script backup"`
+	Prefix     string             `bson:"prefix" json:"prefix" example:"pdfm_k3xq2a"`
+	Hash       string             `bson:"hash" json:"-"`
+	Scopes     []string           `bson:"scopes" json:"scopes" example:"merge,compress,files"`
+	RateLimit  int                `bson:"rate_limit" json:"rate_limit" example:"60"` // request per menit
+	UsageCount int64              `bson:"usage_count" json:"usage_count" example:"120"`
+	LastUsedAt time.Time          `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	LastUsedIP string             `bson:"last_used_ip,omitempty" json:"last_used_ip,omitempty"`
+	ExpiresAt  time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	RevokedAt  time.Time          `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// APIKeyUsage adalah jumlah pemakaian satu key dalam satu hari (zona Asia/Jakarta)
+type APIKeyUsage struct {
+	KeyID   primitive.ObjectID `bson:"key_id" json:"-"`
+	UserID  primitive.ObjectID `bson:"user_id" json:"-"`
+	Day     string             `bson:"day" json:"day" example:"2026-01-15"`
+	Total   int64              `bson:"total" json:"total" example:"42"`
+	Limited int64              `bson:"limited" json:"limited" example:"0"` // request yang ditolak rate limit
+	Scopes  map[string]int64   `bson:"scopes" json:"scopes"`
+}
+
+type APIKeyInput struct {
+	Name          string   `json:"name" example:"Script backup"`
+	Scopes        []string `json:"scopes" example:"merge,compress,files"`
+	RateLimit     int      `json:"rate_limit,omitempty" example:"30"`
+	ExpiresInDays int      `json:"expires_in_days,omitempty" example:"90"` // 0 berarti tidak kedaluwarsa
+}
+
+type APIKeyCreatedResponse struct {
+	Status  int    `json:"status" example:"201"`
+	Message string `json:"message" example:"API key dibuat. Simpan key ini, key tidak akan ditampilkan lagi"`
+	Key     string `json:"key" example:"pdfm_k3xq2a..."`
+	APIKey  APIKey `json:"api_key"`
+}
+
+type APIKeyListResponse struct {
+	Status  int      `json:"status" example:"200"`
+	Message string   `json:"message" example:"API keys retrieved successfully"`
+	Keys    []APIKey `json:"keys"`
+	Scopes  []string `json:"scopes" example:"compress,convert,files,history,merge,summary"` // scope yang bisa dipilih
+}
+
+type APIKeyUsageResponse struct {
+	Status  int           `json:"status" example:"200"`
+	Message string        `json:"message" example:"Usage retrieved successfully"`
+	Key     APIKey        `json:"key"`
+	Days    []APIKeyUsage `json:"days"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuditLog adalah catatan aksi yang berkaitan dengan keamanan (koleksi audit_log), mis. perubahan
+// user oleh admin, pembayaran dan penghapusan data. Dokumen tidak pernah diubah setelah ditulis.
+type AuditLog struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Action     string             `bson:"action" json:"action" example:"admin.user.suspend"`
+	ActorID    primitive.ObjectID `bson:"actor_id,omitempty" json:"actor_id,omitempty"`
+	ActorEmail string             `bson:"actor_email,omitempty" json:"actor_email,omitempty"`
+	ActorType  string             `bson:"actor_type" json:"actor_type" example:"session"` // session, apikey, paseto, cron, anonymous
+	TargetType string             `bson:"target_type" json:"target_type" example:"user"`
+	TargetID   string             `bson:"target_id,omitempty" json:"target_id,omitempty"`
+	Details    map[string]any     `bson:"details,omitempty" json:"details,omitempty"`
+	IP         string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent  string             `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	RequestID  string             `bson:"request_id,omitempty" json:"request_id,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+type AuditLogListResponse struct {
+	Status     int        `json:"status" example:"200"`
+	Message    string     `json:"message" example:"Audit log retrieved successfully"`
+	Entries    []AuditLog `json:"entries"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	HasMore    bool       `json:"has_more"`
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FeedbackReply adalah satu balasan dalam thread feedback
+type FeedbackReply struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	AuthorID   primitive.ObjectID `bson:"author_id" json:"author_id"`
+	AuthorName string             `bson:"author_name" json:"author_name" example:"Admin PDFM"`
+	IsAdmin    bool               `bson:"is_admin" json:"is_admin"`
+	Message    string             `bson:"message" json:"message" example:"Terima kasih, bug sudah kami perbaiki."`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// FeedbackAttachment adalah metadata lampiran feedback; isi file ada di storage backend.
+// URL hanya diisi pada response untuk admin.
+type FeedbackAttachment struct {
+	FileID      string `bson:"file_id" json:"file_id" example:"65c..."`
+	Name        string `bson:"name" json:"name" example:"screenshot.png"`
+	ContentType string `bson:"content_type" json:"content_type" example:"image/png"`
+	Size        int64  `bson:"size" json:"size" example:"204800"`
+	URL         string `bson:"-" json:"url,omitempty" example:"/pdfm/feedback/65b.../attachments/65c..."`
+}
+
+// FeedbackTriage adalah hasil analisis otomatis feedback: bahasa, sentimen, keyword dan cluster near-duplicate
+type FeedbackTriage struct {
+	Language       string             `bson:"language" json:"language" example:"id"`
+	Sentiment      string             `bson:"sentiment" json:"sentiment" example:"negative"` // positive, neutral, negative
+	SentimentScore float64            `bson:"sentiment_score" json:"sentiment_score" example:"-1"`
+	Keywords       []string           `bson:"keywords" json:"keywords" example:"gabung,error"`
+	Terms          []string           `bson:"terms" json:"-"`
+	ClusterID      primitive.ObjectID `bson:"cluster_id" json:"cluster_id"`
+	DuplicateOf    primitive.ObjectID `bson:"duplicate_of,omitempty" json:"duplicate_of,omitempty"`
+	Similarity     float64            `bson:"similarity,omitempty" json:"similarity,omitempty" example:"0.82"`
+	AnalyzedAt     time.Time          `bson:"analyzed_at" json:"analyzed_at"`
+}
+
+// FeedbackCount adalah jumlah feedback per nilai satu field
+type FeedbackCount struct {
+	Value string `bson:"_id" json:"value" example:"negative"`
+	Count int64  `bson:"count" json:"count" example:"12"`
+}
+
+// FeedbackCluster adalah sekelompok feedback yang saling near-duplicate
+type FeedbackCluster struct {
+	ClusterID primitive.ObjectID   `bson:"_id" json:"cluster_id"`
+	Count     int64                `bson:"count" json:"count" example:"4"`
+	Sample    string               `bson:"sample" json:"sample" example:"Fitur gabung PDF error"`
+	Keywords  []string             `bson:"keywords" json:"keywords"`
+	Negative  int64                `bson:"negative" json:"negative" example:"3"`
+	Open      int64                `bson:"open" json:"open" example:"2"`
+	Latest    time.Time            `bson:"latest" json:"latest"`
+	Members   []primitive.ObjectID `bson:"members" json:"members"`
+}
+
+// FeedbackTriageSummary adalah tampilan agregat feedback untuk admin
+type FeedbackTriageSummary struct {
+	Total     int64             `json:"total" example:"120"`
+	Analyzed  int64             `json:"analyzed" example:"118"`
+	Sentiment []FeedbackCount   `json:"sentiment"`
+	Language  []FeedbackCount   `json:"language"`
+	Category  []FeedbackCount   `json:"category"`
+	Status    []FeedbackCount   `json:"status"`
+	Keywords  []FeedbackCount   `json:"keywords"`
+	Clusters  []FeedbackCluster `json:"clusters"`
+}
+
+type FeedbackTriageResponse struct {
+	Status  int                   `json:"status" example:"200"`
+	Message string                `json:"message" example:"Feedback triage retrieved successfully"`
+	Summary FeedbackTriageSummary `json:"summary"`
+}
+
+// FeedbackAnalyzeResult adalah hasil analisis ulang feedback lama
+type FeedbackAnalyzeResult struct {
+	Message  string `json:"message" example:"Analisis selesai"`
+	Analyzed int    `json:"analyzed" example:"25"`
+}
+
+// ContactInput adalah isi form kontak tanpa login. Website adalah honeypot: field tersembunyi
+// yang harus dibiarkan kosong, hanya bot yang mengisinya.
+type ContactInput struct {
+	Name     string `json:"name" example:"Budi"`
+	Email    string `json:"email" example:"budi@gmail.com"`
+	Message  string `json:"message" example:"Apakah pdfm bisa dipakai untuk instansi?"`
+	Category string `json:"category,omitempty" example:"feature"`
+	Website  string `json:"website,omitempty" example:""`
+	Captcha  string `json:"captcha" example:"0.AbCdEf..."`
+}
+
+// FeedbackUpdateInput mengubah tiket feedback; field kosong tidak diubah.
+// AssignedTo berisi ID admin, atau "none" untuk melepas penugasan.
+type FeedbackUpdateInput struct {
+	Status     string `json:"status,omitempty" example:"in_progress"`
+	Category   string `json:"category,omitempty" example:"bug"`
+	Priority   string `json:"priority,omitempty" example:"high"`
+	AssignedTo string `json:"assigned_to,omitempty" example:"65b..."`
+}
+
+// FeedbackReplyInput adalah isi balasan admin; Status opsional untuk sekaligus mengubah status tiket
+type FeedbackReplyInput struct {
+	Message string `json:"message" example:"Sudah kami perbaiki di versi terbaru."`
+	Status  string `json:"status,omitempty" example:"resolved"`
+}
+
+type FeedbackThreadResponse struct {
+	Status   int      `json:"status" example:"200"`
+	Message  string   `json:"message" example:"Feedback retrieved successfully"`
+	Feedback Feedback `json:"feedback"`
+}
+
+type FeedbackListResponse struct {
+	Status    int        `json:"status" example:"200"`
+	Message   string     `json:"message" example:"Feedback retrieved successfully"`
+	Feedbacks []Feedback `json:"feedbacks"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// HealthCheck adalah hasil satu pemeriksaan dependensi pada /readyz
+type HealthCheck struct {
+	Name      string  `json:"name" example:"mongo"`
+	Status    string  `json:"status" example:"ok"`
+	LatencyMS float64 `json:"latency_ms" example:"3.2"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// HealthResponse adalah respons /healthz dan /readyz. Status "ok" jika semua pemeriksaan lolos,
+// "fail" jika ada yang gagal.
+type HealthResponse struct {
+	Status    string        `json:"status" example:"ok"`
+	Checks    []HealthCheck `json:"checks,omitempty"`
+	Uptime    string        `json:"uptime,omitempty" example:"2h3m4s"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
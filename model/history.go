@@ -3,73 +3,52 @@ package model
 import (
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-type MergeHistory struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty" example:"65b..."`
-	UserID     primitive.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"`
-	InputFiles []string           `bson:"input_files" json:"input_files" example:"file1.pdf,file2.pdf"`
-	OutputFile string             `bson:"output_file" json:"output_file" example:"merged_result.pdf"`
-	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+// Activity adalah satu entri riwayat pemakaian tool pada koleksi activity_log.
+// Type menjadi diskriminator, Details berisi payload sesuai skema tool (lihat helper/activity).
+type Activity struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id" example:"65b..."`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Type      string             `bson:"type" json:"type" example:"merge"`
+	FileName  string             `bson:"file_name" json:"file_name" example:"merged_result.pdf"`
+	Details   bson.M             `bson:"details,omitempty" json:"details,omitempty"`
+	Inputs    []string           `bson:"inputs,omitempty" json:"inputs,omitempty"`     // ID file input di storage (untuk rerun)
+	Output    string             `bson:"output,omitempty" json:"output,omitempty"`     // ID file hasil di storage
+	RerunOf   primitive.ObjectID `bson:"rerun_of,omitempty" json:"rerun_of,omitempty"` // aktivitas asal jika hasil rerun
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
 }
 
-type CompressHistory struct {
-	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty" example:"65b..."`
-	UserID         primitive.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"`
-	FileName       string             `bson:"file_name" json:"file_name" example:"laporan_besar.pdf"`
-	OriginalSize   int64              `bson:"original_size" json:"original_size" example:"5000000"`
-	CompressedSize int64              `bson:"compressed_size" json:"compressed_size" example:"1500000"`
-	Status         string             `bson:"status" json:"status" example:"success"`
-	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+// Skema detail per tipe. Nama field bson sama dengan koleksi lama agar migrasi cukup decode ulang.
+type MergeDetails struct {
+	InputFiles []string `bson:"input_files" json:"input_files" example:"file1.pdf,file2.pdf"`
+	OutputFile string   `bson:"output_file" json:"output_file" example:"merged_result.pdf"`
 }
 
-type ConvertHistory struct {
-	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty" example:"65b..."`
-	UserID       primitive.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"`
-	FileName     string             `bson:"file_name" json:"file_name" example:"document.docx"`
-	SourceFormat string             `bson:"source_format" json:"source_format" example:"docx"`
-	TargetFormat string             `bson:"target_format" json:"target_format" example:"pdf"`
-	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+type CompressDetails struct {
+	FileName       string `bson:"file_name" json:"file_name" example:"laporan_besar.pdf"`
+	OriginalSize   int64  `bson:"original_size" json:"original_size" example:"5000000"`
+	CompressedSize int64  `bson:"compressed_size" json:"compressed_size" example:"1500000"`
+	Status         string `bson:"status" json:"status" example:"success"`
 }
 
-type SummaryHistory struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty" example:"65b..."`
-	UserID      primitive.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"`
-	FileName    string             `bson:"file_name" json:"file_name" example:"jurnal.pdf"`
-	SummaryText string             `bson:"summary_text" json:"summary_text" example:"Ringkasan dokumen ini adalah..."`
-	Language    string             `bson:"language" json:"language" example:"id"`
-	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+type ConvertDetails struct {
+	FileName     string `bson:"file_name" json:"file_name" example:"document.docx"`
+	SourceFormat string `bson:"source_format" json:"source_format" example:"docx"`
+	TargetFormat string `bson:"target_format" json:"target_format" example:"pdf"`
 }
 
-// untuk Swagger
-type MergeInput struct {
-	InputFiles []string `json:"input_files" example:"file1.pdf,file2.pdf"`
-	OutputFile string   `json:"output_file" example:"merged_result.pdf"`
-}
-
-type CompressInput struct {
-	FileName       string `json:"file_name" example:"laporan_besar.pdf"`
-	OriginalSize   int64  `json:"original_size" example:"5000000"`
-	CompressedSize int64  `json:"compressed_size" example:"1500000"`
-	Status         string `json:"status" example:"success"`
-}
-
-type ConvertInput struct {
-	FileName     string `json:"file_name" example:"document.docx"`
-	SourceFormat string `json:"source_format" example:"docx"`
-	TargetFormat string `json:"target_format" example:"pdf"`
-}
-
-type SummaryInput struct {
-	FileName    string `json:"file_name" example:"jurnal.pdf"`
-	SummaryText string `json:"summary_text" example:"Ringkasan..."`
-	Language    string `json:"language" example:"id"`
+type SummaryDetails struct {
+	FileName    string `bson:"file_name" json:"file_name" example:"jurnal.pdf"`
+	SummaryText string `bson:"summary_text" json:"summary_text" example:"Ringkasan dokumen ini adalah..."`
+	Language    string `bson:"language" json:"language" example:"id"`
 }
 
 type DeleteHistoryInput struct {
 	ID   string `json:"id" example:"65b123..."`
-	Type string `json:"type" example:"merge"` // merge, compress, convert, summary
+	Type string `json:"type,omitempty" example:"merge"` // opsional, jika diisi harus sama dengan tipe item
 }
 
 // ==========================================
@@ -86,9 +65,38 @@ type HistoryItem struct {
 }
 
 type UnifiedHistoryResponse struct {
-	Status  int           `json:"status" example:"200"`
-	Message string        `json:"message" example:"History retrieved successfully"`
-	History []HistoryItem `json:"history"`
+	Status     int           `json:"status" example:"200"`
+	Message    string        `json:"message" example:"History retrieved successfully"`
+	History    []HistoryItem `json:"history"`
+	NextCursor string        `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyNS0wMS0wMlQwMzowNDowNVoiLCJpZCI6IjY1YiJ9"`
+	HasMore    bool          `json:"has_more" example:"false"`
+}
+
+// StoredFile: metadata file input/output tool yang disimpan sementara di server
+type StoredFile struct {
+	ID          string    `json:"id" example:"65c..."`
+	Name        string    `json:"name" example:"laporan.pdf"`
+	ContentType string    `json:"content_type" example:"application/pdf"`
+	Size        int64     `json:"size" example:"102400"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type StoredFileResponse struct {
+	Message string     `json:"message" example:"File berhasil diunggah"`
+	File    StoredFile `json:"file"`
+}
+
+// RerunResponse: hasil menjalankan ulang aktivitas lama
+type RerunResponse struct {
+	Message    string             `json:"message" example:"Operasi berhasil dijalankan ulang"`
+	ActivityID primitive.ObjectID `json:"activity_id" example:"65d..."`
+	Output     StoredFile         `json:"output"`
+}
+
+// MigrationResult: jumlah dokumen per tipe yang dipindahkan ke activity_log
+type MigrationResult struct {
+	Message  string           `json:"message" example:"Migrasi selesai"`
+	Migrated map[string]int64 `json:"migrated"`
 }
 
 type HistoryActionResponse struct {
@@ -16,6 +16,23 @@ type Notification struct {
 	IsRead    bool               `bson:"is_read" json:"is_read"`
 	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
 	FileName  string             `bson:"file_name,omitempty" json:"file_name"`
+	Code      string             `bson:"code,omitempty" json:"code,omitempty"` // kode template pesan
+	// Diisi untuk notifikasi hasil pengumuman admin
+	AnnouncementID primitive.ObjectID `bson:"announcement_id,omitempty" json:"announcement_id,omitempty"`
+	Title          string             `bson:"title,omitempty" json:"title,omitempty"`
+	Link           string             `bson:"link,omitempty" json:"link,omitempty"`
+	// Status pengiriman per channel (in_app, email, whatsapp)
+	Deliveries []NotificationDelivery `bson:"deliveries,omitempty" json:"deliveries,omitempty"`
+}
+
+// NotificationDelivery adalah status pengiriman satu notifikasi lewat satu channel
+type NotificationDelivery struct {
+	Channel     string    `bson:"channel" json:"channel" example:"email"`
+	Status      string    `bson:"status" json:"status" example:"sent"` // sent, queued, digest, retrying, sending, failed, skipped
+	Attempts    int       `bson:"attempts" json:"attempts" example:"1"`
+	LastError   string    `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	NextAttempt time.Time `bson:"next_attempt,omitempty" json:"next_attempt,omitempty"`
+	SentAt      time.Time `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
 }
 
 // NotificationRequest is the request body for creating a notification
@@ -24,6 +41,9 @@ type NotificationRequest struct {
 	Message  string `json:"message" example:"File PDF berhasil digabungkan"`
 	Icon     string `json:"icon" example:"check-circle"`
 	FileName string `json:"file_name" example:"laporan_final.pdf"`
+	// Code template pesan (mis. notif_merge); jika diisi, Message dibuat dari katalog sesuai bahasa user
+	Code   string            `json:"code,omitempty" example:"notif_merge"`
+	Params map[string]string `json:"params,omitempty"`
 }
 
 // NotificationResponse is the response for getting notifications
@@ -31,6 +51,15 @@ type NotificationResponse struct {
 	Status        int            `json:"status" example:"200"`
 	Message       string         `json:"message" example:"Notifications retrieved successfully"`
 	Notifications []Notification `json:"notifications"`
+	NextCursor    string         `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyNC0wMS0wMVQwMDowMDowMFoiLCJpZCI6IjY1YjQuLi4ifQ"`
+	HasMore       bool           `json:"has_more" example:"false"`
+}
+
+// UnreadCountResponse adalah jumlah notifikasi yang belum dibaca
+type UnreadCountResponse struct {
+	Status  int    `json:"status" example:"200"`
+	Message string `json:"message" example:"Unread count retrieved successfully"`
+	Unread  int64  `json:"unread" example:"3"`
 }
 
 // Untuk Swagger
@@ -39,3 +68,50 @@ type NotificationActionResponse struct {
 	Message string `json:"message" example:"Berhasil"`
 	ID      string `json:"id,omitempty" example:"65b4..."`
 }
+
+// NotificationPreferences adalah pilihan channel notifikasi milik satu user
+type NotificationPreferences struct {
+	UserID primitive.ObjectID `bson:"user_id" json:"-"`
+	// Channels memetakan tipe notifikasi ke channel yang aktif; key "*" berlaku untuk tipe yang tidak disebut
+	Channels       map[string][]string `bson:"channels" json:"channels"`
+	WhatsAppNumber string              `bson:"whatsapp_number,omitempty" json:"whatsapp_number,omitempty" example:"6281234567890"`
+	Timezone       string              `bson:"timezone,omitempty" json:"timezone,omitempty" example:"Asia/Jakarta"`
+	QuietHours     QuietHours          `bson:"quiet_hours" json:"quiet_hours"`
+	Digest         DigestSetting       `bson:"digest" json:"digest"`
+	UpdatedAt      time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// QuietHours menahan pengiriman email/WhatsApp di rentang jam tertentu (boleh melewati tengah malam)
+type QuietHours struct {
+	Enabled bool   `bson:"enabled" json:"enabled"`
+	Start   string `bson:"start" json:"start" example:"22:00"`
+	End     string `bson:"end" json:"end" example:"07:00"`
+}
+
+// DigestSetting mengumpulkan notifikasi email/WhatsApp menjadi satu ringkasan harian
+type DigestSetting struct {
+	Enabled    bool      `bson:"enabled" json:"enabled"`
+	Hour       int       `bson:"hour" json:"hour" example:"8"`
+	LastSentAt time.Time `bson:"last_sent_at,omitempty" json:"last_sent_at,omitempty"`
+	// ClaimedUntil adalah lease dispatch yang sedang mengirim ringkasan, agar dua dispatch tidak mengirim dua kali
+	ClaimedUntil time.Time `bson:"claimed_until,omitempty" json:"-"`
+}
+
+type NotificationPreferencesResponse struct {
+	Status      int                     `json:"status" example:"200"`
+	Message     string                  `json:"message" example:"Preferences retrieved successfully"`
+	Preferences NotificationPreferences `json:"preferences"`
+}
+
+// DispatchResult adalah ringkasan satu kali jalan job pengiriman notifikasi
+type DispatchResult struct {
+	Status    int    `json:"status" example:"200"`
+	Message   string `json:"message" example:"Dispatch selesai"`
+	Attempted int    `json:"attempted" example:"4"`
+	Sent      int    `json:"sent" example:"3"`
+	Failed    int    `json:"failed" example:"1"`
+	Digests   int    `json:"digests" example:"2"`
+	// Pengumuman terjadwal yang diterbitkan dan yang kedaluwarsa pada jalan ini
+	Announcements int `json:"announcements" example:"1"`
+	Expired       int `json:"expired" example:"0"`
+}
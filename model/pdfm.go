@@ -10,12 +10,35 @@ type PdfmUsers struct {
 	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Name         string             `bson:"name" json:"name"`
 	Email        string             `bson:"email" json:"email"`
-	Password     string             `bson:"password" json:"password"`
+	Password     string             `bson:"password" json:"password,omitempty"`
 	IsAdmin      bool               `bson:"isAdmin" json:"isAdmin"`
 	IsSupport    bool               `bson:"isSupport" json:"isSupport"`
-	ProfilePhoto string             `bson:"profilePhoto,omitempty" json:"profilePhoto,omitempty"`
+	IsVerified   bool               `bson:"isVerified,omitempty" json:"isVerified"`               // email sudah terverifikasi
+	ProfilePhoto string             `bson:"profilePhoto,omitempty" json:"profilePhoto,omitempty"` // data URL lama, dimigrasi ke Photo
+	Photo        []PhotoVariant     `bson:"photo,omitempty" json:"photo,omitempty"`
+	Language     string             `bson:"language,omitempty" json:"language,omitempty"` // id atau en
 	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
+	// Suspended memblokir login tanpa menghapus data; DeletedAt terisi berarti user dihapus (soft delete)
+	Suspended     bool               `bson:"suspended,omitempty" json:"suspended,omitempty"`
+	SuspendedAt   time.Time          `bson:"suspendedAt,omitempty" json:"suspendedAt,omitempty"`
+	SuspendReason string             `bson:"suspendReason,omitempty" json:"suspendReason,omitempty"`
+	DeletedAt     time.Time          `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy     primitive.ObjectID `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
+	// Permintaan hapus akun oleh user sendiri; data dihapus permanen setelah DeletionScheduledAt
+	DeletionRequestedAt time.Time `bson:"deletionRequestedAt,omitempty" json:"deletionRequestedAt,omitempty"`
+	DeletionScheduledAt time.Time `bson:"deletionScheduledAt,omitempty" json:"deletionScheduledAt,omitempty"`
+	// GoogleSub adalah ID akun Google yang ditautkan lewat /pdfm/login/google
+	GoogleSub      string    `bson:"googleSub,omitempty" json:"-"`
+	GoogleLinkedAt time.Time `bson:"googleLinkedAt,omitempty" json:"googleLinkedAt,omitempty"`
+}
+
+// PhotoVariant adalah referensi satu ukuran avatar yang disimpan di storage backend
+type PhotoVariant struct {
+	Size   int    `bson:"size" json:"size" example:"256"`
+	FileID string `bson:"file_id" json:"-"`
+	ETag   string `bson:"etag" json:"etag" example:"\"9f86d081884c7d65\""`
+	URL    string `bson:"-" json:"url" example:"/pdfm/profile/photo/256"`
 }
 
 type Invoice struct {
@@ -36,6 +59,21 @@ type Feedback struct {
 	Email     string             `bson:"email" json:"email"`
 	Message   string             `bson:"message" json:"message"`
 	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	// Ticketing: category bug/feature/billing/other, priority low/normal/high/urgent,
+	// status new/in_progress/resolved/closed
+	Category     string               `bson:"category,omitempty" json:"category"`
+	Priority     string               `bson:"priority,omitempty" json:"priority"`
+	Status       string               `bson:"status,omitempty" json:"status"`
+	AssignedTo   primitive.ObjectID   `bson:"assigned_to,omitempty" json:"assigned_to,omitempty"`
+	AssigneeName string               `bson:"assignee_name,omitempty" json:"assignee_name,omitempty"`
+	Replies      []FeedbackReply      `bson:"replies,omitempty" json:"replies"`
+	Attachments  []FeedbackAttachment `bson:"attachments,omitempty" json:"attachments,omitempty"`
+	Triage       *FeedbackTriage      `bson:"triage,omitempty" json:"triage,omitempty"`
+	// Anonymous: dikirim lewat form kontak tanpa login; SpamScore dari spam scorer form tersebut
+	Anonymous  bool      `bson:"anonymous,omitempty" json:"anonymous"`
+	SpamScore  float64   `bson:"spam_score,omitempty" json:"spam_score,omitempty"`
+	UpdatedAt  time.Time `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+	ResolvedAt time.Time `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
 }
 
 type LoginLog struct {
@@ -46,6 +84,7 @@ type LoginLog struct {
 	IPAddress string             `bson:"ip_address" json:"ip_address"`
 	UserAgent string             `bson:"user_agent" json:"user_agent"`
 	LoginAt   time.Time          `bson:"login_at" json:"login_at"`
+	Method    string             `bson:"method,omitempty" json:"method,omitempty"` // password atau google
 }
 
 type Token struct {
@@ -87,13 +126,14 @@ type DeleteUserInput struct {
 }
 
 type UploadProfilePhotoInput struct {
-    ProfilePhoto string `json:"profilePhoto" example:"data:image/png;base64,iVBORw0KGgo..."`
+    ProfilePhoto string `json:"profilePhoto" example:"data:image/png;base64,iVBORw0KGgo..."` // atau kirim multipart field "photo"
 }
 
 type FeedbackInput struct {
     Name    string `json:"name" example:"Budi"`
     Email   string `json:"email" example:"budi@gmail.com"`
     Message string `json:"message" example:"Aplikasi ini sangat mantap!"`
+    Category string `json:"category,omitempty" example:"feature"`
 
 }
 
@@ -103,15 +143,31 @@ type FeedbackInput struct {
 
 // ResponseMessage: Untuk response sederhana cuma pesan doang
 type ResponseMessage struct {
+	Code    string `json:"code,omitempty" example:"register_success"`
 	Message string `json:"message" example:"Berhasil"`
 }
 
+// LanguageInput: body untuk mengubah bahasa pesan & notifikasi user
+type LanguageInput struct {
+	Language string `json:"language" example:"en"`
+}
+
 // LoginResponse: Output khusus Login (ada token, nama, dll)
 type LoginResponse struct {
 	Token    string `json:"token" example:"eyJhbGciOiJIUzI1Ni..."`
 	UserName string `json:"userName" example:"Pipo"`
 	IsAdmin  bool   `json:"isAdmin" example:"false"`
 	Message  string `json:"message" example:"Login berhasil"`
+	Language string `json:"language,omitempty" example:"id"`
+	Created  bool   `json:"created,omitempty" example:"false"` // akun baru dibuat lewat login Google
+	Linked   bool   `json:"linked,omitempty" example:"false"`  // akun lama baru saja ditautkan ke Google
+}
+
+// GoogleLoginInput berisi ID token dari Google Identity Services. Password hanya diperlukan untuk
+// menautkan Google ke akun email/password yang sudah ada.
+type GoogleLoginInput struct {
+	IDToken  string `json:"id_token" example:"eyJhbGciOiJSUzI1NiIs..."`
+	Password string `json:"password,omitempty" example:"rahasia123"`
 }
 
 type FeedbackResponse struct {
@@ -129,6 +185,7 @@ type PaymentResponse struct {
 
 // ProfilePhotoResponse: Output setelah upload foto
 type ProfilePhotoResponse struct {
-	Message      string `json:"message,omitempty" example:"Profile photo updated successfully"`
-	ProfilePhoto string `json:"profilePhoto,omitempty" example:"base64string..."`
+	Message      string         `json:"message,omitempty" example:"Profile photo updated successfully"`
+	ProfilePhoto string         `json:"profilePhoto,omitempty" example:"data:image/jpeg;base64,..."` // avatar 256px, untuk client lama
+	Photos       []PhotoVariant `json:"photos,omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// RetentionItem adalah hasil purge satu koleksi untuk satu plan
+type RetentionItem struct {
+	Collection string    `json:"collection" example:"activity_log"`
+	Plan       string    `json:"plan,omitempty" example:"free"`
+	Cutoff     time.Time `json:"cutoff"`
+	Matched    int64     `json:"matched" example:"120"`
+	Deleted    int64     `json:"deleted" example:"0"`
+	Anonymized int64     `json:"anonymized,omitempty" example:"0"` // hanya untuk penghapusan akun
+	Error      string    `json:"error,omitempty"`
+}
+
+// RetentionReport adalah laporan (atau hasil) satu kali jalan retention purger
+type RetentionReport struct {
+	DryRun      bool            `json:"dry_run" example:"true"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	Supporters  int             `json:"supporters" example:"12"`
+	Items       []RetentionItem `json:"items"`
+}
+
+type RetentionReportResponse struct {
+	Status  int             `json:"status" example:"200"`
+	Message string          `json:"message" example:"Laporan retensi (dry run)"`
+	Report  RetentionReport `json:"report"`
+}
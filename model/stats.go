@@ -0,0 +1,36 @@
+package model
+
+// UsageStats adalah ringkasan pemakaian tool PDF, untuk satu user atau seluruh sistem (admin)
+type UsageStats struct {
+	Scope           string           `json:"scope" example:"user"` // user atau system
+	TotalOperations int64            `json:"total_operations" example:"42"`
+	PerType         map[string]int64 `json:"per_type"`
+	Compression     CompressionStats `json:"compression"`
+	TopConversions  []ConversionPair `json:"top_conversions"`
+	// Heatmap[weekday][jam] dalam zona Asia/Jakarta, weekday 0 = Minggu
+	Heatmap       [7][24]int64 `json:"heatmap"`
+	Timezone      string       `json:"timezone" example:"Asia/Jakarta"`
+	ActiveDays    int          `json:"active_days" example:"12"`
+	CurrentStreak int          `json:"current_streak" example:"3"`
+	LongestStreak int          `json:"longest_streak" example:"7"`
+	ActiveUsers   int64        `json:"active_users,omitempty" example:"150"` // hanya untuk scope system
+}
+
+type CompressionStats struct {
+	Files           int64 `json:"files" example:"10"`
+	OriginalBytes   int64 `json:"original_bytes" example:"50000000"`
+	CompressedBytes int64 `json:"compressed_bytes" example:"15000000"`
+	BytesSaved      int64 `json:"bytes_saved" example:"35000000"`
+}
+
+type ConversionPair struct {
+	SourceFormat string `json:"source_format" example:"docx"`
+	TargetFormat string `json:"target_format" example:"pdf"`
+	Count        int64  `json:"count" example:"5"`
+}
+
+type UsageStatsResponse struct {
+	Status  int        `json:"status" example:"200"`
+	Message string     `json:"message" example:"Stats retrieved successfully"`
+	Stats   UsageStats `json:"stats"`
+}
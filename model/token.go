@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RevokedToken adalah token PASETO yang dicabut sebelum kedaluwarsa. Dokumen dihapus otomatis
+// (TTL) setelah ExpiresAt karena token-nya sudah tidak berlaku.
+type RevokedToken struct {
+	Jti       string             `bson:"jti" json:"jti"`
+	Subject   string             `bson:"subject,omitempty" json:"subject,omitempty"`
+	Reason    string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+	RevokedAt time.Time          `bson:"revoked_at" json:"revoked_at"`
+	RevokedBy primitive.ObjectID `bson:"revoked_by,omitempty" json:"revoked_by,omitempty"`
+}
+
+// RevokeTokenInput berisi token yang dicabut, atau jti-nya saja jika token tidak tersedia
+type RevokeTokenInput struct {
+	Token     string    `json:"token,omitempty" example:"v4.public.eyJ..."`
+	Jti       string    `json:"jti,omitempty" example:"9f1c2b..."`
+	ExpiresAt time.Time `json:"expires_at,omitempty"` // hanya dipakai bersama jti
+	Reason    string    `json:"reason,omitempty" example:"Perangkat hilang"`
+}
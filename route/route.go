@@ -2,305 +2,278 @@ package route
 
 import (
 	"net/http"
-	"strings"
 
 	"github.com/gocroot/config"
 	"github.com/gocroot/controller"
-	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/router"
 
-	httpSwagger "github.com/swaggo/http-swagger"
 	_ "github.com/gocroot/docs"
+	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+// Router berisi semua route aplikasi, disusun sekali saat package dimuat
+var Router = newRouter()
+
+// URL adalah entrypoint HTTP untuk Cloud Functions (WebHook) dan server lokal
 func URL(w http.ResponseWriter, r *http.Request) {
-	if config.SetAccessControlHeaders(w, r) {
-		return // If it's a preflight request, return early.
-	}
-	config.SetEnv()
+	Router.ServeHTTP(w, r)
+}
+
+// monitoringPaths tidak membutuhkan profil WhatsAuth sehingga dilewati loadEnv; /readyz
+// memeriksa profil sendiri
+var monitoringPaths = map[string]bool{"/healthz": true, "/readyz": true, "/metrics": true}
+
+// loadEnv memastikan profil WhatsAuth sudah dimuat. Profil di-cache dan dimuat ulang di latar
+// belakang setelah config.ProfileTTL, bukan dibaca dari database di setiap request.
+func loadEnv(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !monitoringPaths[r.URL.Path] {
+			config.RefreshEnv()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func newRouter() *router.Router {
+	rt := router.New(
+		router.RequestID,
+		router.Logger,
+		router.Metrics,
+		router.Recover(http.HandlerFunc(controller.InternalError)),
+		controller.CORS,
+		loadEnv,
+		router.MaxBodySize(config.MaxRequestSize),
+	)
+	rt.NotFound = http.HandlerFunc(controller.NotFound)
+	rt.MethodNotAllowed = http.HandlerFunc(controller.MethodNotAllowed)
+
+	rt.GET("/swagger/*", httpSwagger.WrapHandler)
+	rt.GET("/", controller.GetHome)
+	rt.GET("/metrics", controller.GetMetrics)
+	rt.GET("/healthz", controller.GetHealthz)
+	rt.GET("/readyz", controller.GetReadyz)
+
+	legacyRoutes(rt)
+	pdfmRoutes(rt)
 
-	var method, path string = r.Method, r.URL.Path
-	switch {
-	
-	case strings.HasPrefix(path, "/swagger/"):
-		httpSwagger.WrapHandler(w, r)
-		return
+	// Google Auth
+	rt.POST("/auth/users", controller.Auth)
+	rt.POST("/auth/login", controller.GeneratePasswordHandler)
+	rt.POST("/auth/verify", controller.VerifyPasswordHandler)
+	rt.POST("/auth/resend", controller.ResendPasswordHandler)
 
-	case method == "GET" && path == "/":
-		controller.GetHome(w, r)
+	return rt
+}
 
+// legacyRoutes adalah endpoint WhatsAuth, helpdesk, LMS dan proyek buku. Autentikasinya (header
+// Login) masih ditangani di masing-masing handler.
+func legacyRoutes(rt *router.Router) {
 	//chat bot inbox
-	case method == "POST" && at.URLParam(path, "/webhook/nomor/:nomorwa"):
-		controller.PostInboxNomor(w, r)
+	rt.POST("/webhook/nomor/:nomorwa", controller.PostInboxNomor)
 
 	//masking list nmor official
-	case method == "GET" && path == "/data/phone/all":
-		controller.GetBotList(w, r)
+	rt.GET("/data/phone/all", controller.GetBotList)
 
 	//akses data helpdesk layanan user
-	case method == "GET" && path == "/data/user/helpdesk/all":
-		controller.GetHelpdeskAll(w, r)
-	case method == "GET" && path == "/data/user/helpdesk/masuk":
-		controller.GetLatestHelpdeskMasuk(w, r)
-	case method == "GET" && path == "/data/user/helpdesk/selesai":
-		controller.GetLatestHelpdeskSelesai(w, r)
+	rt.GET("/data/user/helpdesk/all", controller.GetHelpdeskAll)
+	rt.GET("/data/user/helpdesk/masuk", controller.GetLatestHelpdeskMasuk)
+	rt.GET("/data/user/helpdesk/selesai", controller.GetLatestHelpdeskSelesai)
 
 	//pamong desa data from api
-	case method == "GET" && path == "/data/lms/user":
-		controller.GetDataUserFromApi(w, r)
-
+	rt.GET("/data/lms/user", controller.GetDataUserFromApi)
 	//simpan testimoni dari pamong desa lms api
-	case method == "POST" && path == "/data/lms/testi":
-		controller.PostTestimoni(w, r)
-
-		//get random 4 testi
-	case method == "GET" && path == "/data/lms/random/testi":
-		controller.GetRandomTesti4(w, r)
+	rt.POST("/data/lms/testi", controller.PostTestimoni)
+	//get random 4 testi
+	rt.GET("/data/lms/random/testi", controller.GetRandomTesti4)
 
 	//mendapatkan data sent item
-	case method == "GET" && at.URLParam(path, "/data/peserta/sent/:id"):
-		controller.GetSentItem(w, r)
-
+	rt.GET("/data/peserta/sent/:id", controller.GetSentItem)
 	//simpan feedback unsubs user
-	case method == "POST" && path == "/data/peserta/unsubscribe":
-		controller.PostUnsubscribe(w, r)
+	rt.POST("/data/peserta/unsubscribe", controller.PostUnsubscribe)
 
 	//generate token linked device
-	case method == "PUT" && path == "/data/user":
-		controller.PutTokenDataUser(w, r)
-
+	rt.PUT("/data/user", controller.PutTokenDataUser)
 	//Menambhahkan data nomor sender untuk broadcast
-	case method == "PUT" && path == "/data/sender":
-		controller.PutNomorBlast(w, r)
-
+	rt.PUT("/data/sender", controller.PutNomorBlast)
 	//mendapatkan data list nomor sender untuk broadcast
-	case method == "GET" && path == "/data/sender":
-		controller.GetDataSenders(w, r)
-
+	rt.GET("/data/sender", controller.GetDataSenders)
 	//mendapatkan data list nomor sender yang kena blokir dari broadcast
-	case method == "GET" && path == "/data/blokir":
-		controller.GetDataSendersTerblokir(w, r)
-
+	rt.GET("/data/blokir", controller.GetDataSendersTerblokir)
 	//mendapatkan data rekap pengiriman wa blast
-	case method == "GET" && path == "/data/rekap":
-		controller.GetRekapBlast(w, r)
-
+	rt.GET("/data/rekap", controller.GetRekapBlast)
 	//mendapatkan data faq
-	case method == "GET" && at.URLParam(path, "/data/faq/:id"):
-		controller.GetFAQ(w, r)
+	rt.GET("/data/faq/:id", controller.GetFAQ)
 
 	//legacy
-	case method == "PUT" && path == "/data/user/task/doing":
-		controller.PutTaskUser(w, r)
-	case method == "GET" && path == "/data/user/task/done":
-		controller.GetTaskDone(w, r)
-	case method == "POST" && path == "/data/user/task/done":
-		controller.PostTaskUser(w, r)
-	case method == "GET" && path == "/data/pushrepo/kemarin":
-		controller.GetYesterdayDistincWAGroup(w, r)
+	rt.PUT("/data/user/task/doing", controller.PutTaskUser)
+	rt.GET("/data/user/task/done", controller.GetTaskDone)
+	rt.POST("/data/user/task/done", controller.PostTaskUser)
+	rt.GET("/data/pushrepo/kemarin", controller.GetYesterdayDistincWAGroup)
 
 	//Helpdesk
 	//mendapatkan data tiket
-	case method == "GET" && at.URLParam(path, "/data/tiket/closed/:id"):
-		controller.GetClosedTicket(w, r)
-
+	rt.GET("/data/tiket/closed/:id", controller.GetClosedTicket)
 	//simpan feedback tiket user
-	case method == "POST" && path == "/data/tiket/rate":
-		controller.PostMasukanTiket(w, r)
-		// order
-	case method == "POST" && at.URLParam(path, "/data/order/:namalapak"):
-		controller.HandleOrder(w, r)
+	rt.POST("/data/tiket/rate", controller.PostMasukanTiket)
+	// order
+	rt.POST("/data/order/:namalapak", controller.HandleOrder)
 
 	//user data
-	case method == "GET" && path == "/data/user":
-		controller.GetDataUser(w, r)
-
+	rt.GET("/data/user", controller.GetDataUser)
 	//user pendaftaran
-	case method == "POST" && path == "/auth/register/users": //mendapatkan email gmail
-		controller.RegisterGmailAuth(w, r)
-	case method == "POST" && path == "/data/user":
-		controller.PostDataUser(w, r)
-	case method == "POST" && path == "/upload/profpic": //upload gambar profile
-		controller.UploadProfilePictureHandler(w, r)
-	case method == "POST" && path == "/data/user/bio":
-		controller.PostDataBioUser(w, r)
-		/* 	case method == "POST" && at.URLParam(path, "/data/user/wa/:nomorwa"):
-		controller.PostDataUserFromWA(w, r) */
+	rt.POST("/auth/register/users", controller.RegisterGmailAuth) //mendapatkan email gmail
+	rt.POST("/data/user", controller.PostDataUser)
+	rt.POST("/upload/profpic", controller.UploadProfilePictureHandler) //upload gambar profile
+	rt.POST("/data/user/bio", controller.PostDataBioUser)
 
 	//data proyek
-	case method == "GET" && path == "/data/proyek":
-		controller.GetDataProject(w, r)
-	case method == "GET" && path == "/data/proyek/approved": //akses untuk manager
-		controller.GetEditorApprovedProject(w, r)
-	case method == "POST" && path == "/data/proyek":
-		controller.PostDataProject(w, r)
-	case method == "PUT" && path == "/data/metadatabuku":
-		controller.PutMetaDataProject(w, r)
-	case method == "PUT" && path == "/data/proyek/publishbuku": //publish buku isbn by manager
-		controller.PutPublishProject(w, r)
-	case method == "PUT" && path == "/data/proyek":
-		controller.PutDataProject(w, r)
-	case method == "DELETE" && path == "/data/proyek":
-		controller.DeleteDataProject(w, r)
-	case method == "GET" && path == "/data/proyek/anggota":
-		controller.GetDataMemberProject(w, r)
-	case method == "GET" && path == "/data/proyek/editor":
-		controller.GetDataEditorProject(w, r)
-	case method == "DELETE" && path == "/data/proyek/anggota":
-		controller.DeleteDataMemberProject(w, r)
-	case method == "POST" && path == "/data/proyek/anggota":
-		controller.PostDataMemberProject(w, r)
-	case method == "POST" && path == "/data/proyek/editor": //set editor oleh owner
-		controller.PostDataEditorProject(w, r)
-	case method == "PUT" && path == "/data/proyek/editor": //set approved oleh editor
-		controller.PUtApprovedEditorProject(w, r)
+	rt.GET("/data/proyek", controller.GetDataProject)
+	rt.GET("/data/proyek/approved", controller.GetEditorApprovedProject) //akses untuk manager
+	rt.POST("/data/proyek", controller.PostDataProject)
+	rt.PUT("/data/metadatabuku", controller.PutMetaDataProject)
+	rt.PUT("/data/proyek/publishbuku", controller.PutPublishProject) //publish buku isbn by manager
+	rt.PUT("/data/proyek", controller.PutDataProject)
+	rt.DELETE("/data/proyek", controller.DeleteDataProject)
+	rt.GET("/data/proyek/anggota", controller.GetDataMemberProject)
+	rt.GET("/data/proyek/editor", controller.GetDataEditorProject)
+	rt.DELETE("/data/proyek/anggota", controller.DeleteDataMemberProject)
+	rt.POST("/data/proyek/anggota", controller.PostDataMemberProject)
+	rt.POST("/data/proyek/editor", controller.PostDataEditorProject)   //set editor oleh owner
+	rt.PUT("/data/proyek/editor", controller.PUtApprovedEditorProject) //set approved oleh editor
 
 	//upload cover,draft,pdf,sampul buku project
-	case method == "POST" && at.URLParam(path, "/upload/coverbuku/:projectid"):
-		controller.UploadCoverBukuWithParamFileHandler(w, r)
-	case method == "POST" && at.URLParam(path, "/upload/draftbuku/:projectid"):
-		controller.UploadDraftBukuWithParamFileHandler(w, r)
-	case method == "POST" && at.URLParam(path, "/upload/draftpdfbuku/:projectid"):
-		controller.UploadDraftBukuPDFWithParamFileHandler(w, r)
-	case method == "POST" && at.URLParam(path, "/upload/sampulpdfbuku/:projectid"):
-		controller.UploadSampulBukuPDFWithParamFileHandler(w, r)
-	case method == "POST" && at.URLParam(path, "/upload/spk/:projectid"):
-		controller.UploadSPKPDFWithParamFileHandler(w, r)
-	case method == "POST" && at.URLParam(path, "/upload/spi/:projectid"):
-		controller.UploadSPIPDFWithParamFileHandler(w, r)
-	case method == "GET" && at.URLParam(path, "/download/draft/:path"): //downoad file draft
-		controller.AksesFileRepoDraft(w, r)
-	case method == "POST" && path == "/data/proyek/katalog": //post blog katalog
-		controller.PostKatalogBuku(w, r)
-	case method == "GET" && at.URLParam(path, "/download/dokped/spk/:namaproject"): //base64 namaproject
-		controller.GetFileDraftSPK(w, r)
-	case method == "GET" && at.URLParam(path, "/download/dokped/spkt/:namaproject"): //base64 namaproject
-		controller.GetFileDraftSPKT(w, r)
-	case method == "GET" && at.URLParam(path, "/download/dokped/spi/:path"): //base64 path sampul
-		controller.GetFileDraftSPI(w, r)
-
-	case method == "POST" && path == "/data/proyek/menu":
-		controller.PostDataMenuProject(w, r)
-	case method == "POST" && path == "/approvebimbingan":
-		controller.ApproveBimbinganbyPoin(w, r)
-	case method == "DELETE" && path == "/data/proyek/menu":
-		controller.DeleteDataMenuProject(w, r)
-	case method == "POST" && path == "/notif/ux/postlaporan":
-		controller.PostLaporan(w, r)
-	case method == "POST" && path == "/notif/ux/postfeedback":
-		controller.PostFeedback(w, r)
-
-	case method == "POST" && path == "/notif/ux/postmeeting":
-		controller.PostMeeting(w, r)
-	case method == "POST" && at.URLParam(path, "/notif/ux/postpresensi/:id"):
-		controller.PostPresensi(w, r)
-	case method == "POST" && at.URLParam(path, "/notif/ux/posttasklists/:id"):
-		controller.PostTaskList(w, r)
-	case method == "POST" && at.URLParam(path, "/webhook/nomor/:nomorwa"):
-		controller.PostInboxNomor(w, r)
+	rt.POST("/upload/coverbuku/:projectid", controller.UploadCoverBukuWithParamFileHandler)
+	rt.POST("/upload/draftbuku/:projectid", controller.UploadDraftBukuWithParamFileHandler)
+	rt.POST("/upload/draftpdfbuku/:projectid", controller.UploadDraftBukuPDFWithParamFileHandler)
+	rt.POST("/upload/sampulpdfbuku/:projectid", controller.UploadSampulBukuPDFWithParamFileHandler)
+	rt.POST("/upload/spk/:projectid", controller.UploadSPKPDFWithParamFileHandler)
+	rt.POST("/upload/spi/:projectid", controller.UploadSPIPDFWithParamFileHandler)
+	rt.GET("/download/draft/:path", controller.AksesFileRepoDraft)            //downoad file draft
+	rt.POST("/data/proyek/katalog", controller.PostKatalogBuku)               //post blog katalog
+	rt.GET("/download/dokped/spk/:namaproject", controller.GetFileDraftSPK)   //base64 namaproject
+	rt.GET("/download/dokped/spkt/:namaproject", controller.GetFileDraftSPKT) //base64 namaproject
+	rt.GET("/download/dokped/spi/:path", controller.GetFileDraftSPI)          //base64 path sampul
+
+	rt.POST("/data/proyek/menu", controller.PostDataMenuProject)
+	rt.POST("/approvebimbingan", controller.ApproveBimbinganbyPoin)
+	rt.DELETE("/data/proyek/menu", controller.DeleteDataMenuProject)
+	rt.POST("/notif/ux/postlaporan", controller.PostLaporan)
+	rt.POST("/notif/ux/postfeedback", controller.PostFeedback)
+	rt.POST("/notif/ux/postmeeting", controller.PostMeeting)
+	rt.POST("/notif/ux/postpresensi/:id", controller.PostPresensi)
+	rt.POST("/notif/ux/posttasklists/:id", controller.PostTaskList)
 
 	// LMS
-	case method == "GET" && path == "/lms/refresh/cookie":
-		controller.RefreshLMSCookie(w, r)
-	case method == "GET" && path == "/lms/count/user":
-		controller.GetCountDocUser(w, r)
+	rt.GET("/lms/refresh/cookie", controller.RefreshLMSCookie)
+	rt.GET("/lms/count/user", controller.GetCountDocUser)
+}
+
+// jsonBodyLimit membatasi body endpoint pdfm yang hanya menerima JSON kecil
+const jsonBodyLimit = 1 << 20
+
+// pdfmRoutes adalah endpoint aplikasi pdfm. Route di grup user dan admin sudah diautentikasi
+// middleware; endpoint admin yang juga dipanggil Cloud Scheduler (X-Cron-Secret) memeriksa aksesnya
+// sendiri sehingga didaftarkan di luar grup admin. Hanya route dengan controller.RequireScope yang
+// menerima personal API key, dengan scope yang tertulis di route tersebut.
+func pdfmRoutes(rt *router.Router) {
+	jsonBody := router.MaxBodySize(jsonBodyLimit)
+	pdfm := rt.Group("/pdfm")
+	user := pdfm.Group("", controller.RequireUser)
+	admin := pdfm.Group("/admin", controller.RequireAdmin)
 
-	//PDFM
 	//Profile Photo
-	case method == "POST" && path == "/pdfm/profile/photo":
-		controller.UploadProfilePhotoHandler(w, r)
-	case method == "GET" && path == "/pdfm/profile/photo":
-		controller.GetProfilePhotoHandler(w, r)
-
-	//Register
-	case method == "POST" && path == "/pdfm/register":
-		controller.RegisterHandler(w, r)
-	//Login
-	case method == "POST" && path == "/pdfm/login":
-		controller.GetUser(w, r)
-	//Logout
-	case method == "POST" && path == "/pdfm/logout":
-		controller.LogoutHandler(w, r)
+	user.POST("/profile/photo", controller.UploadProfilePhotoHandler)
+	user.GET("/profile/photo", controller.GetProfilePhotoHandler)
+	user.GET("/profile/photo/:size", controller.GetProfilePhotoSize)
 
-	//PaymentHandler
-	case method == "POST" && path == "/pdfm/payment":
-		controller.ConfirmPaymentHandler(w, r)
+	//Register, Login, Logout
+	pdfm.POST("/register", controller.RegisterHandler, jsonBody)
+	pdfm.POST("/login", controller.GetUser, jsonBody)
+	pdfm.POST("/login/google", controller.LoginGoogle, jsonBody)
+	pdfm.POST("/logout", controller.LogoutHandler)
 
+	//PaymentHandler
+	pdfm.POST("/payment", controller.ConfirmPaymentHandler, jsonBody)
 	//Get InvoiceHandler
-	case method == "GET" && path == "/pdfm/invoices":
-		controller.GetInvoicesHandler(w, r)
-
-	//CRUD
-	case method == "GET" && path == "/pdfm/get/users":
-		controller.GetUsers(w, r)
-	case method == "POST" && path == "/pdfm/create/users":
-		controller.CreateUser(w, r)
-	case method == "GET" && path == "/pdfm/getone/users":
-		controller.GetOneUser(w, r)
-	case method == "GET" && path == "/pdfm/getoneadmin/users":
-		controller.GetOneUserAdmin(w, r)
-	case method == "PUT" && path == "/pdfm/update/users":
-		controller.UpdateUser(w, r)
-	case method == "DELETE" && path == "/pdfm/delete/users":
-		controller.DeleteUser(w, r)
+	user.GET("/invoices", controller.GetInvoicesHandler)
+
+	//CRUD (route lama, khusus admin)
+	pdfm.GET("/get/users", controller.GetUsers, controller.RequireAdmin)
+	pdfm.POST("/create/users", controller.CreateUser, controller.RequireAdmin, jsonBody)
+	user.GET("/getone/users", controller.GetOneUser)
+	pdfm.GET("/getoneadmin/users", controller.GetOneUserAdmin, controller.RequireAdmin)
+	pdfm.PUT("/update/users", controller.UpdateUser, controller.RequireAdmin, jsonBody)
+	pdfm.DELETE("/delete/users", controller.DeleteUser, controller.RequireAdmin)
+	user.POST("/account/export", controller.ExportAccount)
+	user.DELETE("/account", controller.RequestAccountDeletion)
+	user.POST("/account/deletion/cancel", controller.CancelAccountDeletion)
+	pdfm.POST("/admin/accounts/purge", controller.PurgeDeletedAccounts)
+	admin.GET("/users", controller.ListUsersAdmin)
+	admin.POST("/users/:id/restore", controller.RestoreUserAdmin)
+	admin.POST("/users/:id/suspend", controller.SuspendUserAdmin)
+	admin.POST("/users/:id/unsuspend", controller.UnsuspendUserAdmin)
+	admin.GET("/users/:id", controller.GetUserAdmin)
+	admin.DELETE("/users/:id", controller.DeleteUserAdmin)
+
+	admin.POST("/tokens/revoke", controller.RevokeWatoken)
+	admin.GET("/audit", controller.GetAuditLog)
+
+	//API keys
+	user.POST("/apikeys", controller.CreateAPIKey)
+	user.GET("/apikeys", controller.ListAPIKeys)
+	user.GET("/apikeys/:id/usage", controller.GetAPIKeyUsage)
+	user.DELETE("/apikeys/:id", controller.RevokeAPIKey)
 
 	//Notifications
-	case method == "GET" && path == "/pdfm/notifications":
-		controller.GetNotifications(w, r)
-	case method == "POST" && path == "/pdfm/notifications":
-		controller.AddNotification(w, r)
-	case method == "PUT" && path == "/pdfm/notifications/read":
-		controller.MarkAllAsRead(w, r)
-	case method == "DELETE" && path == "/pdfm/notifications":
-		controller.ClearNotifications(w, r)
-
-		// 1. Merge Logs
-	case method == "POST" && path == "/pdfm/log/merge":
-		controller.CreateMergeHistory(w, r)
-	case method == "GET" && path == "/pdfm/log/merge":
-		controller.GetMergeHistory(w, r)
-
-	// 2. Compress Logs
-	case method == "POST" && path == "/pdfm/log/compress":
-		controller.CreateCompressHistory(w, r)
-	case method == "GET" && path == "/pdfm/log/compress":
-		controller.GetCompressHistory(w, r)
-
-	// 3. Convert Logs
-	case method == "POST" && path == "/pdfm/log/convert":
-		controller.CreateConvertHistory(w, r)
-	case method == "GET" && path == "/pdfm/log/convert":
-		controller.GetConvertHistory(w, r)
-
-	// 4. Summary Logs
-	case method == "POST" && path == "/pdfm/log/summary":
-		controller.CreateSummaryHistory(w, r)
-	case method == "GET" && path == "/pdfm/log/summary":
-		controller.GetSummaryHistory(w, r)
-
-	// 5. All History (Combined)
-	case method == "GET" && path == "/pdfm/history/all":
-		controller.GetAllHistory(w, r)
-	case method == "DELETE" && path == "/pdfm/history/delete":
-		controller.DeleteHistory(w, r)
-
-		// Feedback (Kotak Saran / Contact Us)
-	case method == "POST" && path == "/pdfm/feedback":
-		controller.InsertFeedback(w, r)
-	case method == "GET" && path == "/pdfm/feedback":
-		controller.GetAllFeedback(w, r)
-
-	// Google Auth
-	case method == "POST" && path == "/auth/users":
-		controller.Auth(w, r)
-	case method == "POST" && path == "/auth/login":
-		controller.GeneratePasswordHandler(w, r)
-	case method == "POST" && path == "/auth/verify":
-		controller.VerifyPasswordHandler(w, r)
-	case method == "POST" && path == "/auth/resend":
-		controller.ResendPasswordHandler(w, r)
-
-	// Google Auth
-	default:
-		controller.NotFound(w, r)
-	}
+	user.PUT("/profile/language", controller.UpdateLanguage)
+	// EventSource tidak bisa mengirim header Authorization, token dibaca handler dari query
+	pdfm.GET("/notifications/stream", controller.StreamNotifications)
+	user.GET("/notifications/unread-count", controller.GetUnreadCount)
+	user.GET("/notifications/preferences", controller.GetNotificationPreferences)
+	user.PUT("/notifications/preferences", controller.UpdateNotificationPreferences)
+	pdfm.POST("/admin/notifications/dispatch", controller.DispatchNotifications)
+	admin.POST("/announcements", controller.CreateAnnouncement)
+	admin.GET("/announcements", controller.GetAnnouncements)
+	admin.DELETE("/announcements/:id", controller.WithdrawAnnouncement)
+	user.PUT("/notifications/:id/read", controller.MarkNotificationRead)
+	user.DELETE("/notifications/:id", controller.DeleteNotification)
+	user.GET("/notifications", controller.GetNotifications)
+	user.POST("/notifications", controller.AddNotification)
+	user.PUT("/notifications/read", controller.MarkAllAsRead)
+	user.DELETE("/notifications", controller.ClearNotifications)
+
+	// History per tool (merge, compress, convert, summary) -> activity_log
+	pdfm.POST("/log/:type", controller.CreateActivity, controller.RequireScope(":type"))
+	pdfm.GET("/log/:type", controller.GetActivities, controller.RequireScope(":type"))
+
+	// All History (Combined)
+	pdfm.GET("/history/all", controller.GetAllHistory, controller.RequireScope("history"))
+	user.DELETE("/history/delete", controller.DeleteHistory)
+	pdfm.GET("/history/export", controller.ExportHistory, controller.RequireScope("history"))
+	// Rerun juga memeriksa scope tipe tool di RerunHistory
+	pdfm.POST("/history/:id/rerun", controller.RerunHistory, controller.RequireScope("history"))
+
+	// File input/output tool (retensi sementara untuk rerun)
+	pdfm.POST("/files", controller.UploadToolFile, controller.RequireScope("files"))
+	pdfm.GET("/files/:id", controller.DownloadToolFile, controller.RequireScope("files"))
+	admin.POST("/activity/migrate", controller.MigrateHistory)
+
+	// Usage Stats
+	user.GET("/stats", controller.GetMyStats)
+	admin.GET("/stats", controller.GetSystemStats)
+
+	// Retensi data
+	admin.GET("/retention", controller.GetRetentionReport)
+	pdfm.POST("/admin/retention/purge", controller.PurgeRetention)
+
+	// Feedback (Kotak Saran / Contact Us)
+	pdfm.POST("/contact", controller.SubmitContact)
+	user.POST("/feedback", controller.InsertFeedback)
+	pdfm.GET("/feedback", controller.GetAllFeedback, controller.RequireAdmin)
+	pdfm.POST("/admin/feedback/analyze", controller.AnalyzeFeedback)
+	user.GET("/feedback/mine", controller.GetMyFeedback)
+	user.POST("/feedback/:id/replies", controller.ReplyFeedback)
+	user.GET("/feedback/:id/attachments/:file", controller.DownloadFeedbackAttachment)
+	user.GET("/feedback/:id", controller.GetFeedbackThread)
+	user.PUT("/feedback/:id", controller.UpdateFeedback)
 }
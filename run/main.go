@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/gocroot/controller"
 	"github.com/gocroot/route"
-
-	httpSwagger "github.com/swaggo/http-swagger"
-	_ "github.com/gocroot/docs"
 )
 
 // @title PDF Merger API
@@ -26,7 +31,44 @@ import (
 // @schemes https
 
 func main() {
-	http.HandleFunc("/", route.URL)
-	http.HandleFunc("/swagger/", httpSwagger.WrapHandler)
-	http.ListenAndServe(":8080", nil)
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           http.HandlerFunc(route.URL),
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+
+	// Saat SIGTERM, /readyz langsung 503 agar load balancer berhenti mengirim trafik, lalu request
+	// yang sedang berjalan diberi waktu selesai
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	// done ditutup setelah Shutdown selesai menunggu request yang sedang berjalan
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-ctx.Done()
+		controller.Drain()
+		time.Sleep(drainDelay)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			slog.Error("shutdown server gagal", "error", err)
+		}
+	}()
+
+	slog.Info("server berjalan", "addr", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("server berhenti", "error", err)
+		os.Exit(1)
+	}
+	// ListenAndServe langsung kembali begitu Shutdown dipanggil, jadi tunggu sampai draining selesai
+	<-done
+	slog.Info("server berhenti")
 }
+
+// drainDelay memberi waktu load balancer membaca /readyz yang gagal sebelum listener ditutup
+const drainDelay = 5 * time.Second